@@ -2,12 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"net/http"
+	_ "net/http/pprof"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"weather-platform/internal/config"
+	"weather-platform/internal/models"
 	"weather-platform/internal/repository"
 	"weather-platform/internal/services"
 	"weather-platform/pkg/database"
@@ -20,8 +29,138 @@ func main() {
 	dataDir := flag.String("data-dir", "./wx_data", "Directory containing weather data files")
 	batchSize := flag.Int("batch-size", 1000, "Number of records to process in each batch")
 	calculateStats := flag.Bool("calculate-stats", false, "Calculate statistics after ingestion")
+	persistFailures := flag.Bool("persist-failures", false, "Persist parse/conversion failures to the failed_records table")
+	batchTimeout := flag.Duration("batch-timeout", 0, "Flush a partial batch after this interval (0 disables, flush only when full)")
+	workers := flag.Int("workers", 1, "Number of files to ingest concurrently")
+	maxInFlight := flag.Int("max-inflight", 0, "Maximum batches written to the database concurrently; further writers wait for a slot (0 = half of -workers, at least 1)")
+	fromStdin := flag.Bool("stdin", false, "Read records for a single station from stdin instead of -data-dir")
+	stationID := flag.String("station-id", "", "Station ID for records read from stdin (required with -stdin)")
+	delimiter := flag.String("delimiter", ",", "Field delimiter for .csv files (single character, or \\t for tab)")
+	skipHeader := flag.Bool("skip-header", false, "Skip the first row of each .csv file")
+	conflict := flag.String("conflict", "update", "Handling of existing station/date rows: update (overwrite), ignore (keep original), or error (fail the batch)")
+	pprofAddr := flag.String("pprof-addr", "", "Serve pprof profiling endpoints on this address during ingestion, e.g. localhost:6060 (empty disables)")
+	output := flag.String("output", "text", "Summary format written to stdout: text or json")
+	glob := flag.String("glob", strings.Join(services.DefaultFilePatterns, ","), "Comma-separated glob patterns selecting files in -data-dir, e.g. *.dat,*.tsv")
+	checkOrdering := flag.Bool("check-ordering", false, "Warn about and count rows dated earlier than the preceding row in the same file")
+	fromLine := flag.Int("from-line", 0, "Start ingesting each file at this 1-based line number (0 = first line)")
+	toLine := flag.Int("to-line", 0, "Stop ingesting each file after this line number (0 = end of file)")
+	format := flag.String("format", services.FormatAuto, "Parser for matched files: auto (.csv as CSV, others tab-delimited), tab, or csv")
+	tempScale := flag.Float64("temp-scale", models.DefaultTempScale, "Divisor converting raw temperatures to °C (10 = tenths of a degree, 1 = whole degrees)")
+	cumulativePrecip := flag.Bool("cumulative-precip", false, "Treat the precipitation column as a running total that may reset and store daily differences (rows must be in date order)")
+	precipScale := flag.Float64("precip-scale", models.DefaultPrecipScale, "Divisor converting raw precipitation to cm (100 = tenths of a mm, 10 = whole mm)")
+	negativePrecip := flag.String("negative-precip", models.NegativePrecipError, "Handling of negative precipitation other than -9999: error (reject the record) or null (store it as missing)")
+	maxErrors := flag.Int("max-errors", 0, "Abort the run once file errors plus failed records exceed this count (0 = unlimited)")
+	encoding := flag.String("encoding", services.EncodingUTF8, "Character encoding of input files: utf-8 or latin1 (a leading UTF-8 byte order mark is always removed)")
+	stationAliasesPath := flag.String("station-aliases", "", "CSV file of alias,canonical station ID rows; aliased files are stored under the canonical ID (empty disables)")
+	intraFileParallelism := flag.Int("intra-file-parallelism", 0, "Split each tab-delimited file of at least -intra-file-min-bytes into this many byte ranges parsed concurrently (0 or 1 disables; ignored with -cumulative-precip or -check-ordering)")
+	intraFileMinBytes := flag.Int64("intra-file-min-bytes", services.DefaultIntraFileMinBytes, "Smallest file size in bytes split by -intra-file-parallelism")
+	coordinate := flag.Bool("coordinate", false, "Claim files through the shared ingestion_file_status table so several ingesters can split -data-dir, resuming partially ingested files")
+	coordinatorID := flag.String("coordinator-id", "", "Name recorded for files this instance claims with -coordinate (empty = host name and PID)")
+	claimTimeout := flag.Duration("claim-timeout", services.DefaultClaimTimeout, "With -coordinate, reclaim files another instance left in progress without updates for this long")
+	retryFailed := flag.Bool("retry-failed", false, "With -coordinate, claim files an earlier run marked failed again, resuming after their last committed line")
+	archive := flag.String("archive", "", "Ingest station files from this .tar.gz archive instead of -data-dir; entries are matched against -glob by base name (empty disables)")
 	flag.Parse()
 
+	if *fromStdin && *stationID == "" {
+		fmt.Fprintln(os.Stderr, "-station-id is required with -stdin")
+		os.Exit(1)
+	}
+
+	if *fromStdin && *coordinate {
+		fmt.Fprintln(os.Stderr, "-coordinate cannot be combined with -stdin")
+		os.Exit(1)
+	}
+
+	if *archive != "" && *fromStdin {
+		fmt.Fprintln(os.Stderr, "-archive cannot be combined with -stdin")
+		os.Exit(1)
+	}
+
+	if *archive != "" && *coordinate {
+		fmt.Fprintln(os.Stderr, "-coordinate cannot be combined with -archive")
+		os.Exit(1)
+	}
+
+	if *retryFailed && !*coordinate {
+		fmt.Fprintln(os.Stderr, "-retry-failed requires -coordinate")
+		os.Exit(1)
+	}
+
+	if *claimTimeout < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid -claim-timeout %s: must not be negative\n", *claimTimeout)
+		os.Exit(1)
+	}
+
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "Invalid -output %q: expected text or json\n", *output)
+		os.Exit(1)
+	}
+
+	conflictStrategy, err := repository.ParseConflictStrategy(*conflict)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -conflict: %v\n", err)
+		os.Exit(1)
+	}
+
+	csvDelimiter, err := parseDelimiter(*delimiter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -delimiter: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *fromLine < 0 || *toLine < 0 || (*toLine > 0 && *toLine < *fromLine) {
+		fmt.Fprintf(os.Stderr, "Invalid line window: -from-line=%d -to-line=%d\n", *fromLine, *toLine)
+		os.Exit(1)
+	}
+
+	inputFormat, err := services.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -format: %v\n", err)
+		os.Exit(1)
+	}
+
+	inputEncoding, err := services.ParseEncoding(*encoding)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -encoding: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *tempScale <= 0 || *precipScale <= 0 {
+		fmt.Fprintf(os.Stderr, "Invalid scale: -temp-scale=%g -precip-scale=%g (must be positive)\n", *tempScale, *precipScale)
+		os.Exit(1)
+	}
+
+	negativePrecipPolicy, err := models.ParseNegativePrecip(*negativePrecip)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -negative-precip: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *maxErrors < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid -max-errors %d: must not be negative\n", *maxErrors)
+		os.Exit(1)
+	}
+
+	if *intraFileParallelism < 0 || *intraFileMinBytes < 1 {
+		fmt.Fprintf(os.Stderr, "Invalid intra-file settings: -intra-file-parallelism=%d -intra-file-min-bytes=%d\n", *intraFileParallelism, *intraFileMinBytes)
+		os.Exit(1)
+	}
+
+	var stationAliases services.StationAliases
+	if *stationAliasesPath != "" {
+		stationAliases, err = services.LoadStationAliases(*stationAliasesPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -station-aliases: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	filePatterns, err := parseGlob(*glob)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -glob: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -29,20 +168,61 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Initialize logger
-	logLevel := logging.InfoLevel
-	if cfg.Logging.Level == "debug" {
-		logLevel = logging.DebugLevel
+	logLevel, err := logging.ParseLogLevel(cfg.Logging.Level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid log level, defaulting to info: %v\n", err)
 	}
 
 	logger := logging.NewStructuredLogger("weather-ingester", "1.0.0", logLevel)
+	if *output == "json" {
+		// Keep stdout reserved for the machine-readable summary
+		logger.SetOutput(os.Stderr)
+	}
+	if err := logger.ApplyComponentLevels(cfg.Logging.ComponentLevels); err != nil {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid component log levels: %v\n", err)
+	}
+	logger.EnableAsync(cfg.Logging.AsyncBuffer)
+	defer logger.Close()
+
+	// Cancel ingestion and statistics calculation on Ctrl-C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Optional profiling listener; net/http/pprof registers on the default mux
+	if *pprofAddr != "" {
+		go func() {
+			logger.Info(ctx, "[INGESTER_PPROF] Serving pprof endpoints", logging.Fields{
+				"address": *pprofAddr,
+			})
+			if err := http.ListenAndServe(*pprofAddr, nil); err != nil {
+				logger.Error(ctx, "[INGESTER_PPROF_ERROR] pprof listener failed", logging.Fields{
+					"address": *pprofAddr,
+				}, err)
+			}
+		}()
+	}
 
-	ctx := context.Background()
 	logger.Info(ctx, "[INGESTER_START] Starting weather data ingestion", logging.Fields{
 		"version":          "1.0.0",
 		"data_dir":         *dataDir,
 		"batch_size":       *batchSize,
 		"calculate_stats":  *calculateStats,
+		"persist_failures": *persistFailures,
+		"batch_timeout":    batchTimeout.String(),
+		"workers":          *workers,
+		"max_inflight":     *maxInFlight,
+		"stdin":            *fromStdin,
+		"archive":          *archive,
+		"glob":             filePatterns,
+		"format":           inputFormat,
+		"encoding":         inputEncoding,
+		"station_aliases":  len(stationAliases),
 	})
 
 	// Initialize metrics collector
@@ -60,37 +240,143 @@ func main() {
 		MaxIdleConns:    cfg.Database.MaxIdleConns,
 		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
 		ConnMaxIdleTime: cfg.Database.ConnMaxIdleTime,
+		MinConns:        cfg.Database.MinConns,
+
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+		FailoverHosts:      cfg.Database.FailoverHosts,
+		QueryComments:      cfg.Database.QueryComments,
+
+		PoolMonitorInterval: cfg.Database.PoolMonitorInterval,
+		PoolWarnUtilization: cfg.Database.PoolWarnUtilization,
+		MaxConcurrentTx:     cfg.Database.MaxConcurrentTx,
+		Options:             cfg.Database.OptionsMap(),
 	}
 
-	db, err := database.NewPostgresDB(dbConfig, logger, metricsCollector)
+	db, err := database.NewPostgresDB(dbConfig, logger.Named("database"), metricsCollector)
 	if err != nil {
 		logger.Fatal(ctx, "[INGESTER_ERROR] Failed to connect to database", logging.Fields{}, err)
 	}
 	defer db.Close()
 
 	// Initialize repository
-	weatherRepo := repository.NewWeatherRepository(db, logger, metricsCollector)
+	weatherRepo := repository.NewWeatherRepository(db, logger.Named("repository"), metricsCollector, repository.NewTables(cfg.Database.TablePrefix))
 
 	// Initialize services
-	ingestionService := services.NewIngestionService(weatherRepo, logger, metricsCollector)
-	statsService := services.NewStatisticsService(weatherRepo, logger, metricsCollector)
+	ingestionService := services.NewIngestionService(weatherRepo, logger.Named("ingestion"), metricsCollector)
+	ingestionService.SetOptions(services.IngestionOptions{
+		PersistFailures:      *persistFailures,
+		BatchTimeout:         *batchTimeout,
+		Workers:              *workers,
+		MaxInFlightBatches:   *maxInFlight,
+		CSVDelimiter:         csvDelimiter,
+		CSVSkipHeader:        *skipHeader,
+		Conflict:             conflictStrategy,
+		FilePatterns:         filePatterns,
+		Format:               inputFormat,
+		CheckOrdering:        *checkOrdering,
+		FromLine:             *fromLine,
+		ToLine:               *toLine,
+		CumulativePrecip:     *cumulativePrecip,
+		MaxErrors:            *maxErrors,
+		StationAliases:       stationAliases,
+		Encoding:             inputEncoding,
+		IntraFileParallelism: *intraFileParallelism,
+		IntraFileMinBytes:    *intraFileMinBytes,
+		Coordinate:           *coordinate,
+		CoordinatorID:        *coordinatorID,
+		ClaimTimeout:         *claimTimeout,
+		RetryFailed:          *retryFailed,
+		Conversion: models.ConversionOptions{
+			TempScale:      *tempScale,
+			PrecipScale:    *precipScale,
+			NegativePrecip: negativePrecipPolicy,
+		},
+	})
+	statsService := services.NewStatisticsService(weatherRepo, logger.Named("statistics"), metricsCollector)
+	statsService.SetOptions(services.StatisticsOptions{
+		MinObservations: cfg.Stats.MinObservationsForStats,
+		// Leave no worker waiting on the connection pool
+		Concurrency: min(cfg.Stats.Concurrency, cfg.Database.MaxOpenConns),
+	})
 
 	// Ingest data
-	result, err := ingestionService.IngestDirectory(ctx, *dataDir, *batchSize)
+	var result *services.IngestionResult
+	if *fromStdin {
+		result, err = ingestStdin(ctx, ingestionService, *stationID, *batchSize)
+	} else if *archive != "" {
+		result, err = ingestionService.IngestArchive(ctx, *archive, *batchSize)
+	} else {
+		result, err = ingestionService.IngestDirectory(ctx, *dataDir, *batchSize)
+	}
 	if err != nil {
 		logger.Fatal(ctx, "[INGESTION_ERROR] Ingestion failed", logging.Fields{
 			"error": err.Error(),
 		}, err)
 	}
 
-	// Print results
+	// Print results after any buffered log lines so they do not interleave
+	logger.Flush()
+	if *output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			logger.Error(ctx, "[INGESTER_OUTPUT_ERROR] Failed to write JSON summary", logging.Fields{}, err)
+		}
+	} else {
+		printSummary(result)
+	}
+
+	// An aborted run exits non-zero and skips statistics over partial data
+	if result.Aborted {
+		logger.Fatal(ctx, "[INGESTION_ABORTED] Ingestion aborted after exceeding -max-errors", logging.Fields{
+			"error_count": result.ErrorCount(),
+			"max_errors":  *maxErrors,
+		}, fmt.Errorf("error count %d exceeded -max-errors %d", result.ErrorCount(), *maxErrors))
+	}
+
+	// Calculate statistics if requested
+	if *calculateStats {
+		textOutput := *output == "text"
+		if textOutput {
+			fmt.Println("\n" + strings.Repeat("=", 80))
+			fmt.Println("CALCULATING STATISTICS")
+			fmt.Println(strings.Repeat("=", 80))
+		}
+
+		if err := statsService.CalculateAllStatistics(ctx); err != nil {
+			logger.Error(ctx, "[STATS_ERROR] Statistics calculation failed", logging.Fields{}, err)
+			if textOutput {
+				fmt.Printf("Statistics calculation failed: %v\n", err)
+			}
+		} else if textOutput {
+			fmt.Println("Statistics calculation completed successfully")
+		}
+	}
+
+	logger.Info(ctx, "[INGESTER_COMPLETE] Ingestion completed successfully", logging.Fields{
+		"total_records":      result.TotalRecords,
+		"successful_records": result.SuccessfulRecords,
+		"failed_records":     result.FailedRecords,
+		"duration_seconds":   result.Duration.Seconds(),
+	})
+}
+
+// printSummary writes the human-readable ingestion summary to stdout
+func printSummary(result *services.IngestionResult) {
 	fmt.Println(strings.Repeat("=", 80))
-	fmt.Println("INGESTION COMPLETE")
+	if result.Aborted {
+		fmt.Println("INGESTION ABORTED (error threshold exceeded)")
+	} else {
+		fmt.Println("INGESTION COMPLETE")
+	}
 	fmt.Println(strings.Repeat("=", 80))
 	fmt.Printf("Total Files:        %d\n", result.TotalFiles)
 	fmt.Printf("Total Records:      %d\n", result.TotalRecords)
 	fmt.Printf("Successful Records: %d\n", result.SuccessfulRecords)
 	fmt.Printf("Failed Records:     %d\n", result.FailedRecords)
+	if result.OutOfOrderRecords > 0 {
+		fmt.Printf("Out-of-Order Rows:  %d\n", result.OutOfOrderRecords)
+	}
 	fmt.Printf("Duration:           %v\n", result.Duration)
 	fmt.Printf("Records/Second:     %.2f\n", float64(result.SuccessfulRecords)/result.Duration.Seconds())
 
@@ -105,25 +391,62 @@ func main() {
 			fmt.Printf("  ... and %d more errors\n", len(result.Errors)-10)
 		}
 	}
+}
 
-	// Calculate statistics if requested
-	if *calculateStats {
-		fmt.Println("\n" + strings.Repeat("=", 80))
-		fmt.Println("CALCULATING STATISTICS")
-		fmt.Println(strings.Repeat("=", 80))
+// ingestStdin ingests a single station's records streamed on stdin
+func ingestStdin(ctx context.Context, ingestionService *services.IngestionService, stationID string, batchSize int) (*services.IngestionResult, error) {
+	startTime := time.Now()
 
-		if err := statsService.CalculateAllStatistics(ctx); err != nil {
-			logger.Error(ctx, "[STATS_ERROR] Statistics calculation failed", logging.Fields{}, err)
-			fmt.Printf("Statistics calculation failed: %v\n", err)
-		} else {
-			fmt.Println("Statistics calculation completed successfully")
+	fileResult, err := ingestionService.IngestReader(ctx, stationID, os.Stdin, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &services.IngestionResult{
+		TotalFiles:        1,
+		TotalRecords:      fileResult.TotalRecords,
+		SuccessfulRecords: fileResult.SuccessfulRecords,
+		FailedRecords:     fileResult.FailedRecords,
+		OutOfOrderRecords: fileResult.OutOfOrderRecords,
+		Duration:          time.Since(startTime),
+		Errors:            make([]string, 0),
+	}, nil
+}
+
+// parseDelimiter converts the -delimiter flag into a single CSV field separator
+func parseDelimiter(value string) (rune, error) {
+	if value == `\t` {
+		return '\t', nil
+	}
+
+	runes := []rune(value)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("expected a single character, got %q", value)
+	}
+	if runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+		return 0, fmt.Errorf("%q cannot be used as a delimiter", value)
+	}
+
+	return runes[0], nil
+}
+
+// parseGlob splits a comma-separated pattern list and rejects malformed patterns
+func parseGlob(value string) ([]string, error) {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%q: %w", pattern, err)
 		}
+		patterns = append(patterns, pattern)
 	}
 
-	logger.Info(ctx, "[INGESTER_COMPLETE] Ingestion completed successfully", logging.Fields{
-		"total_records":      result.TotalRecords,
-		"successful_records": result.SuccessfulRecords,
-		"failed_records":     result.FailedRecords,
-		"duration_seconds":   result.Duration.Seconds(),
-	})
+	if len(patterns) == 0 {
+		return nil, errors.New("at least one pattern is required")
+	}
+
+	return patterns, nil
 }
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestParseDelimiter(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    rune
+		wantErr bool
+	}{
+		{value: ",", want: ','},
+		{value: ";", want: ';'},
+		{value: "|", want: '|'},
+		{value: `\t`, want: '\t'},
+		{value: "\t", want: '\t'},
+		{value: "¦", want: '¦'},
+		{value: "", wantErr: true},
+		{value: ";;", wantErr: true},
+		{value: `\n`, wantErr: true},
+		{value: `"`, wantErr: true},
+		{value: "\r", wantErr: true},
+		{value: "\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDelimiter(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDelimiter(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseDelimiter(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -1,19 +1,24 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"time"
 
 	_ "github.com/lib/pq"
 
 	"weather-platform/internal/config"
+	"weather-platform/internal/repository"
+	"weather-platform/migrations"
 )
 
 func main() {
 	direction := flag.String("direction", "up", "Migration direction: up or down")
+	normalizeDates := flag.Bool("normalize-dates", false, "After migrating up, collapse same-day observations and convert observation_date to DATE if needed")
+	waitTimeout := flag.Duration("wait-timeout", 60*time.Second, "How long to retry connecting to the database before giving up (0 = single attempt)")
 	flag.Parse()
 
 	// Load configuration
@@ -23,6 +28,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Connect to database
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -41,36 +51,121 @@ func main() {
 	}
 	defer db.Close()
 
-	if err := db.Ping(); err != nil {
+	if err := waitForDatabase(db, *waitTimeout); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to ping database: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Println("Connected to database successfully")
 
-	// Read migration file
-	var migrationFile string
-	if *direction == "up" {
-		migrationFile = "migrations/001_create_schema.up.sql"
-	} else {
-		migrationFile = "migrations/001_create_schema.down.sql"
+	// Migrations are written against the default table names; DB_TABLE_PREFIX
+	// is applied to each script before it runs
+	tables := repository.NewTables(cfg.Database.TablePrefix)
+	if cfg.Database.TablePrefix != "" {
+		fmt.Printf("Using table prefix %q\n", cfg.Database.TablePrefix)
 	}
 
-	migrationPath := filepath.Join(".", migrationFile)
-	content, err := os.ReadFile(migrationPath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to read migration file: %v\n", err)
+	// Migration files are embedded in the binary; "up" applies the versions
+	// not yet recorded in schema_migrations in ascending order, "down" reverts
+	// the recorded ones in reverse
+	if *direction != "up" && *direction != "down" {
+		fmt.Fprintf(os.Stderr, "Unknown migration direction %q: expected up or down\n", *direction)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Running migration: %s\n", migrationFile)
+	runner := &migrations.Runner{
+		DB:      db,
+		Table:   tables.Migrations,
+		Rewrite: tables.Rewrite,
+		OnApply: func(script migrations.Script) {
+			fmt.Printf("Applied migration: %s\n", script.Name)
+		},
+		BaselineTable: tables.Stations,
+	}
 
-	// Execute migration
-	_, err = db.Exec(string(content))
+	run := runner.Up
+	if *direction == "down" {
+		run = runner.Down
+	}
+
+	applied, err := run(context.Background())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to execute migration: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Migration failed: %v\n", err)
 		os.Exit(1)
 	}
+	if len(applied) == 0 {
+		fmt.Println("No migrations to apply")
+	}
+
+	if *normalizeDates && *direction == "up" {
+		fmt.Println("Normalizing observation dates")
+		if _, err := db.Exec(tables.Rewrite(normalizeDatesSQL)); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to normalize observation dates: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	fmt.Println("Migration completed successfully")
 }
+
+// normalizeDatesSQL converts a timestamp observation_date column to DATE so the
+// (station_id, observation_date) conflict target collapses same-day records.
+// Rows sharing a calendar day are deduplicated first, keeping the newest.
+// It is a no-op when the column is already DATE.
+const normalizeDatesSQL = `
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'weather_observations'
+          AND column_name = 'observation_date'
+          AND data_type <> 'date'
+    ) THEN
+        DELETE FROM weather_observations o
+        USING weather_observations newer
+        WHERE o.station_id = newer.station_id
+          AND o.observation_date::date = newer.observation_date::date
+          AND (o.created_at, o.id) < (newer.created_at, newer.id);
+
+        ALTER TABLE weather_observations
+            ALTER COLUMN observation_date TYPE DATE USING observation_date::date;
+    END IF;
+END
+$$;
+`
+
+// waitForDatabase pings the database until it responds or the timeout elapses,
+// doubling the delay between attempts up to a fixed cap
+func waitForDatabase(db *sql.DB, timeout time.Duration) error {
+	const (
+		initialBackoff = 500 * time.Millisecond
+		maxBackoff     = 5 * time.Second
+	)
+
+	deadline := time.Now().Add(timeout)
+	backoff := initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := db.Ping()
+		if err == nil {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("database not available after %d attempt(s): %w", attempt, err)
+		}
+
+		wait := backoff
+		if wait > remaining {
+			wait = remaining
+		}
+		fmt.Printf("Database not ready (attempt %d): %v; retrying in %s\n", attempt, err, wait)
+		time.Sleep(wait)
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
@@ -2,18 +2,20 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/pprof"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"weather-platform/internal/config"
 	"weather-platform/internal/handlers"
+	"weather-platform/internal/middleware"
 	"weather-platform/internal/repository"
 	"weather-platform/internal/services"
 	"weather-platform/pkg/database"
@@ -35,12 +37,17 @@ func main() {
 	}
 
 	// Initialize logger
-	logLevel := logging.InfoLevel
-	if cfg.Logging.Level == "debug" {
-		logLevel = logging.DebugLevel
+	logLevel, err := logging.ParseLogLevel(cfg.Logging.Level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid log level, defaulting to info: %v\n", err)
 	}
 
 	logger := logging.NewStructuredLogger("weather-api", "1.0.0", logLevel)
+	if err := logger.ApplyComponentLevels(cfg.Logging.ComponentLevels); err != nil {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid component log levels: %v\n", err)
+	}
+	logger.EnableAsync(cfg.Logging.AsyncBuffer)
+	defer logger.Close()
 
 	ctx := context.Background()
 	logger.Info(ctx, "[STARTUP] Starting weather platform API server", logging.Fields{
@@ -66,27 +73,144 @@ func main() {
 		MaxIdleConns:    cfg.Database.MaxIdleConns,
 		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
 		ConnMaxIdleTime: cfg.Database.ConnMaxIdleTime,
+		MinConns:        cfg.Database.MinConns,
+
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+		FailoverHosts:      cfg.Database.FailoverHosts,
+		QueryComments:      cfg.Database.QueryComments,
+
+		PoolMonitorInterval: cfg.Database.PoolMonitorInterval,
+		PoolWarnUtilization: cfg.Database.PoolWarnUtilization,
+		MaxConcurrentTx:     cfg.Database.MaxConcurrentTx,
+		Options:             cfg.Database.OptionsMap(),
 	}
 
-	db, err := database.NewPostgresDB(dbConfig, logger, metricsCollector)
+	db, err := database.NewPostgresDB(dbConfig, logger.Named("database"), metricsCollector)
 	if err != nil {
 		logger.Fatal(ctx, "[STARTUP_ERROR] Failed to connect to database", logging.Fields{}, err)
 	}
 	defer db.Close()
 
 	// Initialize repository
-	weatherRepo := repository.NewWeatherRepository(db, logger, metricsCollector)
+	weatherRepo := repository.NewWeatherRepository(db, logger.Named("repository"), metricsCollector, repository.NewTables(cfg.Database.TablePrefix))
+
+	// Fail fast with an actionable message when migrations have not been applied,
+	// unless DB_AUTO_MIGRATE allows creating the schema from the embedded migrations
+	err = weatherRepo.VerifySchema(ctx)
+	var schemaErr *repository.SchemaError
+	if errors.As(err, &schemaErr) && cfg.Database.AutoMigrate {
+		logger.Warn(ctx, "[STARTUP_AUTO_MIGRATE] Schema incomplete, applying missing embedded migrations", logging.Fields{
+			"missing_tables": schemaErr.MissingTables,
+		})
+		if err := weatherRepo.ApplyMigrations(ctx); err != nil {
+			logger.Fatal(ctx, "[STARTUP_ERROR] Automatic migration failed; run weather-migrate -direction=up to inspect", logging.Fields{}, err)
+		}
+		err = weatherRepo.VerifySchema(ctx)
+	}
+	if err != nil {
+		if errors.As(err, &schemaErr) {
+			logger.Fatal(ctx, "[STARTUP_SCHEMA_MISSING] Database is not migrated; run `make migrate-up` (or weather-migrate -direction=up) before starting the server", logging.Fields{
+				"missing_tables": schemaErr.MissingTables,
+			}, err)
+		}
+		logger.Fatal(ctx, "[STARTUP_ERROR] Failed to verify database schema", logging.Fields{}, err)
+	}
 
 	// Initialize services
-	weatherService := services.NewWeatherService(weatherRepo, logger, metricsCollector)
-	statsService := services.NewStatisticsService(weatherRepo, logger, metricsCollector)
+	weatherService := services.NewWeatherService(weatherRepo, logger.Named("weather"), metricsCollector)
+	statsService := services.NewStatisticsService(weatherRepo, logger.Named("statistics"), metricsCollector)
+	ingestionService := services.NewIngestionService(weatherRepo, logger.Named("ingestion"), metricsCollector)
+
+	// API-triggered runs use the server's ingestion settings; cfg.Validate
+	// has checked them, and empty values keep the ingester defaults
+	inputFormat, err := services.ParseFormat(cfg.Server.IngestionFormat)
+	if err != nil {
+		logger.Fatal(ctx, "[STARTUP_ERROR] Invalid SERVER_INGESTION_FORMAT", logging.Fields{}, err)
+	}
+	ingestionService.SetOptions(services.IngestionOptions{
+		PersistFailures: cfg.Server.IngestionPersistFailures,
+		Workers:         cfg.Server.IngestionWorkers,
+		Conflict:        repository.ConflictStrategy(cfg.Server.IngestionConflict),
+		Format:          inputFormat,
+	})
 
 	// Initialize handlers
-	weatherHandler := handlers.NewWeatherHandler(weatherService, statsService, logger, metricsCollector)
+	weatherHandler := handlers.NewWeatherHandler(weatherService, statsService, ingestionService, logger.Named("api"), metricsCollector)
+	weatherHandler.SetOptions(handlers.Options{
+		MaxQueryRangeDays: cfg.Server.MaxQueryRangeDays,
+
+		TemperaturePrecision:   cfg.Server.TemperaturePrecision,
+		PrecipitationPrecision: cfg.Server.PrecipitationPrecision,
+
+		DateFormat: cfg.Server.DateFormat,
+		PrettyJSON: cfg.Server.PrettyJSON,
+
+		ExplicitNulls: cfg.Server.ExplicitNulls,
+
+		MaxResponseBytes: cfg.Server.MaxResponseBytes,
+
+		StaleStatsFallback: cfg.Server.StaleStatsFallback,
+
+		IngestionRoot: cfg.Server.IngestionRoot,
+	})
+
+	// Dependency checks reported by /health/deep
+	healthRegistry := handlers.NewHealthRegistry(0)
+	healthRegistry.Register(handlers.NewHealthCheck("postgres", db.HealthCheck), true)
+	weatherHandler.SetHealthRegistry(healthRegistry)
+
+	// Client IPs come from forwarding headers only behind trusted proxies
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		logger.Fatal(ctx, "[STARTUP_ERROR] Invalid SERVER_TRUSTED_PROXIES", logging.Fields{}, err)
+	}
 
 	// Setup router
 	router := mux.NewRouter()
 
+	// Attach a request ID to every request so log lines can be correlated
+	router.Use(middleware.RequestID)
+
+	// Access logs wrap everything after the request ID so rejected requests are logged too
+	if cfg.Server.AccessLogFormat != "" {
+		router.Use(middleware.AccessLog(cfg.Server.AccessLogFormat, os.Stdout, trustedProxies))
+	}
+
+	// Track in-flight requests for the gauge and shutdown draining
+	inFlight := middleware.NewInFlightTracker(metricsCollector.APIRequestsInFlight)
+	router.Use(inFlight.Middleware)
+
+	// Reject every write before auth so a query-only deployment cannot modify data
+	if cfg.Server.ReadOnly {
+		logger.Info(ctx, "[STARTUP_READ_ONLY] Read-only mode active, POST/PUT/PATCH/DELETE requests are rejected with 405", logging.Fields{})
+		router.Use(middleware.ReadOnly)
+	}
+
+	// Protect admin and write routes with Basic Auth
+	if cfg.Auth.Username == "" || cfg.Auth.Password == "" {
+		logger.Warn(ctx, "[STARTUP] Auth credentials not configured, protected routes will reject all requests", logging.Fields{
+			"protected_prefixes": cfg.Auth.ProtectedPrefixes,
+			"protect_writes":     cfg.Auth.ProtectWrites,
+		})
+	}
+	router.Use(middleware.BasicAuth(middleware.BasicAuthConfig{
+		Username:          cfg.Auth.Username,
+		Password:          cfg.Auth.Password,
+		ProtectedPrefixes: cfg.Auth.ProtectedPrefixes,
+		ProtectWrites:     cfg.Auth.ProtectWrites,
+		TrustedProxies:    trustedProxies,
+	}, logger.Named("auth")))
+
+	// Config validation guarantees AUTH_PROTECT_WRITES covers POST /api/ingestion/run
+	if cfg.Server.IngestionRoot != "" {
+		logger.Info(ctx, "[STARTUP_INGESTION_API] API ingestion enabled", logging.Fields{
+			"ingestion_root": cfg.Server.IngestionRoot,
+		})
+	}
+
+	// Bound request bodies on write routes
+	router.Use(middleware.MaxBodySize(cfg.Server.MaxRequestBodyBytes))
+
 	// Register routes
 	weatherHandler.RegisterRoutes(router)
 
@@ -97,6 +221,16 @@ func main() {
 	// Prometheus metrics endpoint
 	router.Handle("/metrics", promhttp.Handler())
 
+	// Profiling endpoints, off by default
+	if cfg.Server.EnablePprof {
+		logger.Warn(ctx, "[STARTUP] pprof profiling endpoints enabled under /debug/pprof", logging.Fields{})
+		router.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		router.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		router.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		router.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		router.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
+	}
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
@@ -104,6 +238,7 @@ func main() {
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
+		ConnState:    metricsCollector.TrackConnState,
 	}
 
 	// Start server in goroutine
@@ -117,18 +252,39 @@ func main() {
 		}
 	}()
 
+	// Purge old observations in the background, stopped before shutdown
+	retentionCtx, stopRetention := context.WithCancel(ctx)
+	defer stopRetention()
+	if cfg.Retention.Years > 0 {
+		logger.Info(ctx, "[STARTUP_RETENTION] Observation retention enabled", logging.Fields{
+			"retention_years": cfg.Retention.Years,
+			"interval":        cfg.Retention.Interval.String(),
+		})
+		go weatherService.RunRetention(retentionCtx, cfg.Retention.Years, cfg.Retention.Interval)
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	stopRetention()
 
-	logger.Info(ctx, "[SHUTDOWN] Shutting down server...", logging.Fields{})
+	logger.Info(ctx, "[SHUTDOWN] Shutting down server, draining in-flight requests", logging.Fields{
+		"in_flight": inFlight.Total(),
+		"timeout":   cfg.Server.ShutdownTimeout.String(),
+	})
 
-	// Graceful shutdown with timeout
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Graceful shutdown: stop accepting connections, let in-flight requests finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			logger.Warn(ctx, "[SHUTDOWN_TIMEOUT] Shutdown timeout elapsed with requests still active", logging.Fields{
+				"in_flight":      inFlight.Total(),
+				"busy_endpoints": inFlight.Snapshot(),
+			})
+		}
 		logger.Error(ctx, "[SHUTDOWN_ERROR] Server forced to shutdown", logging.Fields{}, err)
 	}
 
@@ -7,7 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"weather-platform/internal/models"
+	"weather-platform/internal/services"
 	"weather-platform/pkg/logging"
 )
 
@@ -52,43 +52,31 @@ func main() {
 			continue
 		}
 
-		// Read and parse file
-		var lines []string
-		content, _ := os.ReadFile(filePath)
-		lines = strings.Split(string(content), "\n")
-
 		fileRecords := 0
 		fileValid := 0
 		fileMissing := 0
 
-		for i, line := range lines {
-			if line == "" {
-				continue
+		// Stream the file line by line with the ingester's parser
+		err = services.ScanLines(file, func(line int, text string) bool {
+			if text == "" {
+				return true
 			}
 
 			totalRecords++
 			fileRecords++
 
 			// Parse line
-			parts := strings.Split(line, "\t")
-			if len(parts) != 4 {
-				fmt.Printf("  [%d] Invalid format: %s\n", i+1, line)
-				continue
-			}
-
-			// Create raw record
-			record := &models.RawWeatherRecord{
-				Date:                 strings.TrimSpace(parts[0]),
-				MaxTemperatureTenths: parseInt(parts[1]),
-				MinTemperatureTenths: parseInt(parts[2]),
-				PrecipitationTenths:  parseInt(parts[3]),
+			record, err := services.ParseLine(text)
+			if err != nil {
+				fmt.Printf("  [%d] Invalid format: %v\n", line, err)
+				return true
 			}
 
 			// Convert to observation
 			obs, err := record.ToObservation(stationID)
 			if err != nil {
-				fmt.Printf("  [%d] Conversion error: %v\n", i+1, err)
-				continue
+				fmt.Printf("  [%d] Conversion error: %v\n", line, err)
+				return true
 			}
 
 			fileValid++
@@ -113,8 +101,8 @@ func main() {
 			}
 
 			// Print first 3 records and any with missing data
-			if i < 3 || hasMissing {
-				fmt.Printf("  [%d] Date: %s", i+1, obs.ObservationDate.Format("2006-01-02"))
+			if line <= 3 || hasMissing {
+				fmt.Printf("  [%d] Date: %s", line, obs.ObservationDate.Format("2006-01-02"))
 
 				if obs.MaxTemperatureCelsius != nil {
 					fmt.Printf(" | Max: %.1f°C", *obs.MaxTemperatureCelsius)
@@ -139,6 +127,14 @@ func main() {
 				}
 				fmt.Println()
 			}
+			return true
+		})
+		file.Close()
+
+		if err != nil {
+			logger.Error(ctx, "Failed to read file", logging.Fields{
+				"file": filePath,
+			}, err)
 		}
 
 		fmt.Printf("\n  Station Summary:\n")
@@ -146,8 +142,6 @@ func main() {
 		fmt.Printf("    Valid conversions: %d\n", fileValid)
 		fmt.Printf("    Missing values: %d\n", fileMissing)
 		fmt.Println()
-
-		file.Close()
 	}
 
 	fmt.Println("════════════════════════════════════════════════════════════════")
@@ -171,31 +165,28 @@ func main() {
 		fileName := filepath.Base(filePath)
 		stationID := strings.TrimSuffix(fileName, filepath.Ext(fileName))
 
-		content, _ := os.ReadFile(filePath)
-		lines := strings.Split(string(content), "\n")
-
 		var maxTemps []float64
 		var minTemps []float64
 		var precips []float64
 
-		for _, line := range lines {
-			if line == "" {
-				continue
-			}
+		file, err := os.Open(filePath)
+		if err != nil {
+			logger.Error(ctx, "Failed to open file", logging.Fields{
+				"file": filePath,
+			}, err)
+			os.Exit(1)
+		}
 
-			parts := strings.Split(line, "\t")
-			if len(parts) != 4 {
-				continue
+		err = services.ScanLines(file, func(_ int, text string) bool {
+			record, err := services.ParseLine(text)
+			if err != nil {
+				return true
 			}
 
-			record := &models.RawWeatherRecord{
-				Date:                 strings.TrimSpace(parts[0]),
-				MaxTemperatureTenths: parseInt(parts[1]),
-				MinTemperatureTenths: parseInt(parts[2]),
-				PrecipitationTenths:  parseInt(parts[3]),
+			obs, err := record.ToObservation(stationID)
+			if err != nil {
+				return true
 			}
-
-			obs, _ := record.ToObservation(stationID)
 			if obs.MaxTemperatureCelsius != nil {
 				maxTemps = append(maxTemps, *obs.MaxTemperatureCelsius)
 			}
@@ -205,6 +196,14 @@ func main() {
 			if obs.PrecipitationCm != nil {
 				precips = append(precips, *obs.PrecipitationCm)
 			}
+			return true
+		})
+		file.Close()
+
+		if err != nil {
+			logger.Error(ctx, "Failed to read file", logging.Fields{
+				"file": filePath,
+			}, err)
 		}
 
 		fmt.Printf("Station: %s\n", stationID)
@@ -246,12 +245,6 @@ func main() {
 	fmt.Println()
 }
 
-func parseInt(s string) int {
-	var val int
-	fmt.Sscanf(strings.TrimSpace(s), "%d", &val)
-	return val
-}
-
 func average(values []float64) float64 {
 	if len(values) == 0 {
 		return 0
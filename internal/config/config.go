@@ -2,16 +2,43 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+
+	"weather-platform/internal/middleware"
 )
 
 // Config holds application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logging  LoggingConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Logging   LoggingConfig
+	Auth      AuthConfig
+	Stats     StatsConfig
+	Retention RetentionConfig
+}
+
+// StatsConfig holds statistics calculation configuration
+type StatsConfig struct {
+	// MinObservationsForStats is the observation count a station-year needs to be marked reliable
+	MinObservationsForStats int
+
+	// Concurrency is the number of stations whose statistics are calculated at once
+	Concurrency int
+}
+
+// RetentionConfig holds the observation purge policy
+type RetentionConfig struct {
+	// Years is the number of full years kept before the current one;
+	// older observations are purged once their statistics exist (0 keeps everything)
+	Years int
+
+	// Interval is the time between scheduled purges
+	Interval time.Duration
 }
 
 // ServerConfig holds HTTP server configuration
@@ -21,6 +48,59 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+
+	// ShutdownTimeout bounds how long in-flight requests may drain on shutdown
+	ShutdownTimeout time.Duration
+
+	// MaxQueryRangeDays caps date-range queries (observations, comparisons, missing dates)
+	MaxQueryRangeDays int
+
+	// TemperaturePrecision and PrecipitationPrecision set response rounding (negative disables)
+	TemperaturePrecision   int
+	PrecipitationPrecision int
+
+	// DateFormat is the default observation date format: "datetime" or "date"
+	DateFormat string
+
+	// PrettyJSON indents JSON responses unless a request sets pretty=false
+	PrettyJSON bool
+
+	// ExplicitNulls writes missing values as null unless a request sets nulls=omit
+	ExplicitNulls bool
+
+	// StaleStatsFallback serves the last good statistics page when the database read fails
+	StaleStatsFallback bool
+
+	// EnablePprof mounts net/http/pprof handlers under /debug/pprof
+	EnablePprof bool
+
+	// MaxRequestBodyBytes caps request bodies on write routes (413 when exceeded)
+	MaxRequestBodyBytes int64
+
+	// MaxResponseBytes caps list response bodies (0 disables)
+	MaxResponseBytes int64
+
+	// TrustedProxies are CIDRs whose X-Forwarded-For/X-Real-IP headers are honored
+	TrustedProxies []string
+
+	// ReadOnly rejects every POST/PUT/PATCH/DELETE request with 405
+	ReadOnly bool
+
+	// AccessLogFormat writes an access log line per request to stdout:
+	// "common", "combined" or "json" (empty disables)
+	AccessLogFormat string
+
+	// IngestionRoot is the only directory tree POST /api/ingestion/run may
+	// ingest from (empty disables the endpoint)
+	IngestionRoot string
+
+	// IngestionWorkers, IngestionConflict, IngestionFormat and
+	// IngestionPersistFailures configure runs started through the API, like
+	// the ingester's -workers, -conflict, -format and -persist-failures flags
+	IngestionWorkers         int
+	IngestionConflict        string
+	IngestionFormat          string
+	IngestionPersistFailures bool
 }
 
 // DatabaseConfig holds database configuration
@@ -35,12 +115,59 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+	MinConns        int
+
+	// SlowQueryThreshold logs queries slower than this at Warn (0 disables)
+	SlowQueryThreshold time.Duration
+
+	// TablePrefix is prepended to every table name (empty keeps the default names)
+	TablePrefix string
+
+	// FailoverHosts are tried in order when Host is unreachable ("host" or "host:port")
+	FailoverHosts []string
+
+	// AutoMigrate lets the server apply the missing embedded migrations when its
+	// schema check finds tables missing (off by default)
+	AutoMigrate bool
+
+	// QueryComments prepends the request ID to query text as a SQL comment
+	QueryComments bool
+
+	// PoolMonitorInterval is how often pool metrics are published and failover
+	// hosts checked (0 disables the monitor)
+	PoolMonitorInterval time.Duration
+
+	// PoolWarnUtilization logs a warning when in-use connections exceed this
+	// fraction of MaxOpenConns (0 disables)
+	PoolWarnUtilization float64
+
+	// MaxConcurrentTx caps concurrent batch insert transactions (0 means unlimited)
+	MaxConcurrentTx int
+
+	// Options are extra connection parameters as key=value entries, e.g.
+	// connect_timeout=5 or application_name=weather-api-blue
+	Options []string
+}
+
+// AuthConfig holds HTTP Basic Auth configuration for admin and write endpoints
+type AuthConfig struct {
+	Username          string
+	Password          string
+	ProtectedPrefixes []string
+	ProtectWrites     bool
 }
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level   string
 	Format  string
+
+	// ComponentLevels overrides Level per subsystem, from LOG_LEVEL_<COMPONENT> variables
+	ComponentLevels map[string]string
+
+	// AsyncBuffer is the number of entries buffered for the background log
+	// writer (0 writes synchronously)
+	AsyncBuffer int
 }
 
 // LoadConfig loads configuration from environment variables
@@ -52,6 +179,40 @@ func LoadConfig() (*Config, error) {
 			ReadTimeout:  getEnvDuration("SERVER_READ_TIMEOUT", 10*time.Second),
 			WriteTimeout: getEnvDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
 			IdleTimeout:  getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+
+			ShutdownTimeout: getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+
+			MaxQueryRangeDays: getEnvInt("SERVER_MAX_QUERY_RANGE_DAYS", 3660),
+
+			TemperaturePrecision:   getEnvInt("SERVER_TEMPERATURE_PRECISION", 2),
+			PrecipitationPrecision: getEnvInt("SERVER_PRECIPITATION_PRECISION", 2),
+
+			DateFormat: getEnv("SERVER_DATE_FORMAT", "datetime"),
+
+			PrettyJSON: getEnvBool("SERVER_PRETTY_JSON", false),
+
+			ExplicitNulls: getEnvBool("SERVER_EXPLICIT_NULLS", false),
+
+			StaleStatsFallback: getEnvBool("SERVER_STALE_STATS", false),
+
+			EnablePprof: getEnvBool("SERVER_ENABLE_PPROF", false),
+
+			MaxRequestBodyBytes: int64(getEnvInt("SERVER_MAX_REQUEST_BODY_BYTES", 5<<20)),
+
+			MaxResponseBytes: int64(getEnvInt("SERVER_MAX_RESPONSE_BYTES", 64<<20)),
+
+			TrustedProxies: getEnvList("SERVER_TRUSTED_PROXIES", nil),
+
+			ReadOnly: getEnvBool("SERVER_READ_ONLY", false),
+
+			AccessLogFormat: getEnv("SERVER_ACCESS_LOG_FORMAT", ""),
+
+			IngestionRoot: getEnv("SERVER_INGESTION_ROOT", ""),
+
+			IngestionWorkers:         getEnvInt("SERVER_INGESTION_WORKERS", 1),
+			IngestionConflict:        getEnv("SERVER_INGESTION_CONFLICT", "update"),
+			IngestionFormat:          getEnv("SERVER_INGESTION_FORMAT", "auto"),
+			IngestionPersistFailures: getEnvBool("SERVER_INGESTION_PERSIST_FAILURES", false),
 		},
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -64,10 +225,44 @@ func LoadConfig() (*Config, error) {
 			MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
 			ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 			ConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			MinConns:        getEnvInt("DB_MIN_CONNS", 0),
+
+			SlowQueryThreshold: getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 500*time.Millisecond),
+
+			TablePrefix: getEnv("DB_TABLE_PREFIX", ""),
+
+			FailoverHosts: getEnvList("DB_FAILOVER_HOSTS", nil),
+
+			AutoMigrate: getEnvBool("DB_AUTO_MIGRATE", false),
+
+			QueryComments: getEnvBool("DB_QUERY_COMMENTS", false),
+
+			PoolMonitorInterval: getEnvDuration("DB_POOL_MONITOR_INTERVAL", 10*time.Second),
+			PoolWarnUtilization: getEnvFloat("DB_POOL_WARN_UTILIZATION", 0.8),
+
+			MaxConcurrentTx: getEnvInt("DB_MAX_CONCURRENT_TX", 0),
+
+			Options: getEnvList("DB_OPTIONS", nil),
 		},
 		Logging: LoggingConfig{
-			Level:  getEnv("LOG_LEVEL", "info"),
-			Format: getEnv("LOG_FORMAT", "json"),
+			Level:           getEnv("LOG_LEVEL", "info"),
+			Format:          getEnv("LOG_FORMAT", "json"),
+			ComponentLevels: getEnvComponentLevels("LOG_LEVEL_"),
+			AsyncBuffer:     getEnvInt("LOG_ASYNC_BUFFER", 0),
+		},
+		Stats: StatsConfig{
+			MinObservationsForStats: getEnvInt("STATS_MIN_OBSERVATIONS", 0),
+			Concurrency:             getEnvInt("STATS_CONCURRENCY", 4),
+		},
+		Retention: RetentionConfig{
+			Years:    getEnvInt("RETENTION_YEARS", 0),
+			Interval: getEnvDuration("RETENTION_INTERVAL", 24*time.Hour),
+		},
+		Auth: AuthConfig{
+			Username:          getEnv("AUTH_USERNAME", ""),
+			Password:          getEnv("AUTH_PASSWORD", ""),
+			ProtectedPrefixes: getEnvList("AUTH_PROTECTED_PREFIXES", []string{"/api/admin", "/debug/pprof"}),
+			ProtectWrites:     getEnvBool("AUTH_PROTECT_WRITES", true),
 		},
 	}
 
@@ -82,6 +277,23 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvComponentLevels collects variables named <prefix><COMPONENT> into a
+// lowercase component -> value map, e.g. LOG_LEVEL_DATABASE=warn -> database: warn
+func getEnvComponentLevels(prefix string) map[string]string {
+	levels := make(map[string]string)
+	for _, kv := range os.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(key, prefix) || value == "" {
+			continue
+		}
+		component := strings.ToLower(strings.TrimPrefix(key, prefix))
+		if component != "" {
+			levels[component] = value
+		}
+	}
+	return levels
+}
+
 // getEnvInt gets integer environment variable with default value
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
@@ -92,6 +304,42 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvBool gets boolean environment variable with default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvFloat gets float environment variable with default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList gets comma-separated environment variable with default value
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // getEnvDuration gets duration environment variable with default value
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
@@ -102,19 +350,159 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+// FieldError describes one invalid configuration value
+// Field is the environment variable that sets it
+type FieldError struct {
+	Field   string
+	Value   interface{}
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s=%v: %s", e.Field, e.Value, e.Message)
+}
+
+// ValidationErrors lists every problem found by Validate
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d configuration problem(s):", len(e))
+	for _, fieldErr := range e {
+		b.WriteString("\n  - ")
+		b.WriteString(fieldErr.Error())
+	}
+	return b.String()
+}
+
+// maxTablePrefixLen keeps the longest prefixed index name within
+// PostgreSQL's 63-byte identifier limit
+const maxTablePrefixLen = 29
+
+// validTablePrefix reports whether prefix is safe to splice into SQL identifiers
+func validTablePrefix(prefix string) bool {
+	if len(prefix) > maxTablePrefixLen {
+		return false
+	}
+	for i, c := range prefix {
+		switch {
+		case c >= 'a' && c <= 'z', c == '_' && i > 0, c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// reservedOptionKeys are connection parameters set from their own variables
+var reservedOptionKeys = []string{"host", "port", "user", "password", "dbname", "sslmode"}
+
+// validOptionKey reports whether key may be passed through DB_OPTIONS
+func validOptionKey(key string) bool {
+	if key == "" || slices.Contains(reservedOptionKeys, key) {
+		return false
+	}
+	for _, c := range key {
+		if (c < 'a' || c > 'z') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// OptionsMap returns Options as connection parameters keyed by name
+// Call after Validate, which rejects entries without a key
+func (c DatabaseConfig) OptionsMap() map[string]string {
+	options := make(map[string]string, len(c.Options))
+	for _, option := range c.Options {
+		if key, value, ok := strings.Cut(option, "="); ok {
+			options[key] = value
+		}
+	}
+	return options
+}
+
+// validHostPort reports whether value is a host or a host:port with a valid port
+func validHostPort(value string) bool {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		// No port: a bare host name or IPv6 address
+		return !strings.ContainsAny(value, "[]") && value != ""
+	}
+	portNum, err := strconv.Atoi(port)
+	return err == nil && host != "" && portNum >= 1 && portNum <= 65535
+}
+
 // Validate validates the configuration
+// All problems are collected and returned together as ValidationErrors
 func (c *Config) Validate() error {
-	if c.Server.Port <= 0 || c.Server.Port > 65535 {
-		return fmt.Errorf("invalid server port: %d", c.Server.Port)
+	var errs ValidationErrors
+	check := func(ok bool, field string, value interface{}, message string) {
+		if !ok {
+			errs = append(errs, FieldError{Field: field, Value: value, Message: message})
+		}
 	}
 
-	if c.Database.Host == "" {
-		return fmt.Errorf("database host is required")
-	}
+	// Server
+	check(c.Server.Port >= 1 && c.Server.Port <= 65535, "SERVER_PORT", c.Server.Port, "must be between 1 and 65535")
+	check(c.Server.ReadTimeout > 0, "SERVER_READ_TIMEOUT", c.Server.ReadTimeout, "must be positive")
+	check(c.Server.WriteTimeout > 0, "SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout, "must be positive")
+	check(c.Server.IdleTimeout > 0, "SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout, "must be positive")
+	check(c.Server.ShutdownTimeout > 0, "SERVER_SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout, "must be positive")
+	check(c.Server.MaxQueryRangeDays >= 0, "SERVER_MAX_QUERY_RANGE_DAYS", c.Server.MaxQueryRangeDays, "must not be negative (0 disables)")
+	check(c.Server.DateFormat == "" || c.Server.DateFormat == "datetime" || c.Server.DateFormat == "date", "SERVER_DATE_FORMAT", c.Server.DateFormat, "must be datetime or date")
+	check(c.Server.AccessLogFormat == "" || middleware.IsValidAccessLogFormat(c.Server.AccessLogFormat), "SERVER_ACCESS_LOG_FORMAT", c.Server.AccessLogFormat, "must be common, combined or json (empty disables)")
+	check(c.Server.MaxRequestBodyBytes > 0, "SERVER_MAX_REQUEST_BODY_BYTES", c.Server.MaxRequestBodyBytes, "must be positive")
+	check(c.Server.MaxResponseBytes >= 0, "SERVER_MAX_RESPONSE_BYTES", c.Server.MaxResponseBytes, "must not be negative (0 disables)")
 
-	if c.Database.Database == "" {
-		return fmt.Errorf("database name is required")
+	// Database
+	check(c.Database.Host != "", "DB_HOST", `""`, "is required")
+	check(c.Database.Port >= 1 && c.Database.Port <= 65535, "DB_PORT", c.Database.Port, "must be between 1 and 65535")
+	check(c.Database.Database != "", "DB_NAME", `""`, "is required")
+	check(c.Database.MaxOpenConns > 0, "DB_MAX_OPEN_CONNS", c.Database.MaxOpenConns, "must be positive")
+	check(c.Database.MaxIdleConns >= 0, "DB_MAX_IDLE_CONNS", c.Database.MaxIdleConns, "must not be negative")
+	check(c.Database.MaxIdleConns <= c.Database.MaxOpenConns, "DB_MAX_IDLE_CONNS", c.Database.MaxIdleConns,
+		fmt.Sprintf("must not exceed DB_MAX_OPEN_CONNS (%d)", c.Database.MaxOpenConns))
+	check(c.Database.MinConns >= 0, "DB_MIN_CONNS", c.Database.MinConns, "must not be negative")
+	check(c.Database.MinConns <= c.Database.MaxOpenConns, "DB_MIN_CONNS", c.Database.MinConns,
+		fmt.Sprintf("must not exceed DB_MAX_OPEN_CONNS (%d)", c.Database.MaxOpenConns))
+	check(c.Database.ConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME", c.Database.ConnMaxLifetime, "must not be negative (0 means unlimited)")
+	check(c.Database.ConnMaxIdleTime >= 0, "DB_CONN_MAX_IDLE_TIME", c.Database.ConnMaxIdleTime, "must not be negative (0 means unlimited)")
+	check(c.Database.SlowQueryThreshold >= 0, "DB_SLOW_QUERY_THRESHOLD", c.Database.SlowQueryThreshold, "must not be negative (0 disables)")
+	check(c.Database.PoolMonitorInterval >= 0, "DB_POOL_MONITOR_INTERVAL", c.Database.PoolMonitorInterval, "must not be negative (0 disables)")
+	check(c.Database.PoolWarnUtilization >= 0 && c.Database.PoolWarnUtilization <= 1, "DB_POOL_WARN_UTILIZATION", c.Database.PoolWarnUtilization, "must be between 0 and 1 (0 disables)")
+	check(c.Database.MaxConcurrentTx >= 0, "DB_MAX_CONCURRENT_TX", c.Database.MaxConcurrentTx, "must not be negative (0 means unlimited)")
+	for _, option := range c.Database.Options {
+		key, _, ok := strings.Cut(option, "=")
+		check(ok && validOptionKey(key), "DB_OPTIONS", option, "must be key=value with a lowercase key other than host, port, user, password, dbname or sslmode")
 	}
+	for _, host := range c.Database.FailoverHosts {
+		check(validHostPort(host), "DB_FAILOVER_HOSTS", host, "must be host or host:port with a port between 1 and 65535")
+	}
+	check(c.Server.IngestionRoot == "" || c.Auth.ProtectWrites, "SERVER_INGESTION_ROOT", c.Server.IngestionRoot, "requires AUTH_PROTECT_WRITES=true so API ingestion needs credentials")
+	check(c.Server.IngestionWorkers >= 0, "SERVER_INGESTION_WORKERS", c.Server.IngestionWorkers, "must not be negative (0 means 1)")
+	check(c.Server.IngestionConflict == "" || c.Server.IngestionConflict == "update" || c.Server.IngestionConflict == "ignore" || c.Server.IngestionConflict == "error", "SERVER_INGESTION_CONFLICT", c.Server.IngestionConflict, "must be update, ignore or error")
+	check(c.Server.IngestionFormat == "" || c.Server.IngestionFormat == "auto" || c.Server.IngestionFormat == "tab" || c.Server.IngestionFormat == "csv", "SERVER_INGESTION_FORMAT", c.Server.IngestionFormat, "must be auto, tab or csv")
+	check(c.Server.IngestionRoot == "" || !c.Server.ReadOnly, "SERVER_INGESTION_ROOT", c.Server.IngestionRoot, "must be empty when SERVER_READ_ONLY is true")
+	check(!(c.Server.ReadOnly && c.Database.AutoMigrate), "DB_AUTO_MIGRATE", c.Database.AutoMigrate, "must be false when SERVER_READ_ONLY is true")
+	check(validTablePrefix(c.Database.TablePrefix), "DB_TABLE_PREFIX", c.Database.TablePrefix,
+		fmt.Sprintf("must be lowercase letters, digits and underscores, start with a letter, and be at most %d characters", maxTablePrefixLen))
+
+	// Statistics
+	check(c.Stats.MinObservationsForStats >= 0 && c.Stats.MinObservationsForStats <= 366,
+		"STATS_MIN_OBSERVATIONS", c.Stats.MinObservationsForStats, "must be between 0 and 366")
+	check(c.Stats.Concurrency >= 1, "STATS_CONCURRENCY", c.Stats.Concurrency, "must be at least 1")
 
+	// Retention
+	check(c.Retention.Years >= 0, "RETENTION_YEARS", c.Retention.Years, "must not be negative (0 disables)")
+	check(c.Retention.Years == 0 || c.Retention.Interval > 0, "RETENTION_INTERVAL", c.Retention.Interval, "must be positive when RETENTION_YEARS is set")
+	check(c.Retention.Years == 0 || !c.Server.ReadOnly, "RETENTION_YEARS", c.Retention.Years, "must be 0 when SERVER_READ_ONLY is true")
+
+	// Logging
+	check(c.Logging.AsyncBuffer >= 0, "LOG_ASYNC_BUFFER", c.Logging.AsyncBuffer, "must not be negative (0 disables)")
+
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig returns a configuration that passes validation
+func validConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:                8080,
+			ReadTimeout:         10 * time.Second,
+			WriteTimeout:        10 * time.Second,
+			IdleTimeout:         120 * time.Second,
+			ShutdownTimeout:     30 * time.Second,
+			MaxRequestBodyBytes: 5 << 20,
+		},
+		Database: DatabaseConfig{
+			Host:         "localhost",
+			Port:         5432,
+			Database:     "weather_db",
+			MaxOpenConns: 25,
+			MaxIdleConns: 5,
+		},
+		Stats: StatsConfig{
+			Concurrency: 4,
+		},
+	}
+}
+
+// TestValidate tests that every problem is reported at once
+func TestValidate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("Validate() on valid config error = %v", err)
+	}
+
+	cfg := validConfig()
+	cfg.Server.Port = 70000
+	cfg.Server.ReadTimeout = 0
+	cfg.Database.Host = ""
+	cfg.Database.MaxOpenConns = 2
+	cfg.Database.MaxIdleConns = 5
+
+	err := cfg.Validate()
+
+	var validationErrs ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+	}
+
+	wantFields := []string{"SERVER_PORT", "SERVER_READ_TIMEOUT", "DB_HOST", "DB_MAX_IDLE_CONNS"}
+	if len(validationErrs) != len(wantFields) {
+		t.Fatalf("Validate() returned %d problems, want %d: %v", len(validationErrs), len(wantFields), err)
+	}
+	for i, field := range wantFields {
+		if validationErrs[i].Field != field {
+			t.Errorf("problem %d field = %q, want %q", i, validationErrs[i].Field, field)
+		}
+	}
+
+	if !strings.Contains(err.Error(), "SERVER_PORT=70000") {
+		t.Errorf("error message %q does not include the offending value", err.Error())
+	}
+}
+
+// TestValidateTablePrefix tests which table prefixes are accepted
+func TestValidateTablePrefix(t *testing.T) {
+	tests := []struct {
+		prefix string
+		valid  bool
+	}{
+		{"", true},
+		{"wx_", true},
+		{"tenant2_", true},
+		{"WX_", false},
+		{"_wx", false},
+		{"2wx_", false},
+		{"wx-", false},
+		{"wx; DROP TABLE", false},
+		{strings.Repeat("a", maxTablePrefixLen+1), false},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Database.TablePrefix = tt.prefix
+		if err := cfg.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate() with DB_TABLE_PREFIX=%q error = %v, want valid %v", tt.prefix, err, tt.valid)
+		}
+	}
+}
+
+// TestValidateFailoverHosts tests failover host address checks
+func TestValidateFailoverHosts(t *testing.T) {
+	tests := []struct {
+		host  string
+		valid bool
+	}{
+		{"db-standby", true},
+		{"db-standby:5433", true},
+		{"[::1]:5433", true},
+		{"db-standby:0", false},
+		{"db-standby:abc", false},
+		{":5433", false},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Database.FailoverHosts = []string{tt.host}
+		if err := cfg.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate() with DB_FAILOVER_HOSTS=%q error = %v, want valid %v", tt.host, err, tt.valid)
+		}
+	}
+}
+
+// TestValidateDatabaseOptions tests which DB_OPTIONS entries are accepted
+func TestValidateDatabaseOptions(t *testing.T) {
+	tests := []struct {
+		option string
+		valid  bool
+	}{
+		{"application_name=weather-api", true},
+		{"options=-c statement_timeout=30000", true},
+		{"connect_timeout=", true},
+		{"connect_timeout", false},
+		{"=5", false},
+		{"password=secret", false},
+		{"Connect-Timeout=5", false},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Database.Options = []string{tt.option}
+		if err := cfg.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate() with DB_OPTIONS=%q error = %v, want valid %v", tt.option, err, tt.valid)
+		}
+	}
+
+	cfg := DatabaseConfig{Options: []string{"connect_timeout=5", "options=-c statement_timeout=30000"}}
+	options := cfg.OptionsMap()
+	if options["connect_timeout"] != "5" || options["options"] != "-c statement_timeout=30000" {
+		t.Errorf("OptionsMap() = %v", options)
+	}
+}
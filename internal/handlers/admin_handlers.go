@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"weather-platform/internal/repository"
+	"weather-platform/pkg/logging"
+)
+
+// CompactDuplicates handles POST /api/admin/observations/compact
+func (h *WeatherHandler) CompactDuplicates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/admin/observations/compact").Observe(duration.Seconds())
+	}()
+
+	removed, err := h.weatherService.CompactDuplicates(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "[API_COMPACT_DUPLICATES_ERROR] Failed to compact duplicate observations", logging.Fields{}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/admin/observations/compact")
+		h.sendError(w, r, "failed to compact duplicate observations", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info(ctx, "[API_COMPACT_DUPLICATES] Duplicate observations compacted", logging.Fields{
+		"rows_removed": removed,
+	})
+
+	response := map[string]interface{}{
+		"rows_removed": removed,
+	}
+
+	h.metrics.RecordAPIRequest("/api/admin/observations/compact", "POST", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// BackfillMissingDates handles POST /api/admin/observations/backfill
+func (h *WeatherHandler) BackfillMissingDates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/admin/observations/backfill").Observe(duration.Seconds())
+	}()
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		h.sendError(w, r, "station_id is required", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseDateParam(r, "from")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseDateParam(r, "to")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if from == nil || to == nil {
+		h.sendError(w, r, "from and to are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validateDateRange(from, to); err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Observation dates cannot be in the future, so placeholder rows past today
+	// would violate the schema's date constraint
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if to.After(today) {
+		h.sendError(w, r, "to must not be in the future", http.StatusBadRequest)
+		return
+	}
+
+	inserted, err := h.weatherService.BackfillMissingDates(ctx, stationID, *from, *to)
+	if err != nil {
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_BACKFILL_MISSING_DATES_ERROR] Failed to backfill missing dates", logging.Fields{
+			"station_id": stationID,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/admin/observations/backfill")
+		h.sendError(w, r, "failed to backfill missing dates", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info(ctx, "[API_BACKFILL_MISSING_DATES] Missing dates backfilled", logging.Fields{
+		"station_id":    stationID,
+		"from":          from.Format("2006-01-02"),
+		"to":            to.Format("2006-01-02"),
+		"rows_inserted": inserted,
+	})
+
+	response := map[string]interface{}{
+		"station_id":    stationID,
+		"from":          from.Format("2006-01-02"),
+		"to":            to.Format("2006-01-02"),
+		"rows_inserted": inserted,
+	}
+
+	h.metrics.RecordAPIRequest("/api/admin/observations/backfill", "POST", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
@@ -0,0 +1,921 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"weather-platform/internal/models"
+	"weather-platform/internal/repository"
+	"weather-platform/pkg/logging"
+)
+
+// maxMissingRangeDays caps the generated date series for missing-date queries,
+// whatever SERVER_MAX_QUERY_RANGE_DAYS allows
+const maxMissingRangeDays = 3660
+
+// ComparisonResponse represents a side-by-side station comparison
+type ComparisonResponse struct {
+	StationA string                    `json:"station_a"`
+	StationB string                    `json:"station_b"`
+	Metric   string                    `json:"metric"`
+	From     string                    `json:"from"`
+	To       string                    `json:"to"`
+	Data     []*models.DailyComparison `json:"data"`
+}
+
+// maxMovingAverageWindow bounds the moving-average window in observations
+const maxMovingAverageWindow = 365
+
+// maxHistogramBins bounds the number of histogram bins per request
+const maxHistogramBins = 100
+
+// maxRankingLimit bounds the number of stations per ranking request
+const maxRankingLimit = 100
+
+// defaultDegreeDayBase is the conventional degree-day base temperature in °C
+const defaultDegreeDayBase = 18.0
+
+// defaultGrowingDegreeDayBase is the common growing degree-day base in °C
+const defaultGrowingDegreeDayBase = 10.0
+
+// CompareStations handles GET /api/weather/compare
+func (h *WeatherHandler) CompareStations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/compare").Observe(duration.Seconds())
+	}()
+
+	// Parse query parameters
+	stationA := r.URL.Query().Get("station_a")
+	stationB := r.URL.Query().Get("station_b")
+	metric := r.URL.Query().Get("metric")
+
+	if stationA == "" || stationB == "" {
+		h.sendError(w, r, "station_a and station_b are required", http.StatusBadRequest)
+		return
+	}
+
+	if metric == "" {
+		metric = "max_temp"
+	}
+	if !repository.IsValidObservationMetric(metric) {
+		h.sendError(w, r, "invalid metric, expected one of max_temp, min_temp, precip", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseDateParam(r, "from")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseDateParam(r, "to")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if from == nil || to == nil {
+		h.sendError(w, r, "from and to are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validateDateRange(from, to); err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Compare stations
+	comparisons, err := h.weatherService.CompareStations(ctx, stationA, stationB, metric, *from, *to)
+	if err != nil {
+		h.logger.Error(ctx, "[API_COMPARE_STATIONS_ERROR] Failed to compare stations", logging.Fields{
+			"station_a": stationA,
+			"station_b": stationB,
+			"metric":    metric,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/compare")
+		h.sendError(w, r, "failed to compare stations", http.StatusInternalServerError)
+		return
+	}
+
+	response := ComparisonResponse{
+		StationA: stationA,
+		StationB: stationB,
+		Metric:   metric,
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Data:     comparisons,
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/compare", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetExtremes handles GET /api/weather/extremes
+func (h *WeatherHandler) GetExtremes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/extremes").Observe(duration.Seconds())
+	}()
+
+	year, err := parseYearParam(r, "year")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var stationID *string
+	if id := r.URL.Query().Get("station_id"); id != "" {
+		stationID = &id
+	}
+
+	extremes, err := h.weatherService.GetExtremes(ctx, year, stationID)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_EXTREMES_ERROR] Failed to get extremes", logging.Fields{
+			"year":       year,
+			"station_id": stationID,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/extremes")
+		h.sendError(w, r, "failed to retrieve extremes", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/extremes", "GET", "200")
+	h.sendJSON(w, r, extremes, http.StatusOK)
+}
+
+// GetGlobalStats handles GET /api/stats/global
+// The observation count is an estimate unless exact=true
+func (h *WeatherHandler) GetGlobalStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/stats/global").Observe(duration.Seconds())
+	}()
+
+	exact, err := parseBoolParam(r, "exact")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.weatherService.GetGlobalStats(ctx, exact != nil && *exact)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_GLOBAL_STATS_ERROR] Failed to get global stats", logging.Fields{
+			"exact": exact != nil && *exact,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/stats/global")
+		h.sendError(w, r, "failed to retrieve global stats", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/stats/global", "GET", "200")
+	h.sendJSON(w, r, stats, http.StatusOK)
+}
+
+// GetMissingDates handles GET /api/weather/missing
+func (h *WeatherHandler) GetMissingDates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/missing").Observe(duration.Seconds())
+	}()
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		h.sendError(w, r, "station_id is required", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseDateParam(r, "from")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseDateParam(r, "to")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if from == nil || to == nil {
+		h.sendError(w, r, "from and to are required", http.StatusBadRequest)
+		return
+	}
+
+	maxDays := h.options.MaxQueryRangeDays
+	if maxDays <= 0 || maxDays > maxMissingRangeDays {
+		maxDays = maxMissingRangeDays
+	}
+
+	if err := checkDateRange(from, to, maxDays); err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dates, err := h.weatherService.GetMissingDates(ctx, stationID, *from, *to)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_MISSING_DATES_ERROR] Failed to get missing dates", logging.Fields{
+			"station_id": stationID,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/missing")
+		h.sendError(w, r, "failed to retrieve missing dates", http.StatusInternalServerError)
+		return
+	}
+
+	missing := make([]string, 0, len(dates))
+	for _, date := range dates {
+		missing = append(missing, date.Format("2006-01-02"))
+	}
+
+	response := map[string]interface{}{
+		"station_id":    stationID,
+		"from":          from.Format("2006-01-02"),
+		"to":            to.Format("2006-01-02"),
+		"missing_count": len(missing),
+		"missing_dates": missing,
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/missing", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetMovingAverage handles GET /api/weather/moving-average
+func (h *WeatherHandler) GetMovingAverage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/moving-average").Observe(duration.Seconds())
+	}()
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		h.sendError(w, r, "station_id is required", http.StatusBadRequest)
+		return
+	}
+
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "max_temp"
+	}
+	if !repository.IsValidObservationMetric(metric) {
+		h.sendError(w, r, "invalid metric, expected one of max_temp, min_temp, precip", http.StatusBadRequest)
+		return
+	}
+
+	window := 7
+	if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+		parsed, err := strconv.Atoi(windowStr)
+		if err != nil || parsed < 1 || parsed > maxMovingAverageWindow {
+			h.sendError(w, r, "invalid window, expected an integer between 1 and 365", http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+
+	from, err := parseDateParam(r, "from")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseDateParam(r, "to")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if from == nil || to == nil {
+		h.sendError(w, r, "from and to are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validateDateRange(from, to); err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	points, err := h.weatherService.GetMovingAverage(ctx, stationID, metric, window, *from, *to)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_MOVING_AVERAGE_ERROR] Failed to get moving average", logging.Fields{
+			"station_id": stationID,
+			"metric":     metric,
+			"window":     window,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/moving-average")
+		h.sendError(w, r, "failed to retrieve moving average", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"station_id": stationID,
+		"metric":     metric,
+		"window":     window,
+		"from":       from.Format("2006-01-02"),
+		"to":         to.Format("2006-01-02"),
+		"data":       points,
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/moving-average", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetDiurnalRange handles GET /api/weather/diurnal-range
+// Accepts the GET /api/weather filters; days missing either temperature are omitted
+func (h *WeatherHandler) GetDiurnalRange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/diurnal-range").Observe(duration.Seconds())
+	}()
+
+	page, limit, offset := parsePagination(r)
+
+	filter, err := h.parseObservationFilter(r)
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	ranges, total, err := h.weatherService.GetDiurnalRange(ctx, filter)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_DIURNAL_RANGE_ERROR] Failed to get diurnal ranges", logging.Fields{
+			"filter": filter,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/diurnal-range")
+		h.sendError(w, r, "failed to retrieve diurnal ranges", http.StatusInternalServerError)
+		return
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	if err := respond(h, w, r, "/api/weather/diurnal-range", ranges, pageMeta{total, page, limit, totalPages}); err != nil {
+		h.logger.Warn(ctx, "[API_GET_DIURNAL_RANGE_STREAM_ERROR] Failed to stream diurnal ranges", logging.Fields{
+			"error": err.Error(),
+		})
+	}
+}
+
+// GetHistogram handles GET /api/weather/histogram
+func (h *WeatherHandler) GetHistogram(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/histogram").Observe(duration.Seconds())
+	}()
+
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "max_temp"
+	}
+	if !repository.IsValidObservationMetric(metric) {
+		h.sendError(w, r, "invalid metric, expected one of max_temp, min_temp, precip", http.StatusBadRequest)
+		return
+	}
+
+	bins := 20
+	if binsStr := r.URL.Query().Get("bins"); binsStr != "" {
+		parsed, err := strconv.Atoi(binsStr)
+		if err != nil || parsed < 1 || parsed > maxHistogramBins {
+			h.sendError(w, r, "invalid bins, expected an integer between 1 and 100", http.StatusBadRequest)
+			return
+		}
+		bins = parsed
+	}
+
+	var filter repository.ObservationFilter
+	if stationID := r.URL.Query().Get("station_id"); stationID != "" {
+		filter.StationID = &stationID
+	}
+
+	from, err := parseDateParam(r, "from")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseDateParam(r, "to")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validateDateRange(from, to); err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.StartDate = from
+	filter.EndDate = to
+
+	histogram, err := h.weatherService.GetHistogram(ctx, metric, bins, filter)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_HISTOGRAM_ERROR] Failed to get histogram", logging.Fields{
+			"metric": metric,
+			"bins":   bins,
+			"filter": filter,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/histogram")
+		h.sendError(w, r, "failed to retrieve histogram", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"metric": metric,
+		"bins":   bins,
+		"data":   histogram,
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/histogram", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetFrostFreeSeason handles GET /api/weather/frost-free
+func (h *WeatherHandler) GetFrostFreeSeason(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/frost-free").Observe(duration.Seconds())
+	}()
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		h.sendError(w, r, "station_id is required", http.StatusBadRequest)
+		return
+	}
+
+	year, err := parseYearParam(r, "year")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if year == nil {
+		h.sendError(w, r, "year is required", http.StatusBadRequest)
+		return
+	}
+
+	season, err := h.weatherService.CalculateFrostFreeSeason(ctx, stationID, *year)
+	if err != nil {
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_GET_FROST_FREE_ERROR] Failed to calculate frost-free season", logging.Fields{
+			"station_id": stationID,
+			"year":       *year,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/frost-free")
+		h.sendError(w, r, "failed to calculate frost-free season", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/frost-free", "GET", "200")
+	h.sendJSON(w, r, season, http.StatusOK)
+}
+
+// GetDegreeDays handles GET /api/weather/degree-days
+func (h *WeatherHandler) GetDegreeDays(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/degree-days").Observe(duration.Seconds())
+	}()
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		h.sendError(w, r, "station_id is required", http.StatusBadRequest)
+		return
+	}
+
+	year, err := parseYearParam(r, "year")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if year == nil {
+		h.sendError(w, r, "year is required", http.StatusBadRequest)
+		return
+	}
+
+	base := defaultDegreeDayBase
+	if baseStr := r.URL.Query().Get("base"); baseStr != "" {
+		parsed, err := strconv.ParseFloat(baseStr, 64)
+		if err != nil || math.IsNaN(parsed) || parsed < -50 || parsed > 50 {
+			h.sendError(w, r, "invalid base, expected a temperature between -50 and 50", http.StatusBadRequest)
+			return
+		}
+		base = parsed
+	}
+
+	degreeDays, err := h.weatherService.CalculateDegreeDays(ctx, stationID, *year, base)
+	if err != nil {
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_GET_DEGREE_DAYS_ERROR] Failed to calculate degree days", logging.Fields{
+			"station_id": stationID,
+			"year":       *year,
+			"base":       base,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/degree-days")
+		h.sendError(w, r, "failed to calculate degree days", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/degree-days", "GET", "200")
+	h.sendJSON(w, r, degreeDays, http.StatusOK)
+}
+
+// GetWeatherEvents handles GET /api/weather/events
+// Lists the stations meeting every given threshold on one date, e.g. the
+// footprint of a storm (min_precip) or cold snap (min_temp_below)
+func (h *WeatherHandler) GetWeatherEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/events").Observe(duration.Seconds())
+	}()
+
+	date, err := parseDateParam(r, "date")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if date == nil {
+		h.sendError(w, r, "date is required", http.StatusBadRequest)
+		return
+	}
+
+	filter := repository.EventFilter{Date: *date}
+	thresholds := []struct {
+		name  string
+		value **float64
+	}{
+		{"min_precip", &filter.MinPrecipitationCm},
+		{"max_temp_above", &filter.MaxTempAbove},
+		{"min_temp_below", &filter.MinTempBelow},
+	}
+	for _, threshold := range thresholds {
+		if *threshold.value, err = parseFloatParam(r, threshold.name); err != nil {
+			h.sendError(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if filter.MinPrecipitationCm == nil && filter.MaxTempAbove == nil && filter.MinTempBelow == nil {
+		h.sendError(w, r, "at least one of min_precip, max_temp_above, or min_temp_below is required", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.weatherService.GetWeatherEvents(ctx, filter)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_EVENTS_ERROR] Failed to get weather events", logging.Fields{
+			"date": date.Format("2006-01-02"),
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/events")
+		h.sendError(w, r, "failed to retrieve weather events", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"date":  date.Format("2006-01-02"),
+		"total": len(events),
+		"data":  events,
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/events", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetRanking handles GET /api/weather/ranking
+func (h *WeatherHandler) GetRanking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/ranking").Observe(duration.Seconds())
+	}()
+
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "precip"
+	}
+	if !repository.IsValidRankingMetric(metric) {
+		h.sendError(w, r, "invalid metric, expected one of precip, max_temp", http.StatusBadRequest)
+		return
+	}
+
+	year, err := parseYearParam(r, "year")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if year == nil {
+		h.sendError(w, r, "year is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := 10
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > maxRankingLimit {
+			h.sendError(w, r, "invalid limit, expected an integer between 1 and 100", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	ranking, err := h.statsService.GetRanking(ctx, metric, *year, limit)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_RANKING_ERROR] Failed to get ranking", logging.Fields{
+			"metric": metric,
+			"year":   *year,
+			"limit":  limit,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/ranking")
+		h.sendError(w, r, "failed to get ranking", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/ranking", "GET", "200")
+	h.sendJSON(w, r, map[string]interface{}{
+		"metric": metric,
+		"year":   *year,
+		"data":   ranking,
+	}, http.StatusOK)
+}
+
+// GetYearOverYearChange handles GET /api/weather/yoy
+func (h *WeatherHandler) GetYearOverYearChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/yoy").Observe(duration.Seconds())
+	}()
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		h.sendError(w, r, "station_id is required", http.StatusBadRequest)
+		return
+	}
+
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "avg_max_temp"
+	}
+	if !repository.IsValidYearOverYearMetric(metric) {
+		h.sendError(w, r, "invalid metric, expected one of avg_max_temp, avg_min_temp, total_precip, avg_diurnal_range", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.statsService.GetYearOverYearChange(ctx, stationID, metric)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_YOY_ERROR] Failed to get year-over-year change", logging.Fields{
+			"station_id": stationID,
+			"metric":     metric,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/yoy")
+		h.sendError(w, r, "failed to get year-over-year change", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/yoy", "GET", "200")
+	h.sendJSON(w, r, map[string]interface{}{
+		"station_id": stationID,
+		"metric":     metric,
+		"data":       changes,
+	}, http.StatusOK)
+}
+
+// GetLongestStreak handles GET /api/weather/streak
+// Returns a station's longest run of consecutive days meeting a condition
+func (h *WeatherHandler) GetLongestStreak(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/streak").Observe(duration.Seconds())
+	}()
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		h.sendError(w, r, "station_id is required", http.StatusBadRequest)
+		return
+	}
+
+	condition := r.URL.Query().Get("condition")
+	if !repository.IsValidStreakCondition(condition) {
+		h.sendError(w, r, "invalid condition, expected one of hot, cold, wet, dry", http.StatusBadRequest)
+		return
+	}
+
+	threshold, err := parseFloatParam(r, "threshold")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if threshold == nil {
+		h.sendError(w, r, "threshold is required", http.StatusBadRequest)
+		return
+	}
+
+	streak, err := h.weatherService.GetLongestStreak(ctx, stationID, condition, *threshold)
+	if err != nil {
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_GET_STREAK_ERROR] Failed to get longest streak", logging.Fields{
+			"station_id": stationID,
+			"condition":  condition,
+			"threshold":  *threshold,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/streak")
+		h.sendError(w, r, "failed to get longest streak", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/streak", "GET", "200")
+	h.sendJSON(w, r, map[string]interface{}{
+		"station_id": stationID,
+		"condition":  condition,
+		"threshold":  *threshold,
+		"data":       streak,
+	}, http.StatusOK)
+}
+
+// GetDegreeDayAnomaly handles GET /api/weather/anomaly
+// Compares a station-year's degree-day total with the station's normal
+func (h *WeatherHandler) GetDegreeDayAnomaly(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/anomaly").Observe(duration.Seconds())
+	}()
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		h.sendError(w, r, "station_id is required", http.StatusBadRequest)
+		return
+	}
+
+	year, err := parseYearParam(r, "year")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if year == nil {
+		h.sendError(w, r, "year is required", http.StatusBadRequest)
+		return
+	}
+
+	metric := r.URL.Query().Get("metric")
+	if !repository.IsValidDegreeDayMetric(metric) {
+		h.sendError(w, r, "invalid metric, expected one of gdd, hdd, cdd", http.StatusBadRequest)
+		return
+	}
+
+	base := defaultDegreeDayBase
+	if metric == "gdd" {
+		base = defaultGrowingDegreeDayBase
+	}
+	if baseStr := r.URL.Query().Get("base"); baseStr != "" {
+		parsed, err := strconv.ParseFloat(baseStr, 64)
+		if err != nil || math.IsNaN(parsed) || parsed < -50 || parsed > 50 {
+			h.sendError(w, r, "invalid base, expected a temperature between -50 and 50", http.StatusBadRequest)
+			return
+		}
+		base = parsed
+	}
+
+	anomaly, err := h.weatherService.GetDegreeDayAnomaly(ctx, stationID, metric, *year, base)
+	if err != nil {
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_GET_ANOMALY_ERROR] Failed to calculate degree-day anomaly", logging.Fields{
+			"station_id": stationID,
+			"year":       *year,
+			"metric":     metric,
+			"base":       base,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/anomaly")
+		h.sendError(w, r, "failed to calculate degree-day anomaly", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/anomaly", "GET", "200")
+	h.sendJSON(w, r, anomaly, http.StatusOK)
+}
+
+// GetCorrelation handles GET /api/weather/correlation
+// Returns the Pearson correlation between two metrics for a station
+func (h *WeatherHandler) GetCorrelation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/correlation").Observe(duration.Seconds())
+	}()
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		h.sendError(w, r, "station_id is required", http.StatusBadRequest)
+		return
+	}
+
+	metricX := r.URL.Query().Get("x")
+	metricY := r.URL.Query().Get("y")
+	if !repository.IsValidObservationMetric(metricX) || !repository.IsValidObservationMetric(metricY) {
+		h.sendError(w, r, "invalid x or y, expected one of max_temp, min_temp, precip", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseDateParam(r, "from")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseDateParam(r, "to")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validateDateRange(from, to); err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := repository.ObservationFilter{
+		StationID: &stationID,
+		StartDate: from,
+		EndDate:   to,
+	}
+
+	correlation, err := h.weatherService.GetCorrelation(ctx, metricX, metricY, filter)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_CORRELATION_ERROR] Failed to get correlation", logging.Fields{
+			"x":      metricX,
+			"y":      metricY,
+			"filter": filter,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/correlation")
+		h.sendError(w, r, "failed to get correlation", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/correlation", "GET", "200")
+	h.sendJSON(w, r, map[string]interface{}{
+		"station_id":  stationID,
+		"x":           metricX,
+		"y":           metricY,
+		"coefficient": correlation.Coefficient,
+		"sample_size": correlation.SampleSize,
+	}, http.StatusOK)
+}
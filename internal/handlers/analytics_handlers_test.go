@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"weather-platform/internal/models"
+)
+
+// TestGetCorrelation tests metric validation and that a coefficient the
+// database leaves NULL, for fewer than two rows or a constant metric, is
+// returned as null with its sample size
+func TestGetCorrelation(t *testing.T) {
+	coefficient := 0.82
+
+	tests := []struct {
+		name            string
+		query           string
+		correlation     models.Correlation
+		want            int
+		wantCoefficient *float64
+	}{
+		{"correlated", "x=max_temp&y=min_temp", models.Correlation{Coefficient: &coefficient, SampleSize: 365}, http.StatusOK, &coefficient},
+		{"single row", "x=max_temp&y=precip", models.Correlation{SampleSize: 1}, http.StatusOK, nil},
+		{"no rows", "x=max_temp&y=precip", models.Correlation{}, http.StatusOK, nil},
+		{"constant metric", "x=precip&y=min_temp", models.Correlation{SampleSize: 30}, http.StatusOK, nil},
+		{"unknown x", "x=humidity&y=min_temp", models.Correlation{}, http.StatusBadRequest, nil},
+		{"column name as y", "x=max_temp&y=min_temperature_celsius", models.Correlation{}, http.StatusBadRequest, nil},
+		{"missing y", "x=max_temp", models.Correlation{}, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepository{correlation: tt.correlation}
+			h := newTestHandler(t, repo, DefaultOptions())
+
+			target := "/api/weather/correlation?station_id=USC00110072&" + tt.query
+			rec := route(h, http.MethodGet, target)
+			if rec.Code != tt.want {
+				t.Fatalf("GET %s status = %d, want %d", target, rec.Code, tt.want)
+			}
+			if reached := repo.calls > 0; reached != (tt.want == http.StatusOK) {
+				t.Errorf("repository reached = %v, want %v", reached, tt.want == http.StatusOK)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("response is not JSON: %v", err)
+			}
+			raw, ok := body["coefficient"]
+			if !ok {
+				t.Fatalf("response has no coefficient: %s", rec.Body)
+			}
+
+			var got *float64
+			if err := json.Unmarshal(raw, &got); err != nil {
+				t.Fatalf("coefficient %s is not a number or null", raw)
+			}
+			if (got == nil) != (tt.wantCoefficient == nil) || (got != nil && *got != *tt.wantCoefficient) {
+				t.Errorf("coefficient = %s, want %v", raw, tt.wantCoefficient)
+			}
+
+			var sampleSize int
+			if err := json.Unmarshal(body["sample_size"], &sampleSize); err != nil || sampleSize != tt.correlation.SampleSize {
+				t.Errorf("sample_size = %s, want %d", body["sample_size"], tt.correlation.SampleSize)
+			}
+		})
+	}
+}
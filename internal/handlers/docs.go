@@ -24,7 +24,7 @@ func OpenAPISpec(w http.ResponseWriter, r *http.Request) {
 			"/api/weather": map[string]interface{}{
 				"get": map[string]interface{}{
 					"summary":     "Get weather observations",
-					"description": "Retrieve weather observations with filtering and pagination",
+					"description": "Retrieve weather observations with filtering and pagination. Set Accept to text/csv or application/x-ndjson for CSV or NDJSON output. X-Total-Count and Link (rel=\"prev\"/rel=\"next\") headers describe the pagination.",
 					"parameters": []map[string]interface{}{
 						{
 							"name":        "station_id",
@@ -47,6 +47,62 @@ func OpenAPISpec(w http.ResponseWriter, r *http.Request) {
 							"required":    false,
 							"schema":      map[string]string{"type": "string", "format": "date"},
 						},
+						{
+							"name":        "created_after",
+							"in":          "query",
+							"description": "Only rows ingested after this time (RFC 3339 timestamp or YYYY-MM-DD), for incremental sync",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date-time"},
+						},
+						{
+							"name":        "has_max_temp",
+							"in":          "query",
+							"description": "false returns only days missing maximum temperature (at most one has_ filter; true is not supported)",
+							"required":    false,
+							"schema":      map[string]string{"type": "boolean"},
+						},
+						{
+							"name":        "has_min_temp",
+							"in":          "query",
+							"description": "false returns only days missing minimum temperature (at most one has_ filter; true is not supported)",
+							"required":    false,
+							"schema":      map[string]string{"type": "boolean"},
+						},
+						{
+							"name":        "has_precip",
+							"in":          "query",
+							"description": "false returns only days missing precipitation (at most one has_ filter; true is not supported)",
+							"required":    false,
+							"schema":      map[string]string{"type": "boolean"},
+						},
+						{
+							"name":        "include",
+							"in":          "query",
+							"description": "Comma-separated computed fields to add: diurnal_range (max minus min temperature, omitted when either is missing)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "fields",
+							"in":          "query",
+							"description": "Comma-separated observation fields to return, e.g. station_id,observation_date,max_temperature_celsius (default: all fields)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "format",
+							"in":          "query",
+							"description": "Response layout: json (default) or columnar, which returns {\"dates\",\"max_temp\",\"min_temp\",\"precip\"} arrays aligned by index with null for missing values; pagination is then only in the X-Total-Count and Link headers",
+							"required":    false,
+							"schema":      map[string]interface{}{"type": "string", "enum": []string{"json", "columnar"}, "default": "json"},
+						},
+						{
+							"name":        "date_format",
+							"in":          "query",
+							"description": "Observation date format: datetime (RFC 3339 timestamp, default) or date (YYYY-MM-DD). The default is set by SERVER_DATE_FORMAT",
+							"required":    false,
+							"schema":      map[string]interface{}{"type": "string", "enum": []string{"datetime", "date"}, "default": "datetime"},
+						},
 						{
 							"name":        "page",
 							"in":          "query",
@@ -77,11 +133,12 @@ func OpenAPISpec(w http.ResponseWriter, r *http.Request) {
 													"properties": map[string]interface{}{
 														"id":                        map[string]string{"type": "integer"},
 														"station_id":                map[string]string{"type": "string"},
-														"observation_date":          map[string]string{"type": "string", "format": "date-time"},
+														"observation_date":          map[string]string{"type": "string", "format": "date-time", "description": "RFC 3339 timestamp, or YYYY-MM-DD with date_format=date"},
 														"max_temperature_celsius":   map[string]interface{}{"type": "number", "nullable": true},
 														"min_temperature_celsius":   map[string]interface{}{"type": "number", "nullable": true},
 														"precipitation_cm":          map[string]interface{}{"type": "number", "nullable": true},
 														"created_at":                map[string]string{"type": "string", "format": "date-time"},
+														"diurnal_range_celsius":     map[string]interface{}{"type": "number", "nullable": true},
 													},
 												},
 											},
@@ -100,7 +157,7 @@ func OpenAPISpec(w http.ResponseWriter, r *http.Request) {
 			"/api/weather/stats": map[string]interface{}{
 				"get": map[string]interface{}{
 					"summary":     "Get weather statistics",
-					"description": "Retrieve calculated yearly statistics per station",
+					"description": "Retrieve calculated yearly statistics per station. Set Accept to text/csv or application/x-ndjson for CSV or NDJSON output. X-Total-Count and Link (rel=\"prev\"/rel=\"next\") headers describe the pagination.",
 					"parameters": []map[string]interface{}{
 						{
 							"name":        "station_id",
@@ -116,6 +173,13 @@ func OpenAPISpec(w http.ResponseWriter, r *http.Request) {
 							"required":    false,
 							"schema":      map[string]string{"type": "integer"},
 						},
+						{
+							"name":        "reliable",
+							"in":          "query",
+							"description": "Filter by is_reliable (observation count meets STATS_MIN_OBSERVATIONS)",
+							"required":    false,
+							"schema":      map[string]string{"type": "boolean"},
+						},
 						{
 							"name":        "page",
 							"in":          "query",
@@ -150,6 +214,7 @@ func OpenAPISpec(w http.ResponseWriter, r *http.Request) {
 														"avg_max_temperature_celsius":  map[string]interface{}{"type": "number", "nullable": true},
 														"avg_min_temperature_celsius":  map[string]interface{}{"type": "number", "nullable": true},
 														"total_precipitation_cm":       map[string]interface{}{"type": "number", "nullable": true},
+														"avg_diurnal_range_celsius":    map[string]interface{}{"type": "number", "nullable": true},
 														"observation_count":            map[string]string{"type": "integer"},
 														"valid_max_temp_count":         map[string]string{"type": "integer"},
 														"valid_min_temp_count":         map[string]string{"type": "integer"},
@@ -169,6 +234,1304 @@ func OpenAPISpec(w http.ResponseWriter, r *http.Request) {
 					},
 				},
 			},
+			"/api/ingestion/failures": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get failed ingestion records",
+					"description": "Retrieve input lines that failed parsing or conversion (requires ingester -persist-failures)",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Filter by weather station ID",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "page",
+							"in":          "query",
+							"description": "Page number (default: 1)",
+							"required":    false,
+							"schema":      map[string]interface{}{"type": "integer", "default": 1},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Records per page (default: 100)",
+							"required":    false,
+							"schema":      map[string]interface{}{"type": "integer", "default": 100},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Successful response",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"type": "object",
+										"properties": map[string]interface{}{
+											"data": map[string]interface{}{
+												"type": "array",
+												"items": map[string]interface{}{
+													"type": "object",
+													"properties": map[string]interface{}{
+														"id":          map[string]string{"type": "integer"},
+														"station_id":  map[string]string{"type": "string"},
+														"line_number": map[string]string{"type": "integer"},
+														"raw_line":    map[string]string{"type": "string"},
+														"reason":      map[string]string{"type": "string"},
+														"created_at":  map[string]string{"type": "string", "format": "date-time"},
+													},
+												},
+											},
+											"total":       map[string]string{"type": "integer"},
+											"page":        map[string]string{"type": "integer"},
+											"limit":       map[string]string{"type": "integer"},
+											"total_pages": map[string]string{"type": "integer"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/api/weather/compare": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Compare two stations",
+					"description": "Retrieve aligned daily values of a metric for two stations with the per-day difference (station_a - station_b). Days missing at one station return null for that side.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_a",
+							"in":          "query",
+							"description": "First weather station ID",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "station_b",
+							"in":          "query",
+							"description": "Second weather station ID",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "from",
+							"in":          "query",
+							"description": "Start date (YYYY-MM-DD)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "to",
+							"in":          "query",
+							"description": "End date (YYYY-MM-DD)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "metric",
+							"in":          "query",
+							"description": "Metric to compare: max_temp, min_temp, precip (default: max_temp)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Successful response",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+					},
+				},
+			},
+			"/api/weather/years": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "List available years",
+					"description": "Retrieve the sorted distinct years that have observations, optionally scoped to a station",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Filter by weather station ID",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Successful response",
+						},
+					},
+				},
+			},
+			"/api/stations/{station_id}/quality": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get station data quality",
+					"description": "Retrieve, per year, the ratio of valid max/min temperature and precipitation values to total observations. Ratios are null for years with no observations.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "path",
+							"description": "Weather station ID",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Successful response",
+						},
+						"404": map[string]interface{}{
+							"description": "Station not found",
+						},
+					},
+				},
+			},
+			"/api/weather/extremes": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get record extremes",
+					"description": "Retrieve the hottest day (highest max temperature), coldest day (lowest min temperature), and wettest day (highest precipitation) with station and date. Missing values are excluded.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "year",
+							"in":          "query",
+							"description": "Restrict to a year",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Restrict to a weather station ID",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Successful response",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+					},
+				},
+			},
+			"/api/weather/missing": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "List missing dates",
+					"description": "Retrieve the calendar dates within a range that have no observation for a station. The range is capped to protect against very large date series.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station ID",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "from",
+							"in":          "query",
+							"description": "Start date (YYYY-MM-DD)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "to",
+							"in":          "query",
+							"description": "End date (YYYY-MM-DD)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Successful response",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters or range too large",
+						},
+					},
+				},
+			},
+			"/api/weather/latest": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get latest observation per station",
+					"description": "Returns each station's most recent observation in a single query, keyed by station ID",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station ID; repeat to request several stations, omit for all",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "date_format",
+							"in":          "query",
+							"description": "Observation date format: datetime (RFC 3339 timestamp, default) or date (YYYY-MM-DD). The default is set by SERVER_DATE_FORMAT",
+							"required":    false,
+							"schema":      map[string]interface{}{"type": "string", "enum": []string{"datetime", "date"}, "default": "datetime"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Latest observations keyed by station ID",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid date_format",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/admin/observations/compact": map[string]interface{}{
+				"post": map[string]interface{}{
+					"summary":     "Remove duplicate observations",
+					"description": "Deletes duplicate (station_id, observation_date) rows in a single transaction, keeping the most recently created row. Requires Basic Auth.",
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Number of rows removed",
+						},
+						"401": map[string]interface{}{
+							"description": "Authentication required",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/stats/{station_id}/{year}": map[string]interface{}{
+				"patch": map[string]interface{}{
+					"summary":     "Correct statistics for a station-year",
+					"description": "Updates only the fields present in the JSON body; omitted fields are unchanged. Counts must not be negative. Requires Basic Auth.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "path",
+							"description": "Weather station ID",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "year",
+							"in":          "path",
+							"description": "Statistics year",
+							"required":    true,
+							"schema":      map[string]string{"type": "integer"},
+						},
+					},
+					"requestBody": map[string]interface{}{
+						"description": "Sparse statistics fields, e.g. {\"total_precipitation_cm\": 101.2}",
+						"required":    true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]string{"type": "object"},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Updated statistics",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid body, negative count, or constraint violation",
+						},
+						"401": map[string]interface{}{
+							"description": "Authentication required",
+						},
+						"404": map[string]interface{}{
+							"description": "No statistics for station and year",
+						},
+						"413": map[string]interface{}{
+							"description": "Request body too large",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/count": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Count weather observations",
+					"description": "Returns only the number of observations matching the same filters as /api/weather, without transferring rows",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station ID",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "start_date",
+							"in":          "query",
+							"description": "Start date (YYYY-MM-DD)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "end_date",
+							"in":          "query",
+							"description": "End date (YYYY-MM-DD)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "created_after",
+							"in":          "query",
+							"description": "Only rows ingested after this time (RFC 3339 timestamp or YYYY-MM-DD), for incremental sync",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date-time"},
+						},
+						{
+							"name":        "has_max_temp",
+							"in":          "query",
+							"description": "false returns only days missing maximum temperature (at most one has_ filter; true is not supported)",
+							"required":    false,
+							"schema":      map[string]string{"type": "boolean"},
+						},
+						{
+							"name":        "has_min_temp",
+							"in":          "query",
+							"description": "false returns only days missing minimum temperature (at most one has_ filter; true is not supported)",
+							"required":    false,
+							"schema":      map[string]string{"type": "boolean"},
+						},
+						{
+							"name":        "has_precip",
+							"in":          "query",
+							"description": "false returns only days missing precipitation (at most one has_ filter; true is not supported)",
+							"required":    false,
+							"schema":      map[string]string{"type": "boolean"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Observation count",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/moving-average": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get moving average",
+					"description": "Returns each observation's metric value and the trailing average over the last `window` non-null observations. sample_count is below window near the start of the range.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station ID",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "metric",
+							"in":          "query",
+							"description": "Metric: max_temp (default), min_temp, or precip",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "window",
+							"in":          "query",
+							"description": "Window size in observations, 1-365 (default 7)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "from",
+							"in":          "query",
+							"description": "Start date (YYYY-MM-DD)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "to",
+							"in":          "query",
+							"description": "End date (YYYY-MM-DD)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Moving average series",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/histogram": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get metric histogram",
+					"description": "Buckets non-null metric values into equal-width bins between the observed min and max using width_bucket. Empty bins are included.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "metric",
+							"in":          "query",
+							"description": "Metric: max_temp (default), min_temp, or precip",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station ID",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "bins",
+							"in":          "query",
+							"description": "Number of bins, 1-100 (default 20)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "from",
+							"in":          "query",
+							"description": "Start date (YYYY-MM-DD)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "to",
+							"in":          "query",
+							"description": "End date (YYYY-MM-DD)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Histogram bins with bin_start, bin_end, and count",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/ingestion/runs": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "List ingestion runs",
+					"description": "Returns recorded directory ingestion runs, most recent first, with start/end times, files processed, record counts, and error count",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "page",
+							"in":          "query",
+							"description": "Page number (default 1)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Records per page (default 100, max 1000)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Paginated ingestion runs",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/frost-free": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get frost-free season",
+					"description": "Returns the days between the last spring frost (before July 1) and the first autumn frost (on or after July 1), where a frost day has a minimum temperature at or below 0°C. status is normal, no_frost (whole year), or all_frost (no season).",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station ID",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "year",
+							"in":          "query",
+							"description": "Year",
+							"required":    true,
+							"schema":      map[string]string{"type": "integer"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Frost-free season",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+						"404": map[string]interface{}{
+							"description": "No minimum temperature data for station and year",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/degree-days": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get heating, cooling and growing degree days",
+					"description": "Sums max(0, base - mean) as HDD and max(0, mean - base) as CDD, where mean is the average of daily max and min temperature. GDD clamps both temperatures to between base and 30°C before taking the mean. Days missing either temperature are excluded.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station ID",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "year",
+							"in":          "query",
+							"description": "Year",
+							"required":    true,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "base",
+							"in":          "query",
+							"description": "Base temperature in °C (default 18)",
+							"required":    false,
+							"schema":      map[string]string{"type": "number"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Heating and cooling degree days",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+						"404": map[string]interface{}{
+							"description": "No days with both temperatures for station and year",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/ranking": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Rank stations by yearly statistics",
+					"description": "Returns the top stations for a year from calculated statistics, highest value first. Stations with no value for the metric are excluded; ties share a rank.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "metric",
+							"in":          "query",
+							"description": "Ranking metric: precip (total precipitation, default) or max_temp (average max temperature)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "year",
+							"in":          "query",
+							"description": "Statistics year",
+							"required":    true,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Number of stations (default 10, max 100)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Ranked stations",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/stats/export": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Export statistics as NDJSON",
+					"description": "Streams every calculated statistics row as newline-delimited JSON, ordered by station and year, for bulk loading into a data warehouse. Not paginated; the server write timeout does not apply.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Filter by weather station ID",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "year",
+							"in":          "query",
+							"description": "Filter by year",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "reliable",
+							"in":          "query",
+							"description": "Filter by is_reliable (observation count meets STATS_MIN_OBSERVATIONS)",
+							"required":    false,
+							"schema":      map[string]string{"type": "boolean"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "NDJSON stream, one statistics object per line",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/stations/missing-stats": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "List stations missing statistics",
+					"description": "Returns stations that have observations but no rows in weather_statistics, with their observation counts, to target statistics recalculation.",
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Stations lacking statistics",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/health/deep": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Dependency health check",
+					"description": "Runs every registered dependency check (e.g. Postgres) and returns a per-dependency status map. Overall status is unhealthy if a required check fails and degraded if only optional checks fail.",
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Healthy or degraded",
+						},
+						"503": map[string]interface{}{
+							"description": "A required dependency is unhealthy",
+						},
+					},
+				},
+			},
+			"/api/admin/observations/backfill": map[string]interface{}{
+				"post": map[string]interface{}{
+					"summary":     "Backfill missing dates",
+					"description": "Inserts observations with all-null values for every calendar date in the range that has no row for the station, so downstream queries see a dense daily series. Existing rows are untouched. Requires Basic Auth.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station ID",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "from",
+							"in":          "query",
+							"description": "First date (YYYY-MM-DD)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "to",
+							"in":          "query",
+							"description": "Last date (YYYY-MM-DD), not in the future",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Number of rows inserted",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters, range too wide, or to in the future",
+						},
+						"401": map[string]interface{}{
+							"description": "Authentication required",
+						},
+						"404": map[string]interface{}{
+							"description": "Station not found",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/stations": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "List weather stations",
+					"description": "Returns a page of stations ordered by station ID. Like the other paginated lists, the response carries X-Total-Count with the total number of stations and a Link header with rel=\"prev\"/rel=\"next\" page URLs.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "page",
+							"in":          "query",
+							"description": "Page number (default: 1)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Records per page (default: 100, max: 1000)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Paginated list of stations",
+						},
+						"406": map[string]interface{}{
+							"description": "Unsupported Accept header",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/stations/{station_id}/monthly-counts": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Observation counts by month",
+					"description": "Returns the number of stored observations in each of the 12 months of a year for a station, zero-filled, for calendar-style completeness views.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "path",
+							"description": "Weather station identifier",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "year",
+							"in":          "query",
+							"description": "Year to count",
+							"required":    true,
+							"schema":      map[string]string{"type": "integer"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Twelve monthly counts",
+						},
+						"400": map[string]interface{}{
+							"description": "Missing or invalid year",
+						},
+						"404": map[string]interface{}{
+							"description": "Station not found",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/{station_id}/{date}/history": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get observation history",
+					"description": "Returns the current observation for a station and date together with its previous values, most recent change first. A history entry is recorded whenever a re-import or correction changes a value",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "path",
+							"description": "Station identifier",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "date",
+							"in":          "path",
+							"description": "Observation date (YYYY-MM-DD)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "date_format",
+							"in":          "query",
+							"description": "Observation date format: datetime (RFC 3339 timestamp, default) or date (YYYY-MM-DD). The default is set by SERVER_DATE_FORMAT",
+							"required":    false,
+							"schema":      map[string]interface{}{"type": "string", "enum": []string{"datetime", "date"}, "default": "datetime"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Current observation and its change history",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid date or date_format",
+						},
+						"404": map[string]interface{}{
+							"description": "Observation not found",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/events": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Find stations with coincident extreme events",
+					"description": "Returns every station whose observation on the given date meets all supplied thresholds, with the station's state, ordered by station. At least one threshold is required. Missing values never match",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "date",
+							"in":          "query",
+							"description": "Observation date (YYYY-MM-DD)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "min_precip",
+							"in":          "query",
+							"description": "Precipitation at or above this value (cm)",
+							"required":    false,
+							"schema":      map[string]string{"type": "number"},
+						},
+						{
+							"name":        "max_temp_above",
+							"in":          "query",
+							"description": "Maximum temperature strictly above this value (°C)",
+							"required":    false,
+							"schema":      map[string]string{"type": "number"},
+						},
+						{
+							"name":        "min_temp_below",
+							"in":          "query",
+							"description": "Minimum temperature strictly below this value (°C)",
+							"required":    false,
+							"schema":      map[string]string{"type": "number"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Matching stations with their observations",
+						},
+						"400": map[string]interface{}{
+							"description": "Missing date or threshold, or invalid parameter",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/diurnal-range": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get daily diurnal temperature range",
+					"description": "Returns max minus min temperature per observation, newest first, with the same filters and pagination as /api/weather. Days missing either temperature are excluded. Set Accept to text/csv or application/x-ndjson for CSV or NDJSON output.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Filter by weather station ID",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "start_date",
+							"in":          "query",
+							"description": "Filter by start date (YYYY-MM-DD)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "end_date",
+							"in":          "query",
+							"description": "Filter by end date (YYYY-MM-DD)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "page",
+							"in":          "query",
+							"description": "Page number (default: 1)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Records per page (default: 100)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Paginated daily ranges",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameter",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/stats/global": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get global dataset stats",
+					"description": "Returns the station count, observation count and first and last observation dates in a single query. The observation count is the planner's row estimate (refreshed by ANALYZE) unless exact=true; observation_count_exact reports which was returned",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "exact",
+							"in":          "query",
+							"description": "Count observations exactly with a full table scan (default: false)",
+							"required":    false,
+							"schema":      map[string]string{"type": "boolean"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Global stats",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid exact",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/stations/search": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Search stations",
+					"description": "Returns stations whose ID contains q, ignoring case, for type-ahead pickers. IDs starting with q rank first, then shorter IDs, then alphabetical. % and _ in q match literally",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "q",
+							"in":          "query",
+							"description": "Text to find in station IDs (at most 64 characters)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Maximum matches (default: 10, capped at 50)",
+							"required":    false,
+							"schema":      map[string]string{"type": "integer"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Matching stations with q, limit and total",
+						},
+						"400": map[string]interface{}{
+							"description": "Missing or too long q, or invalid limit",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/yoy": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Year-over-year change",
+					"description": "Returns a station's yearly statistics metric, oldest first, with the change from the previous year that has statistics. The first year and years where either value is null have a null delta.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station identifier",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "metric",
+							"in":          "query",
+							"description": "Statistics metric: avg_max_temp (default), avg_min_temp, total_precip or avg_diurnal_range",
+							"required":    false,
+							"schema":      map[string]string{"type": "string"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Yearly values and deltas",
+						},
+						"400": map[string]interface{}{
+							"description": "Missing station_id or invalid metric",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/ingestion/run": map[string]interface{}{
+				"post": map[string]interface{}{
+					"summary":     "Start a directory ingestion",
+					"description": "Ingests every data file in a directory under SERVER_INGESTION_ROOT in the background and returns the job to poll. Only one job runs at a time. Requires authentication.",
+					"requestBody": map[string]interface{}{
+						"description": "JSON object with data_dir (relative to the ingestion root, required) and batch_size (1-10000, default 1000)",
+						"required":    true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]string{"type": "object"},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"202": map[string]interface{}{
+							"description": "Job started; Location points at its status",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid body, or data_dir missing, not a directory, or outside the ingestion root",
+						},
+						"401": map[string]interface{}{
+							"description": "Authentication required",
+						},
+						"404": map[string]interface{}{
+							"description": "API ingestion disabled",
+						},
+						"409": map[string]interface{}{
+							"description": "Another ingestion job is running",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/ingestion/run/{id}": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get ingestion job status",
+					"description": "Returns a job started by POST /api/ingestion/run: status (running, completed or failed), timing, and the ingestion result once finished. Jobs are kept in memory and forgotten on restart.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Job ID returned when the run started",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Job status",
+						},
+						"404": map[string]interface{}{
+							"description": "Job not found",
+						},
+					},
+				},
+			},
+			"/api/stations/{station_id}/normals": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Climate normals",
+					"description": "Returns 366 entries, one per calendar day including 29 February, with the station's average max/min temperature and mean precipitation across every year with observations on that day, and the number of years contributing to each value. Days without observations have null averages.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "path",
+							"description": "Weather station identifier",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Per-day normals",
+						},
+						"404": map[string]interface{}{
+							"description": "Station not found",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/streak": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Longest streak",
+					"description": "Returns a station's longest run of consecutive days meeting a condition: hot (max temperature above threshold), cold (min temperature below threshold), wet (precipitation above threshold) or dry (precipitation at or below threshold). A missing day or missing value breaks the run; ties return the earliest run. With no qualifying day the length is 0 and the dates are null.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station identifier",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "condition",
+							"in":          "query",
+							"description": "Condition: hot, cold, wet or dry",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "threshold",
+							"in":          "query",
+							"description": "Threshold in °C for hot/cold or cm for wet/dry",
+							"required":    true,
+							"schema":      map[string]string{"type": "number"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Longest streak with length, start_date and end_date",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+						"404": map[string]interface{}{
+							"description": "Station not found",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/correlation": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Metric correlation",
+					"description": "Returns the Pearson correlation coefficient between two metrics for a station, computed with SQL corr() over the days with both values, and the number of such days (sample_size). The coefficient is null when fewer than two days have both values or either metric is constant.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Weather station identifier",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "x",
+							"in":          "query",
+							"description": "First metric: max_temp, min_temp or precip",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "y",
+							"in":          "query",
+							"description": "Second metric: max_temp, min_temp or precip",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "from",
+							"in":          "query",
+							"description": "Start date (YYYY-MM-DD, inclusive)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+						{
+							"name":        "to",
+							"in":          "query",
+							"description": "End date (YYYY-MM-DD, inclusive)",
+							"required":    false,
+							"schema":      map[string]string{"type": "string", "format": "date"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Correlation coefficient and sample size",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid parameters",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
+			"/api/weather/anomaly": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Degree-day anomaly versus the station normal",
+					"description": "Compares a station-year's degree-day total with the station's normal, the mean total of its other years with at least 300 days having both temperatures. Returns absolute and percent differences. When the year has fewer than 300 such days or fewer than 10 other years qualify, sufficient_data is false, reason explains the shortfall and the differences are null",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "station_id",
+							"in":          "query",
+							"description": "Station identifier",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "year",
+							"in":          "query",
+							"description": "Year to compare",
+							"required":    true,
+							"schema":      map[string]string{"type": "integer"},
+						},
+						{
+							"name":        "metric",
+							"in":          "query",
+							"description": "Degree-day metric: gdd (growing), hdd (heating) or cdd (cooling)",
+							"required":    true,
+							"schema":      map[string]string{"type": "string"},
+						},
+						{
+							"name":        "base",
+							"in":          "query",
+							"description": "Base temperature in °C between -50 and 50 (default: 10 for gdd, 18 for hdd and cdd)",
+							"required":    false,
+							"schema":      map[string]string{"type": "number"},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Anomaly, or sufficient_data false with a reason",
+						},
+						"400": map[string]interface{}{
+							"description": "Missing or invalid parameter",
+						},
+						"404": map[string]interface{}{
+							"description": "Station not found",
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+						},
+					},
+				},
+			},
 			"/health": map[string]interface{}{
 				"get": map[string]interface{}{
 					"summary":     "Health check",
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"weather-platform/internal/models"
+	"weather-platform/internal/repository"
+	"weather-platform/internal/services"
+	"weather-platform/pkg/logging"
+	"weather-platform/pkg/metrics"
+)
+
+// testMetrics is shared because collectors register globally and can only be created once
+var testMetrics = metrics.NewCollector("handlers_test")
+
+// stubRepository serves handler tests without a database
+// Only the methods below are implemented; any other call panics through the
+// nil embedded interface, which flags a request that should have been rejected
+type stubRepository struct {
+	repository.WeatherRepository
+
+	// calls counts the implemented repository methods reached
+	calls int
+
+	// stationIDs lists the stations GetStation finds
+	stationIDs []string
+
+	// err fails the data methods below when set
+	err error
+
+	// correlation is returned by GetCorrelation
+	correlation models.Correlation
+}
+
+func (r *stubRepository) GetStation(ctx context.Context, stationID string) (*models.WeatherStation, error) {
+	if !slices.Contains(r.stationIDs, stationID) {
+		return nil, &repository.NotFoundError{Resource: "station", ID: stationID}
+	}
+	return &models.WeatherStation{StationID: stationID}, nil
+}
+
+func (r *stubRepository) GetClimateNormals(ctx context.Context, stationID string) ([]*models.ClimateNormal, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var normals []*models.ClimateNormal
+	for day := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC); day.Year() == 2000; day = day.AddDate(0, 0, 1) {
+		normals = append(normals, &models.ClimateNormal{Month: int(day.Month()), Day: day.Day()})
+	}
+	return normals, nil
+}
+
+func (r *stubRepository) GetCorrelation(ctx context.Context, metricX, metricY string, filter repository.ObservationFilter) (*models.Correlation, error) {
+	r.calls++
+	return &r.correlation, r.err
+}
+
+func (r *stubRepository) GetObservations(ctx context.Context, filter repository.ObservationFilter) ([]*models.WeatherObservation, int, error) {
+	r.calls++
+	return nil, 0, nil
+}
+
+func (r *stubRepository) GetMissingDates(ctx context.Context, stationID string, from, to time.Time) ([]time.Time, error) {
+	r.calls++
+	return nil, nil
+}
+
+// newTestHandler creates a handler backed by repo with the given options
+func newTestHandler(t *testing.T, repo repository.WeatherRepository, opts Options) *WeatherHandler {
+	t.Helper()
+
+	logger := logging.NewStructuredLogger("handlers-test", "test", logging.ErrorLevel)
+	logger.SetOutput(io.Discard)
+
+	h := NewWeatherHandler(
+		services.NewWeatherService(repo, logger, testMetrics),
+		services.NewStatisticsService(repo, logger, testMetrics),
+		services.NewIngestionService(repo, logger, testMetrics),
+		logger,
+		testMetrics,
+	)
+	h.SetOptions(opts)
+	return h
+}
+
+// route sends a request through the handler's registered routes, so path
+// variables and method matching apply as in the server
+func route(h *WeatherHandler, method, target string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	h.RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+// serve runs handler for a GET of target and returns the response status
+func serve(handler http.HandlerFunc, target string) int {
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec.Code
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"weather-platform/pkg/logging"
+)
+
+// Health statuses reported by /health/deep
+const (
+	healthStatusHealthy   = "healthy"
+	healthStatusDegraded  = "degraded"
+	healthStatusUnhealthy = "unhealthy"
+)
+
+// defaultHealthCheckTimeout bounds each dependency check during /health/deep
+const defaultHealthCheckTimeout = 2 * time.Second
+
+// HealthChecker reports the health of a single dependency (database, replica, cache)
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// healthCheckFunc adapts a function to HealthChecker
+type healthCheckFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (c healthCheckFunc) Name() string                    { return c.name }
+func (c healthCheckFunc) Check(ctx context.Context) error { return c.fn(ctx) }
+
+// NewHealthCheck wraps a check function, e.g. a database ping, as a HealthChecker
+func NewHealthCheck(name string, fn func(ctx context.Context) error) HealthChecker {
+	return healthCheckFunc{name: name, fn: fn}
+}
+
+// DependencyHealth is the result of one dependency check
+type DependencyHealth struct {
+	Status     string `json:"status"`
+	Required   bool   `json:"required"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// HealthReport is the aggregated result of all registered checks
+// Status is unhealthy if any required check fails, degraded if only optional checks fail
+type HealthReport struct {
+	Status    string                      `json:"status"`
+	Timestamp string                      `json:"timestamp"`
+	Checks    map[string]DependencyHealth `json:"checks"`
+}
+
+type registeredCheck struct {
+	checker  HealthChecker
+	required bool
+}
+
+// HealthRegistry holds the dependency checks run by /health/deep
+type HealthRegistry struct {
+	mu      sync.RWMutex
+	checks  []registeredCheck
+	timeout time.Duration
+}
+
+// NewHealthRegistry creates an empty registry; a timeout <= 0 uses the default
+func NewHealthRegistry(timeout time.Duration) *HealthRegistry {
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	return &HealthRegistry{timeout: timeout}
+}
+
+// Register adds a dependency check; failures of required checks make the service unhealthy
+func (r *HealthRegistry) Register(checker HealthChecker, required bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, registeredCheck{checker: checker, required: required})
+}
+
+// Run executes all checks concurrently, each bounded by the registry timeout
+func (r *HealthRegistry) Run(ctx context.Context) HealthReport {
+	r.mu.RLock()
+	checks := append([]registeredCheck(nil), r.checks...)
+	r.mu.RUnlock()
+
+	results := make([]DependencyHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check registeredCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, r.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.checker.Check(checkCtx)
+
+			result := DependencyHealth{
+				Status:     healthStatusHealthy,
+				Required:   check.required,
+				DurationMS: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				result.Status = healthStatusUnhealthy
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := HealthReport{
+		Status:    healthStatusHealthy,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Checks:    make(map[string]DependencyHealth, len(checks)),
+	}
+	for i, check := range checks {
+		result := results[i]
+		report.Checks[check.checker.Name()] = result
+
+		if result.Status == healthStatusHealthy {
+			continue
+		}
+		if result.Required {
+			report.Status = healthStatusUnhealthy
+		} else if report.Status == healthStatusHealthy {
+			report.Status = healthStatusDegraded
+		}
+	}
+
+	return report
+}
+
+// SetHealthRegistry sets the dependency checks reported by /health/deep
+func (h *WeatherHandler) SetHealthRegistry(registry *HealthRegistry) {
+	h.health = registry
+}
+
+// DeepHealthCheck handles GET /health/deep
+// Responds 503 when any required dependency is unhealthy, 200 otherwise
+func (h *WeatherHandler) DeepHealthCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	registry := h.health
+	if registry == nil {
+		registry = NewHealthRegistry(0)
+	}
+
+	report := registry.Run(ctx)
+
+	statusCode := http.StatusOK
+	if report.Status == healthStatusUnhealthy {
+		statusCode = http.StatusServiceUnavailable
+		h.logger.Warn(ctx, "[HEALTH_DEEP_UNHEALTHY] Required dependency check failed", logging.Fields{
+			"checks": report.Checks,
+		})
+	}
+
+	h.sendJSON(w, r, report, statusCode)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestHealthRegistryRun tests overall status aggregation
+func TestHealthRegistryRun(t *testing.T) {
+	ok := NewHealthCheck("ok", func(ctx context.Context) error { return nil })
+	failing := NewHealthCheck("failing", func(ctx context.Context) error { return errors.New("down") })
+
+	tests := []struct {
+		name       string
+		register   func(r *HealthRegistry)
+		wantStatus string
+	}{
+		{"no checks", func(r *HealthRegistry) {}, healthStatusHealthy},
+		{"all healthy", func(r *HealthRegistry) { r.Register(ok, true) }, healthStatusHealthy},
+		{"optional failure", func(r *HealthRegistry) {
+			r.Register(ok, true)
+			r.Register(failing, false)
+		}, healthStatusDegraded},
+		{"required failure", func(r *HealthRegistry) {
+			r.Register(ok, false)
+			r.Register(failing, true)
+		}, healthStatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewHealthRegistry(0)
+			tt.register(registry)
+
+			report := registry.Run(context.Background())
+
+			if report.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			if failed, ok := report.Checks["failing"]; ok && failed.Error != "down" {
+				t.Errorf("failing check Error = %q, want %q", failed.Error, "down")
+			}
+		})
+	}
+}
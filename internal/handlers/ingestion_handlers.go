@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"weather-platform/internal/repository"
+	"weather-platform/internal/services"
+	"weather-platform/pkg/logging"
+)
+
+// GetFailedRecords handles GET /api/ingestion/failures
+func (h *WeatherHandler) GetFailedRecords(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/ingestion/failures").Observe(duration.Seconds())
+	}()
+
+	// Parse query parameters
+	stationID := r.URL.Query().Get("station_id")
+	page, limit, offset := parsePagination(r)
+
+	// Build filter
+	filter := repository.FailedRecordFilter{
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	if stationID != "" {
+		filter.StationID = &stationID
+	}
+
+	// Get failed records
+	records, total, err := h.ingestionService.ListFailedRecords(ctx, filter)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_FAILURES_ERROR] Failed to get failed records", logging.Fields{
+			"filter": filter,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/ingestion/failures")
+		h.sendError(w, r, "failed to retrieve failed records", http.StatusInternalServerError)
+		return
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	response := PaginatedResponse{
+		Data:       records,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}
+
+	h.metrics.RecordAPIRequest("/api/ingestion/failures", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetIngestionRuns handles GET /api/ingestion/runs
+func (h *WeatherHandler) GetIngestionRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/ingestion/runs").Observe(duration.Seconds())
+	}()
+
+	page, limit, offset := parsePagination(r)
+
+	runs, total, err := h.ingestionService.ListIngestionRuns(ctx, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_INGESTION_RUNS_ERROR] Failed to get ingestion runs", logging.Fields{
+			"page":  page,
+			"limit": limit,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/ingestion/runs")
+		h.sendError(w, r, "failed to retrieve ingestion runs", http.StatusInternalServerError)
+		return
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	response := PaginatedResponse{
+		Data:       runs,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}
+
+	h.metrics.RecordAPIRequest("/api/ingestion/runs", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// maxIngestionRunBatchSize bounds batch_size for API-triggered ingestion runs
+const maxIngestionRunBatchSize = 10000
+
+// ingestionRunRequest is the body of POST /api/ingestion/run
+type ingestionRunRequest struct {
+	DataDir   string `json:"data_dir"`
+	BatchSize int    `json:"batch_size"`
+}
+
+// StartIngestionRun handles POST /api/ingestion/run
+// Starts a background directory ingestion and returns 202 with the job to poll
+func (h *WeatherHandler) StartIngestionRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/ingestion/run").Observe(duration.Seconds())
+	}()
+
+	if h.options.IngestionRoot == "" {
+		h.sendError(w, r, "API ingestion is disabled, set SERVER_INGESTION_ROOT to enable it", http.StatusNotFound)
+		return
+	}
+
+	var req ingestionRunRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.sendError(w, r, fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.sendError(w, r, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.DataDir == "" {
+		h.sendError(w, r, "data_dir is required", http.StatusBadRequest)
+		return
+	}
+	if req.BatchSize == 0 {
+		req.BatchSize = 1000
+	}
+	if req.BatchSize < 1 || req.BatchSize > maxIngestionRunBatchSize {
+		h.sendError(w, r, fmt.Sprintf("invalid batch_size, expected an integer between 1 and %d", maxIngestionRunBatchSize), http.StatusBadRequest)
+		return
+	}
+
+	dataDir, err := services.ResolveDataDir(h.options.IngestionRoot, req.DataDir)
+	if err != nil {
+		h.logger.Warn(ctx, "[API_INGESTION_RUN_REJECTED] Rejected ingestion data_dir", logging.Fields{
+			"data_dir": req.DataDir,
+			"reason":   err.Error(),
+		})
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.ingestionService.StartDirectoryIngestion(ctx, dataDir, req.BatchSize)
+	if err != nil {
+		if errors.Is(err, services.ErrIngestionJobRunning) {
+			h.sendError(w, r, err.Error(), http.StatusConflict)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_INGESTION_RUN_ERROR] Failed to start ingestion run", logging.Fields{
+			"data_dir": dataDir,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/ingestion/run")
+		h.sendError(w, r, "failed to start ingestion run", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", "/api/ingestion/run/"+job.ID)
+	h.metrics.RecordAPIRequest("/api/ingestion/run", "POST", "202")
+	h.sendJSON(w, r, job, http.StatusAccepted)
+}
+
+// GetIngestionJob handles GET /api/ingestion/run/{id}
+func (h *WeatherHandler) GetIngestionJob(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/ingestion/run/{id}").Observe(duration.Seconds())
+	}()
+
+	id := mux.Vars(r)["id"]
+	job, ok := h.ingestionService.GetIngestionJob(id)
+	if !ok {
+		h.sendError(w, r, fmt.Sprintf("ingestion job %s not found", id), http.StatusNotFound)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/ingestion/run/{id}", "GET", "200")
+	h.sendJSON(w, r, job, http.StatusOK)
+}
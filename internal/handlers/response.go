@@ -0,0 +1,741 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// pageMeta holds the pagination fields written after a streamed data array
+type pageMeta struct {
+	Total      int
+	Page       int
+	Limit      int
+	TotalPages int
+}
+
+// streamPaginatedJSON writes a PaginatedResponse-shaped body, encoding the data
+// array one element at a time so a full page is never buffered in memory.
+// Each element is passed through shape before encoding; pretty indents the
+// body the same way sendJSON does.
+// Once the header is written errors can only truncate the body, so callers log them.
+func streamPaginatedJSON[T any](w http.ResponseWriter, items []T, meta pageMeta, shape func(interface{}) (interface{}, error), statusCode int, pretty bool) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	open, separator, closeData, metaFormat := `{"data":[`, ",", "]", `,"total":%d,"page":%d,"limit":%d,"total_pages":%d}`
+	if pretty {
+		open, separator, closeData = "{\n  \"data\": [\n    ", ",\n    ", "\n  ]"
+		metaFormat = ",\n  \"total\": %d,\n  \"page\": %d,\n  \"limit\": %d,\n  \"total_pages\": %d\n}"
+		if len(items) == 0 {
+			open, closeData = "{\n  \"data\": [", "]"
+		}
+	}
+
+	if _, err := io.WriteString(w, open); err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+		}
+
+		shaped, err := shape(item)
+		if err != nil {
+			return err
+		}
+
+		var encoded []byte
+		if pretty {
+			encoded, err = json.MarshalIndent(shaped, "    ", "  ")
+		} else {
+			encoded, err = json.Marshal(shaped)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(encoded); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, closeData+metaFormat+"\n", meta.Total, meta.Page, meta.Limit, meta.TotalPages)
+	return err
+}
+
+// responseShaping holds the formatting options applied to a response while
+// it is encoded: measurement rounding, explicit nulls and field projection
+type responseShaping struct {
+	temperaturePrecision   int
+	precipitationPrecision int
+	explicitNulls          bool
+
+	// fields limits the outermost object to these keys; nil keeps every key
+	fields []string
+}
+
+// shaping returns the configured response shaping, with explicit nulls as given
+func (h *WeatherHandler) shaping(explicitNulls bool) *responseShaping {
+	return &responseShaping{
+		temperaturePrecision:   h.options.TemperaturePrecision,
+		precipitationPrecision: h.options.PrecipitationPrecision,
+		explicitNulls:          explicitNulls,
+	}
+}
+
+// shape wraps data so the shaping is applied when it is marshaled, in the
+// same pass that encodes it; struct fields keep their declaration order
+// When shaping would change nothing data is returned as is.
+func (s *responseShaping) shape(data interface{}) (interface{}, error) {
+	if s.temperaturePrecision < 0 && s.precipitationPrecision < 0 && !s.explicitNulls && s.fields == nil {
+		return data, nil
+	}
+	return shapedValue{value: data, shaping: s}, nil
+}
+
+// keeps reports whether field projection keeps key
+func (s *responseShaping) keeps(key string) bool {
+	return s.fields == nil || slices.Contains(s.fields, key)
+}
+
+// shapeResponse applies response formatting options (currently float
+// rounding) without touching the source values
+func (h *WeatherHandler) shapeResponse(data interface{}) (interface{}, error) {
+	return h.shaping(false).shape(data)
+}
+
+// shapeGeneric is shapeResponse for callers that read fields from the shaped
+// value: it converts data to its generic JSON form
+func (h *WeatherHandler) shapeGeneric(data interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return h.shaping(false).roundFloats(generic, ""), nil
+}
+
+// shaper returns the shaping function for a request's responses:
+// shapeResponse, plus every omitted field written as null when
+// explicitNulls is set
+func (h *WeatherHandler) shaper(r *http.Request) func(interface{}) (interface{}, error) {
+	return h.shaping(h.explicitNulls(r)).shape
+}
+
+// objectShaper is shaper for callers that read fields from shaped values,
+// such as CSV output: values always come back in their generic JSON form
+func (h *WeatherHandler) objectShaper(r *http.Request) func(interface{}) (interface{}, error) {
+	explicitNulls := h.explicitNulls(r)
+
+	return func(data interface{}) (interface{}, error) {
+		shaped, err := h.shapeGeneric(data)
+		if err != nil {
+			return nil, err
+		}
+		if explicitNulls {
+			fillOmitted(shaped, reflect.ValueOf(data))
+		}
+		return shaped, nil
+	}
+}
+
+// Missing value policies accepted by the nulls query parameter
+const (
+	nullsOmit     = "omit"
+	nullsExplicit = "explicit"
+)
+
+// explicitNulls reports whether missing values should be written as null
+// instead of leaving their keys out: the nulls query parameter when it is
+// omit or explicit, otherwise Options.ExplicitNulls
+func (h *WeatherHandler) explicitNulls(r *http.Request) bool {
+	switch r.URL.Query().Get("nulls") {
+	case nullsExplicit:
+		return true
+	case nullsOmit:
+		return false
+	default:
+		return h.options.ExplicitNulls
+	}
+}
+
+// roundFloats rounds fractional temperatures and precipitation amounts in a
+// generic JSON value
+// key is the nearest enclosing object key and selects the precision
+func (s *responseShaping) roundFloats(value interface{}, key string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = s.roundFloats(child, k)
+		}
+		return v
+
+	case []interface{}:
+		for i, child := range v {
+			v[i] = s.roundFloats(child, key)
+		}
+		return v
+
+	case json.Number:
+		// Integers (ids, counts, years) are passed through untouched
+		if _, err := v.Int64(); err == nil {
+			return v
+		}
+
+		precision, ok := s.precisionFor(key)
+		if !ok || precision < 0 {
+			return v
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return v
+		}
+		return roundTo(f, precision)
+
+	default:
+		return v
+	}
+}
+
+// precisionFor returns the decimal places for values under key: temperatures
+// (keys ending in _celsius) and precipitation amounts (keys ending in _cm)
+// Other values, such as coefficients, ratios and percentages, are not
+// measurements in a unit and are never rounded
+func (s *responseShaping) precisionFor(key string) (int, bool) {
+	switch {
+	case strings.HasSuffix(key, "_celsius"):
+		return s.temperaturePrecision, true
+	case strings.HasSuffix(key, "_cm"):
+		return s.precipitationPrecision, true
+	default:
+		return 0, false
+	}
+}
+
+// roundTo rounds f to the given number of decimal places
+func roundTo(f float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(f*scale) / scale
+}
+
+// Response formats selected by content negotiation
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+
+	// formatColumnar is selected with ?format=columnar rather than Accept
+	formatColumnar = "columnar"
+)
+
+// mediaTypeFormats maps supported Accept media types to response formats
+var mediaTypeFormats = map[string]string{
+	"application/json":     formatJSON,
+	"application/*":        formatJSON,
+	"*/*":                  formatJSON,
+	"text/csv":             formatCSV,
+	"application/x-ndjson": formatNDJSON,
+}
+
+// negotiateFormat picks the response format from an Accept header
+// Media types are tried in descending q order; an empty header selects JSON.
+// Returns false when no listed media type is supported.
+func negotiateFormat(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return formatJSON, true
+	}
+
+	type candidate struct {
+		format string
+		q      float64
+	}
+
+	var best *candidate
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(name) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		format, ok := mediaTypeFormats[mediaType]
+		if !ok || q <= 0 {
+			continue
+		}
+		if best == nil || q > best.q {
+			best = &candidate{format: format, q: q}
+		}
+	}
+
+	if best == nil {
+		return "", false
+	}
+	return best.format, true
+}
+
+// respond writes a page of list results in the format negotiated from the
+// request's Accept header: the paginated JSON envelope (default), CSV with a
+// header row, or NDJSON with one object per line. Items are shaped like JSON responses.
+// The request metric is recorded under route. Returns an error only after the
+// header has been written, so callers log it.
+func respond[T any](h *WeatherHandler, w http.ResponseWriter, r *http.Request, route string, items []T, meta pageMeta) error {
+	return respondFields(h, w, r, route, items, meta, nil)
+}
+
+// respondFields is respond limited to the given JSON fields of each item
+// (nil writes every field). CSV columns keep the item type's declaration order.
+func respondFields[T any](h *WeatherHandler, w http.ResponseWriter, r *http.Request, route string, items []T, meta pageMeta, fields []string) error {
+	format, ok := negotiateFormat(r.Header.Get("Accept"))
+	if !ok {
+		h.sendError(w, r, "unsupported Accept header, expected application/json, text/csv, or application/x-ndjson", http.StatusNotAcceptable)
+		return nil
+	}
+
+	shaping := h.shaping(h.explicitNulls(r))
+	if len(fields) > 0 {
+		shaping.fields = fields
+	}
+	shape := shaping.shape
+	if format == formatCSV {
+		shape = h.objectShaper(r)
+	}
+
+	if limit := h.options.MaxResponseBytes; limit > 0 {
+		if estimate := estimateResponseBytes(items, shape); estimate > limit {
+			h.metrics.RecordAPIError("response_too_large", route)
+			h.sendError(w, r, fmt.Sprintf("response of about %d bytes exceeds the %d byte limit, narrow the filter or lower limit", estimate, limit), http.StatusRequestEntityTooLarge)
+			return nil
+		}
+
+		guard := newSizeLimitedWriter(w, limit)
+		defer func() {
+			if guard.exceeded {
+				h.metrics.RecordAPIError("response_truncated", route)
+			}
+		}()
+		w = guard
+	}
+
+	h.metrics.RecordAPIRequest(route, r.Method, "200")
+	setPaginationHeaders(w, r, meta)
+
+	switch format {
+	case formatCSV:
+		columns := jsonFieldNames(reflect.TypeOf((*T)(nil)).Elem())
+		if len(fields) > 0 {
+			columns = slices.DeleteFunc(columns, func(column string) bool {
+				return !slices.Contains(fields, column)
+			})
+		}
+		return writeCSVColumns(w, items, columns, shape)
+	case formatNDJSON:
+		return writeNDJSON(w, items, shape)
+	default:
+		return streamPaginatedJSON(w, items, meta, shape, http.StatusOK, h.prettyJSON(r))
+	}
+}
+
+// responseTruncatedTrailer is the HTTP trailer set when a streamed response
+// is cut off at Options.MaxResponseBytes
+const responseTruncatedTrailer = "X-Response-Truncated"
+
+// errResponseTooLarge is returned by writes past Options.MaxResponseBytes
+var errResponseTooLarge = errors.New("response exceeds the maximum response size")
+
+// sizeLimitedWriter counts the body bytes written through it and refuses any
+// write that would take the total past max
+// The first refused write sets the X-Response-Truncated trailer, so clients
+// can tell a cut-off body from a complete one; the body itself is left as is.
+type sizeLimitedWriter struct {
+	http.ResponseWriter
+	max      int64
+	written  int64
+	exceeded bool
+}
+
+// newSizeLimitedWriter wraps w and declares the truncation trailer, which
+// must be announced before the header is written
+func newSizeLimitedWriter(w http.ResponseWriter, max int64) *sizeLimitedWriter {
+	w.Header().Add("Trailer", responseTruncatedTrailer)
+	return &sizeLimitedWriter{ResponseWriter: w, max: max}
+}
+
+func (w *sizeLimitedWriter) Write(p []byte) (int, error) {
+	if w.exceeded {
+		return 0, errResponseTooLarge
+	}
+	if w.written+int64(len(p)) > w.max {
+		w.exceeded = true
+		w.Header().Set(responseTruncatedTrailer, fmt.Sprintf("response exceeded %d bytes after %d", w.max, w.written))
+		return 0, errResponseTooLarge
+	}
+
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (Flush, deadlines)
+func (w *sizeLimitedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// estimateResponseBytes approximates the encoded size of items from the size
+// of the first shaped item; it is only a guide for rejecting responses before
+// any byte is written, so shaping errors estimate zero and are left to the writer
+// Only the first item is sampled, so pages whose rows have more or fewer null
+// fields than it are misjudged, and pretty-printing, the pagination envelope
+// and CSV framing are not counted; sizeLimitedWriter enforces the actual limit.
+func estimateResponseBytes[T any](items []T, shape func(interface{}) (interface{}, error)) int64 {
+	if len(items) == 0 {
+		return 0
+	}
+
+	shaped, err := shape(items[0])
+	if err != nil {
+		return 0
+	}
+	encoded, err := json.Marshal(shaped)
+	if err != nil {
+		return 0
+	}
+
+	// One separator per item
+	return int64(len(encoded)+1) * int64(len(items))
+}
+
+// columnarColumn names a columnar response array and the JSON field of each
+// shaped item it collects
+type columnarColumn struct {
+	key   string
+	field string
+}
+
+// respondColumnar writes a page of list results as one array per column,
+// aligned by index, instead of an array of objects. Missing values are null.
+// Items are shaped like JSON responses; pagination is carried only in the
+// X-Total-Count and Link headers. Returns an error only after the header has
+// been written, so callers log it.
+func respondColumnar[T any](h *WeatherHandler, w http.ResponseWriter, r *http.Request, route string, items []T, meta pageMeta, columns []columnarColumn) error {
+	body, err := buildColumns(items, columns, h.shapeGeneric)
+	if err != nil {
+		h.metrics.RecordAPIError("internal_error", route)
+		h.sendError(w, r, "failed to build columnar response", http.StatusInternalServerError)
+		return nil
+	}
+
+	// The body is built in full, so its exact size is known before writing
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	if h.prettyJSON(r) {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(body); err != nil {
+		h.metrics.RecordAPIError("internal_error", route)
+		h.sendError(w, r, "failed to build columnar response", http.StatusInternalServerError)
+		return nil
+	}
+
+	if limit := h.options.MaxResponseBytes; limit > 0 && int64(encoded.Len()) > limit {
+		h.metrics.RecordAPIError("response_too_large", route)
+		h.sendError(w, r, fmt.Sprintf("response of %d bytes exceeds the %d byte limit, narrow the filter or lower limit", encoded.Len(), limit), http.StatusRequestEntityTooLarge)
+		return nil
+	}
+
+	h.metrics.RecordAPIRequest(route, r.Method, "200")
+	setPaginationHeaders(w, r, meta)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = encoded.WriteTo(w)
+	return err
+}
+
+// buildColumns collects each column's field from every shaped item
+// Every array has one entry per item, nil where the item lacks the field
+func buildColumns[T any](items []T, columns []columnarColumn, shape func(interface{}) (interface{}, error)) (map[string][]interface{}, error) {
+	body := make(map[string][]interface{}, len(columns))
+	for _, column := range columns {
+		body[column.key] = make([]interface{}, 0, len(items))
+	}
+
+	for _, item := range items {
+		shaped, err := shape(item)
+		if err != nil {
+			return nil, err
+		}
+
+		fields, _ := shaped.(map[string]interface{})
+		for _, column := range columns {
+			body[column.key] = append(body[column.key], fields[column.field])
+		}
+	}
+
+	return body, nil
+}
+
+// setPaginationHeaders sets X-Total-Count and a Link header with rel="prev"
+// and rel="next" page URLs, so clients can page without parsing the body
+// Links are relative references to the request path with every other query
+// parameter preserved, which keeps them correct behind proxies
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, meta pageMeta) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(meta.Total))
+
+	pageLink := func(page int, rel string) string {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(meta.Limit))
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.Path, query.Encode(), rel)
+	}
+
+	var links []string
+	if meta.Page > 1 {
+		links = append(links, pageLink(min(meta.Page-1, max(meta.TotalPages, 1)), "prev"))
+	}
+	if meta.Page < meta.TotalPages {
+		links = append(links, pageLink(meta.Page+1, "next"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// writeNDJSON writes each shaped item as a JSON object on its own line
+func writeNDJSON[T any](w http.ResponseWriter, items []T, shape func(interface{}) (interface{}, error)) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for _, item := range items {
+		shaped, err := shape(item)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(shaped); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeCSV writes shaped items as CSV with columns in struct field order
+// Missing (null) values are written as empty cells
+func writeCSV[T any](w http.ResponseWriter, items []T, shape func(interface{}) (interface{}, error)) error {
+	return writeCSVColumns(w, items, jsonFieldNames(reflect.TypeOf((*T)(nil)).Elem()), shape)
+}
+
+// writeCSVColumns writes a header row of columns followed by one row per
+// shaped item, taking each column from the item's JSON field of that name
+func writeCSVColumns[T any](w http.ResponseWriter, items []T, columns []string, shape func(interface{}) (interface{}, error)) error {
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	row := make([]string, len(columns))
+	for _, item := range items {
+		shaped, err := shape(item)
+		if err != nil {
+			return err
+		}
+
+		fields, _ := shaped.(map[string]interface{})
+		for i, column := range columns {
+			row[i] = csvValue(fields[column])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonFieldNames returns the JSON names of a struct type's exported fields in declaration order
+// Fields of untagged embedded structs are listed in place of the embedded field
+func jsonFieldNames(t reflect.Type) []string {
+	fields := jsonFields(t)
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// jsonField is a struct field as encoding/json writes it
+// index is the field's path for reflect.Value.FieldByIndex
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	index     []int
+	omitEmpty bool
+}
+
+// jsonFields returns a struct type's JSON fields in declaration order, as
+// listed by jsonFieldNames
+func jsonFields(t reflect.Type) []jsonField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := make([]jsonField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Untagged embedded structs contribute their own fields, as in encoding/json
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for _, promoted := range jsonFields(embedded) {
+					promoted.index = append([]int{i}, promoted.index...)
+					fields = append(fields, promoted)
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitEmpty := slices.Contains(strings.Split(options, ","), "omitempty")
+		fields = append(fields, jsonField{name: name, typ: field.Type, index: []int{i}, omitEmpty: omitEmpty})
+	}
+
+	return fields
+}
+
+// fillOmitted adds every field of v that encoding/json left out of value,
+// v's generic JSON form, so objects have a stable set of keys
+// An omitted field is written as its zero value would be, so nil pointers
+// become null. Nested structs, slices and maps are filled recursively.
+func fillOmitted(value interface{}, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch generic := value.(type) {
+	case map[string]interface{}:
+		switch v.Kind() {
+		case reflect.Struct:
+			for _, field := range jsonFields(v.Type()) {
+				child, ok := generic[field.name]
+				if !ok {
+					generic[field.name] = zeroJSON(field.typ)
+					continue
+				}
+				// Fails only through a nil embedded pointer, whose fields were omitted
+				if fieldValue, err := v.FieldByIndexErr(field.index); err == nil {
+					fillOmitted(child, fieldValue)
+				}
+			}
+		case reflect.Map:
+			for iter := v.MapRange(); iter.Next(); {
+				if child, ok := generic[fmt.Sprint(iter.Key().Interface())]; ok {
+					fillOmitted(child, iter.Value())
+				}
+			}
+		}
+
+	case []interface{}:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			for i, child := range generic {
+				if i < v.Len() {
+					fillOmitted(child, v.Index(i))
+				}
+			}
+		}
+	}
+}
+
+// zeroJSON returns the generic JSON form of t's zero value
+func zeroJSON(t reflect.Type) interface{} {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return nil
+	}
+
+	encoded, err := json.Marshal(reflect.Zero(t).Interface())
+	if err != nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil
+	}
+	return generic
+}
+
+// csvValue renders a generic JSON value as a CSV cell
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
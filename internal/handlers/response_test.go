@@ -0,0 +1,571 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"weather-platform/internal/models"
+)
+
+func TestShapeResponse_RoundsFractionalValues(t *testing.T) {
+	h := &WeatherHandler{options: Options{TemperaturePrecision: 1, PrecipitationPrecision: 3}}
+
+	maxTemp := 25.500000001
+	precip := 1.23456
+	data := map[string]interface{}{
+		"id":                      int64(9007199254740993),
+		"max_temperature_celsius": &maxTemp,
+		"precipitation_cm":        []float64{precip},
+	}
+
+	shaped, err := h.shapeResponse(data)
+	if err != nil {
+		t.Fatalf("shapeResponse() error = %v", err)
+	}
+
+	encoded, err := json.Marshal(shaped)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"id":9007199254740993,"max_temperature_celsius":25.5,"precipitation_cm":[1.235]}`
+	if string(encoded) != want {
+		t.Errorf("shaped response = %s, want %s", encoded, want)
+	}
+
+	if maxTemp != 25.500000001 {
+		t.Errorf("source value modified: %v", maxTemp)
+	}
+}
+
+func TestShapeResponse_NegativePrecisionDisablesRounding(t *testing.T) {
+	h := &WeatherHandler{options: Options{TemperaturePrecision: -1, PrecipitationPrecision: -1}}
+
+	data := map[string]float64{"avg_max_temperature_celsius": 24.56789}
+	shaped, err := h.shapeResponse(data)
+	if err != nil {
+		t.Fatalf("shapeResponse() error = %v", err)
+	}
+
+	encoded, _ := json.Marshal(shaped)
+	if string(encoded) != `{"avg_max_temperature_celsius":24.56789}` {
+		t.Errorf("shaped response = %s, want unrounded value", encoded)
+	}
+}
+
+// TestObjectShaper_RoundingDisabled tests that callers reading fields still
+// get generic objects when shapeResponse passes values through unchanged
+func TestObjectShaper_RoundingDisabled(t *testing.T) {
+	h := &WeatherHandler{options: Options{TemperaturePrecision: -1, PrecipitationPrecision: -1}}
+
+	maxTemp := 21.55
+	obs := &models.WeatherObservation{StationID: "A", MaxTemperatureCelsius: &maxTemp}
+
+	shaped, err := h.shapeResponse(obs)
+	if err != nil {
+		t.Fatalf("shapeResponse() error = %v", err)
+	}
+	if shaped != interface{}(obs) {
+		t.Errorf("shapeResponse() = %T, want the source value unchanged", shaped)
+	}
+
+	rec := httptest.NewRecorder()
+	shape := h.objectShaper(httptest.NewRequest("GET", "/api/weather", nil))
+	err = writeCSVColumns(rec, []*models.WeatherObservation{obs}, []string{"station_id", "max_temperature_celsius"}, shape)
+	if err != nil {
+		t.Fatalf("writeCSVColumns() error = %v", err)
+	}
+	if want := "station_id,max_temperature_celsius\nA,21.55\n"; rec.Body.String() != want {
+		t.Errorf("CSV body = %q, want %q", rec.Body.String(), want)
+	}
+}
+
+// TestShapeResponse_RoundsOnlyMeasurements tests that coefficients, ratios
+// and percentages keep their precision when measurements are rounded
+func TestShapeResponse_RoundsOnlyMeasurements(t *testing.T) {
+	h := &WeatherHandler{options: Options{TemperaturePrecision: 0, PrecipitationPrecision: 0}}
+
+	data := map[string]float64{
+		"avg_max_temperature_celsius": 24.56,
+		"total_precipitation_cm":      12.34,
+		"coefficient":                 0.87,
+		"precipitation_ratio":         0.95,
+		"percent_difference":          -3.25,
+	}
+	shaped, err := h.shapeResponse(data)
+	if err != nil {
+		t.Fatalf("shapeResponse() error = %v", err)
+	}
+
+	encoded, _ := json.Marshal(shaped)
+	want := `{"avg_max_temperature_celsius":25,"coefficient":0.87,"percent_difference":-3.25,"precipitation_ratio":0.95,"total_precipitation_cm":12}`
+	if string(encoded) != want {
+		t.Errorf("shaped response = %s, want %s", encoded, want)
+	}
+}
+
+// TestShapeResponse_KeepsFieldOrder tests that struct fields are rounded in
+// declaration order rather than re-sorted by a generic round trip
+func TestShapeResponse_KeepsFieldOrder(t *testing.T) {
+	h := &WeatherHandler{options: DefaultOptions()}
+	maxTemp, precip := 21.456, 0.125
+	obs := &models.WeatherObservation{
+		ID:                    1,
+		StationID:             "A",
+		ObservationDate:       time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+		MaxTemperatureCelsius: &maxTemp,
+		PrecipitationCm:       &precip,
+	}
+
+	shaped, err := h.shapeResponse(obs)
+	if err != nil {
+		t.Fatalf("shapeResponse() error = %v", err)
+	}
+	encoded, err := json.Marshal(shaped)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"id":1,"station_id":"A","observation_date":"2023-01-15T00:00:00Z","max_temperature_celsius":21.46,` +
+		`"precipitation_cm":0.13,"created_at":"0001-01-01T00:00:00Z"}`
+	if string(encoded) != want {
+		t.Errorf("shaped observation = %s, want %s", encoded, want)
+	}
+}
+
+// TestShapeResponse_MatchesEncodingJSON tests that values needing no rounding
+// encode exactly as encoding/json writes them
+func TestShapeResponse_MatchesEncodingJSON(t *testing.T) {
+	h := &WeatherHandler{options: DefaultOptions()}
+	maxTemp := 21.5
+	obs := &models.WeatherObservation{ID: 1, StationID: "A<&>", MaxTemperatureCelsius: &maxTemp}
+
+	values := map[string]interface{}{
+		"observation":   obs,
+		"nil date only": models.DateOnlyObservations([]*models.WeatherObservation{nil}),
+		"page":          PaginatedResponse{Data: []*models.WeatherObservation{obs}, Total: 1},
+		"map":           map[string]interface{}{"b": []int{1, 2}, "a": nil, "c": map[int]string{2: "x", 10: "y"}},
+		"floats":        map[string]float64{"tiny": 1e-7, "huge": 1e21, "ratio": 0.123456},
+		"bytes":         struct{ Raw []byte }{[]byte("raw")},
+		"nil slice":     struct{ Years []int }{},
+	}
+
+	for name, value := range values {
+		t.Run(name, func(t *testing.T) {
+			want, err := json.Marshal(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			shaped, err := h.shapeResponse(value)
+			if err != nil {
+				t.Fatalf("shapeResponse() error = %v", err)
+			}
+			got, err := json.Marshal(shaped)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("shaped = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+		ok     bool
+	}{
+		{"", formatJSON, true},
+		{"application/json", formatJSON, true},
+		{"*/*", formatJSON, true},
+		{"text/csv", formatCSV, true},
+		{"application/x-ndjson", formatNDJSON, true},
+		{"text/html, text/csv;q=0.8", formatCSV, true},
+		{"application/json;q=0.5, application/x-ndjson", formatNDJSON, true},
+		{"text/csv;q=0, application/json", formatJSON, true},
+		{"text/html", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := negotiateFormat(tt.accept)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("negotiateFormat(%q) = (%q, %v), want (%q, %v)", tt.accept, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWriteCSV_UsesFieldOrderAndEmptyNulls(t *testing.T) {
+	type row struct {
+		StationID string   `json:"station_id"`
+		Value     *float64 `json:"value_celsius,omitempty"`
+		Internal  string   `json:"-"`
+	}
+
+	h := &WeatherHandler{options: DefaultOptions()}
+	value := 1.23456
+	rec := httptest.NewRecorder()
+
+	err := writeCSV(rec, []*row{{StationID: "A", Value: &value}, {StationID: "B"}}, h.shapeGeneric)
+	if err != nil {
+		t.Fatalf("writeCSV() error = %v", err)
+	}
+
+	want := "station_id,value_celsius\nA,1.23\nB,\n"
+	if rec.Body.String() != want {
+		t.Errorf("CSV body = %q, want %q", rec.Body.String(), want)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+}
+
+// TestWriteCSV_DateOnlyObservations tests embedded field columns and date-only dates
+func TestWriteCSV_DateOnlyObservations(t *testing.T) {
+	h := &WeatherHandler{options: DefaultOptions()}
+	obs := &models.WeatherObservation{
+		ID:              1,
+		StationID:       "A",
+		ObservationDate: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+		CreatedAt:       time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+	}
+	rec := httptest.NewRecorder()
+
+	err := writeCSV(rec, models.DateOnlyObservations([]*models.WeatherObservation{obs}), h.shapeGeneric)
+	if err != nil {
+		t.Fatalf("writeCSV() error = %v", err)
+	}
+
+	want := "id,station_id,observation_date,max_temperature_celsius,min_temperature_celsius,precipitation_cm,created_at,diurnal_range_celsius\n" +
+		"1,A,2023-01-15,,,,2024-02-01T12:00:00Z,\n"
+	if rec.Body.String() != want {
+		t.Errorf("CSV body = %q, want %q", rec.Body.String(), want)
+	}
+}
+
+// TestSetPaginationHeaders tests X-Total-Count and prev/next Link headers
+func TestSetPaginationHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		meta     pageMeta
+		wantLink string
+	}{
+		{
+			"first page links next only",
+			"/api/stations?limit=10",
+			pageMeta{Total: 25, Page: 1, Limit: 10, TotalPages: 3},
+			`</api/stations?limit=10&page=2>; rel="next"`,
+		},
+		{
+			"middle page keeps filters",
+			"/api/weather?station_id=A&page=2&limit=10",
+			pageMeta{Total: 25, Page: 2, Limit: 10, TotalPages: 3},
+			`</api/weather?limit=10&page=1&station_id=A>; rel="prev", </api/weather?limit=10&page=3&station_id=A>; rel="next"`,
+		},
+		{
+			"page past the end links back to the last page",
+			"/api/stations?page=9",
+			pageMeta{Total: 25, Page: 9, Limit: 10, TotalPages: 3},
+			`</api/stations?limit=10&page=3>; rel="prev"`,
+		},
+		{
+			"single page has no links",
+			"/api/stations",
+			pageMeta{Total: 5, Page: 1, Limit: 10, TotalPages: 1},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			setPaginationHeaders(rec, httptest.NewRequest("GET", tt.target, nil), tt.meta)
+
+			if got := rec.Header().Get("X-Total-Count"); got != strconv.Itoa(tt.meta.Total) {
+				t.Errorf("X-Total-Count = %q, want %d", got, tt.meta.Total)
+			}
+			if got := rec.Header().Get("Link"); got != tt.wantLink {
+				t.Errorf("Link = %q, want %q", got, tt.wantLink)
+			}
+		})
+	}
+}
+
+// TestStreamPaginatedJSON_Pretty tests that pretty output is the indented form of the compact output
+func TestStreamPaginatedJSON_Pretty(t *testing.T) {
+	type row struct {
+		StationID string `json:"station_id"`
+		Years     []int  `json:"years"`
+	}
+
+	h := &WeatherHandler{options: DefaultOptions()}
+	meta := pageMeta{Total: 2, Page: 1, Limit: 100, TotalPages: 1}
+
+	for name, items := range map[string][]*row{
+		"rows":  {{StationID: "A", Years: []int{2023, 2024}}, {StationID: "B"}},
+		"empty": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			compact := httptest.NewRecorder()
+			if err := streamPaginatedJSON(compact, items, meta, h.shapeResponse, 200, false); err != nil {
+				t.Fatalf("streamPaginatedJSON(compact) error = %v", err)
+			}
+			if !json.Valid(compact.Body.Bytes()) {
+				t.Fatalf("compact body is not valid JSON: %s", compact.Body.String())
+			}
+
+			pretty := httptest.NewRecorder()
+			if err := streamPaginatedJSON(pretty, items, meta, h.shapeResponse, 200, true); err != nil {
+				t.Fatalf("streamPaginatedJSON(pretty) error = %v", err)
+			}
+
+			var want bytes.Buffer
+			if err := json.Indent(&want, compact.Body.Bytes(), "", "  "); err != nil {
+				t.Fatal(err)
+			}
+			if pretty.Body.String() != want.String() {
+				t.Errorf("pretty body =\n%s\nwant\n%s", pretty.Body.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	h := &WeatherHandler{options: DefaultOptions()}
+	if h.prettyJSON(httptest.NewRequest("GET", "/api/weather", nil)) {
+		t.Error("prettyJSON() without parameter = true, want compact default")
+	}
+	if !h.prettyJSON(httptest.NewRequest("GET", "/api/weather?pretty=true", nil)) {
+		t.Error("prettyJSON(pretty=true) = false")
+	}
+
+	h.options.PrettyJSON = true
+	if h.prettyJSON(httptest.NewRequest("GET", "/api/weather?pretty=false", nil)) {
+		t.Error("prettyJSON(pretty=false) with PrettyJSON default = true, want override")
+	}
+}
+
+// TestProjectFields tests that only selected fields are serialized
+func TestProjectFields(t *testing.T) {
+	h := &WeatherHandler{options: DefaultOptions()}
+	maxTemp := 21.5
+	obs := &models.WeatherObservation{
+		ID:                    1,
+		StationID:             "A",
+		ObservationDate:       time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+		MaxTemperatureCelsius: &maxTemp,
+	}
+
+	shaping := h.shaping(false)
+	shaping.fields = []string{"station_id", "max_temperature_celsius"}
+	shaped, err := shaping.shape(obs)
+	if err != nil {
+		t.Fatalf("shape() error = %v", err)
+	}
+	encoded, err := json.Marshal(shaped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"station_id":"A","max_temperature_celsius":21.5}`; string(encoded) != want {
+		t.Errorf("projected observation = %s, want %s", encoded, want)
+	}
+
+	rec := httptest.NewRecorder()
+	err = writeCSVColumns(rec, []*models.WeatherObservation{obs}, []string{"station_id", "max_temperature_celsius"}, h.shapeGeneric)
+	if err != nil {
+		t.Fatalf("writeCSVColumns() error = %v", err)
+	}
+	if want := "station_id,max_temperature_celsius\nA,21.5\n"; rec.Body.String() != want {
+		t.Errorf("CSV body = %q, want %q", rec.Body.String(), want)
+	}
+}
+
+func TestParseFieldsParam(t *testing.T) {
+	tests := []struct {
+		target  string
+		want    []string
+		wantErr bool
+	}{
+		{"/api/weather", nil, false},
+		{"/api/weather?fields=station_id,+observation_date,station_id", []string{"station_id", "observation_date"}, false},
+		{"/api/weather?fields=station_id,password", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseFieldsParam(httptest.NewRequest("GET", tt.target, nil), observationFields)
+		if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+			t.Errorf("parseFieldsParam(%q) = (%v, %v), want (%v, error %v)", tt.target, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestBuildColumns(t *testing.T) {
+	h := &WeatherHandler{options: DefaultOptions()}
+	maxTemp, precip := 21.456, 0.25
+	observations := models.DateOnlyObservations([]*models.WeatherObservation{
+		{StationID: "A", ObservationDate: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), MaxTemperatureCelsius: &maxTemp},
+		{StationID: "A", ObservationDate: time.Date(2023, 1, 16, 0, 0, 0, 0, time.UTC), PrecipitationCm: &precip},
+	})
+
+	body, err := buildColumns(observations, observationColumns(false, nil), h.shapeGeneric)
+	if err != nil {
+		t.Fatalf("buildColumns() error = %v", err)
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"dates":["2023-01-15","2023-01-16"],"max_temp":[21.46,null],"min_temp":[null,null],"precip":[null,0.25]}`
+	if string(encoded) != want {
+		t.Errorf("columnar body = %s, want %s", encoded, want)
+	}
+
+	columns := observationColumns(true, []string{"observation_date", "diurnal_range_celsius"})
+	if len(columns) != 2 || columns[0].key != "dates" || columns[1].key != "diurnal_range" {
+		t.Errorf("observationColumns(true, fields) = %v, want dates and diurnal_range", columns)
+	}
+}
+
+func TestParseColumnarParam(t *testing.T) {
+	tests := []struct {
+		target  string
+		want    bool
+		wantErr bool
+	}{
+		{"/api/weather", false, false},
+		{"/api/weather?format=json", false, false},
+		{"/api/weather?format=columnar", true, false},
+		{"/api/weather?format=xml", false, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseColumnarParam(httptest.NewRequest("GET", tt.target, nil))
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseColumnarParam(%q) = (%v, %v), want (%v, error %v)", tt.target, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestShaperExplicitNulls(t *testing.T) {
+	h := &WeatherHandler{options: DefaultOptions()}
+	maxTemp := 21.5
+	obs := &models.WeatherObservation{
+		ID:                    1,
+		StationID:             "A",
+		ObservationDate:       time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+		MaxTemperatureCelsius: &maxTemp,
+	}
+
+	encode := func(target string, data interface{}) string {
+		t.Helper()
+		shaped, err := h.shaper(httptest.NewRequest("GET", target, nil))(data)
+		if err != nil {
+			t.Fatalf("shaper(%s) error = %v", target, err)
+		}
+		encoded, err := json.Marshal(shaped)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(encoded)
+	}
+
+	omitted := encode("/api/weather", models.DateOnlyObservation{WeatherObservation: obs})
+	want := `{"id":1,"station_id":"A","observation_date":"2023-01-15","max_temperature_celsius":21.5,"created_at":"0001-01-01T00:00:00Z"}`
+	if omitted != want {
+		t.Errorf("default shaped observation = %s, want %s", omitted, want)
+	}
+
+	explicit := encode("/api/weather?nulls=explicit", models.DateOnlyObservation{WeatherObservation: obs})
+	want = `{"id":1,"station_id":"A","observation_date":"2023-01-15","max_temperature_celsius":21.5,"min_temperature_celsius":null,` +
+		`"precipitation_cm":null,"created_at":"0001-01-01T00:00:00Z","diurnal_range_celsius":null}`
+	if explicit != want {
+		t.Errorf("explicit shaped observation = %s, want %s", explicit, want)
+	}
+
+	// Values behind interface fields and slices are filled too
+	page := PaginatedResponse{Data: []*models.WeatherObservation{obs}, Total: 1}
+	if got := encode("/api/weather?nulls=explicit", page); !strings.Contains(got, `"min_temperature_celsius":null`) {
+		t.Errorf("explicit shaped page = %s, want nested nulls", got)
+	}
+
+	// An omitted non-pointer field takes its zero value rather than null
+	type counted struct {
+		Count int `json:"count,omitempty"`
+	}
+	if got := encode("/x?nulls=explicit", counted{}); got != `{"count":0}` {
+		t.Errorf("explicit shaped zero count = %s, want {\"count\":0}", got)
+	}
+
+	h.options.ExplicitNulls = true
+	if got := encode("/api/weather?nulls=omit", obs); strings.Contains(got, "null") {
+		t.Errorf("nulls=omit with ExplicitNulls = %s, want nulls omitted", got)
+	}
+}
+
+// TestSizeLimitedWriter checks a streamed body stops at the limit and is
+// flagged with the truncation trailer, while a body within it is untouched
+func TestSizeLimitedWriter(t *testing.T) {
+	h := &WeatherHandler{options: DefaultOptions()}
+	value := 1.5
+	items := make([]*models.WeatherObservation, 50)
+	for i := range items {
+		items[i] = &models.WeatherObservation{StationID: "USC00257715", MaxTemperatureCelsius: &value}
+	}
+	meta := pageMeta{Total: 50, Page: 1, Limit: 50, TotalPages: 1}
+
+	rec := httptest.NewRecorder()
+	guard := newSizeLimitedWriter(rec, 1<<20)
+	if err := streamPaginatedJSON(guard, items, meta, h.shapeResponse, 200, false); err != nil {
+		t.Fatalf("streamPaginatedJSON() within limit error = %v", err)
+	}
+	if guard.exceeded || rec.Result().Trailer.Get(responseTruncatedTrailer) != "" {
+		t.Errorf("response within limit flagged as truncated")
+	}
+	if !json.Valid(rec.Body.Bytes()) {
+		t.Errorf("response within limit is not valid JSON")
+	}
+	if err := http.NewResponseController(guard).Flush(); err != nil || !rec.Flushed {
+		t.Errorf("Flush() through the guard error = %v, flushed = %v", err, rec.Flushed)
+	}
+
+	rec = httptest.NewRecorder()
+	guard = newSizeLimitedWriter(rec, 500)
+	err := streamPaginatedJSON(guard, items, meta, h.shapeResponse, 200, false)
+	if err != errResponseTooLarge {
+		t.Fatalf("streamPaginatedJSON() past limit error = %v, want errResponseTooLarge", err)
+	}
+	if rec.Body.Len() > 500 {
+		t.Errorf("body length = %d, want at most 500", rec.Body.Len())
+	}
+	if got := rec.Result().Trailer.Get(responseTruncatedTrailer); !strings.Contains(got, "500 bytes") {
+		t.Errorf("%s trailer = %q, want the limit", responseTruncatedTrailer, got)
+	}
+}
+
+func TestEstimateResponseBytes(t *testing.T) {
+	h := &WeatherHandler{options: DefaultOptions()}
+	obs := &models.WeatherObservation{StationID: "USC00257715"}
+	encoded, err := json.Marshal(obs)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	if got := estimateResponseBytes([]*models.WeatherObservation{}, h.shapeResponse); got != 0 {
+		t.Errorf("estimateResponseBytes(empty) = %d, want 0", got)
+	}
+
+	items := []*models.WeatherObservation{obs, obs, obs}
+	if got, want := estimateResponseBytes(items, h.shapeResponse), int64(3*(len(encoded)+1)); got != want {
+		t.Errorf("estimateResponseBytes() = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,488 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// shapedValue encodes value as encoding/json would, struct fields in
+// declaration order and map keys sorted, while applying its shaping in the
+// same pass, so a shaped response is encoded only once
+type shapedValue struct {
+	value   interface{}
+	shaping *responseShaping
+}
+
+// MarshalJSON implements json.Marshaler
+func (v shapedValue) MarshalJSON() ([]byte, error) {
+	e := &shapeEncoder{shaping: v.shaping}
+	if err := e.encode(reflect.ValueOf(v.value), "", true); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+var (
+	marshalerType     = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// shapeEncoder appends the shaped JSON of a value to buf
+// Throughout, key is the nearest enclosing object key, which selects the
+// precision of numbers, and top is set for the outermost object, the only
+// one field projection applies to.
+type shapeEncoder struct {
+	shaping *responseShaping
+	buf     []byte
+}
+
+func (e *shapeEncoder) encode(v reflect.Value, key string, top bool) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+
+	if v.Type().Implements(marshalerType) || v.Type().Implements(textMarshalerType) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			e.buf = append(e.buf, "null"...)
+			return nil
+		}
+		if v.Type().Implements(marshalerType) {
+			return e.encodeMarshaler(v, key, top)
+		}
+		return e.marshal(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.buf = append(e.buf, "null"...)
+			return nil
+		}
+		return e.encode(v.Elem(), key, top)
+
+	case reflect.Struct:
+		return e.encodeStruct(v, top)
+
+	case reflect.Map:
+		return e.encodeMap(v, key, top)
+
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf = append(e.buf, "null"...)
+			return nil
+		}
+		// Byte slices are written as base64 strings
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return e.marshal(v)
+		}
+		fallthrough
+	case reflect.Array:
+		e.buf = append(e.buf, '[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			if err := e.encode(v.Index(i), key, false); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, ']')
+		return nil
+
+	case reflect.Float32:
+		return e.encodeFloat(v.Float(), 32, key)
+	case reflect.Float64:
+		return e.encodeFloat(v.Float(), 64, key)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.buf = strconv.AppendInt(e.buf, v.Int(), 10)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.buf = strconv.AppendUint(e.buf, v.Uint(), 10)
+		return nil
+	case reflect.Bool:
+		e.buf = strconv.AppendBool(e.buf, v.Bool())
+		return nil
+	case reflect.String:
+		e.appendString(v.String())
+		return nil
+
+	default:
+		// Unsupported kinds fail as they would in encoding/json
+		return e.marshal(v)
+	}
+}
+
+// encodeStruct writes a struct's JSON fields in declaration order
+// With explicit nulls, fields encoding/json would omit are written as their
+// zero value, so nil pointers become null.
+func (e *shapeEncoder) encodeStruct(v reflect.Value, top bool) error {
+	e.buf = append(e.buf, '{')
+	first := true
+	for _, field := range cachedJSONFields(v.Type()) {
+		if top && !e.shaping.keeps(field.name) {
+			continue
+		}
+
+		// Fails only through a nil embedded pointer, whose fields are omitted
+		fieldValue, err := v.FieldByIndexErr(field.index)
+		omitted := err != nil || field.omitEmpty && isEmptyValue(fieldValue)
+		if omitted {
+			if !e.shaping.explicitNulls {
+				continue
+			}
+			fieldValue = reflect.Zero(field.typ)
+		}
+
+		e.appendKey(field.name, &first)
+		if err := e.encode(fieldValue, field.name, false); err != nil {
+			return err
+		}
+	}
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+// encodeMap writes a map with its keys sorted, as encoding/json does
+// Maps with keys that are neither strings nor integers are left to their
+// encoding/json form.
+func (e *shapeEncoder) encodeMap(v reflect.Value, key string, top bool) error {
+	if v.IsNil() {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+
+	mapKeys := v.MapKeys()
+	names := make([]string, len(mapKeys))
+	for i, mapKey := range mapKeys {
+		switch mapKey.Kind() {
+		case reflect.String:
+			names[i] = mapKey.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			names[i] = strconv.FormatInt(mapKey.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			names[i] = strconv.FormatUint(mapKey.Uint(), 10)
+		default:
+			return e.encodeMarshaler(v, key, top)
+		}
+	}
+
+	order := make([]int, len(mapKeys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return names[order[a]] < names[order[b]] })
+
+	e.buf = append(e.buf, '{')
+	first := true
+	for _, i := range order {
+		if top && !e.shaping.keeps(names[i]) {
+			continue
+		}
+		e.appendKey(names[i], &first)
+		if err := e.encode(v.MapIndex(mapKeys[i]), names[i], false); err != nil {
+			return err
+		}
+	}
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+// encodeMarshaler writes a value that encodes itself, such as
+// models.DateOnlyObservation, shaping the JSON it produces
+// Objects from a struct list that struct's fields first, in declaration
+// order, then any others in the order the marshaler wrote them.
+func (e *shapeEncoder) encodeMarshaler(v reflect.Value, key string, top bool) error {
+	encoded, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+
+	switch encoded[0] {
+	case '{', '[':
+		decoder := json.NewDecoder(bytes.NewReader(encoded))
+		decoder.UseNumber()
+
+		value, err := decodeOrdered(decoder)
+		if err != nil {
+			return err
+		}
+		return e.encodeGeneric(value, key, top, v)
+	case '"', 't', 'f', 'n':
+		e.buf = append(e.buf, encoded...)
+		return nil
+	default:
+		e.encodeNumber(json.Number(encoded), key)
+		return nil
+	}
+}
+
+// orderedMember is a decoded JSON object member; objects decoded by
+// decodeOrdered are member slices, which keep the encoded key order
+type orderedMember struct {
+	key   string
+	value interface{}
+}
+
+// decodeOrdered decodes the next JSON value from decoder like Decode into an
+// interface{}, except that objects come back as []orderedMember
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		members := []orderedMember{}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, orderedMember{key: keyToken.(string), value: value})
+		}
+		_, err := decoder.Token()
+		return members, err
+
+	case json.Delim('['):
+		values := []interface{}{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		_, err := decoder.Token()
+		return values, err
+	}
+
+	return token, nil
+}
+
+// encodeGeneric writes a value from decodeOrdered; source is the Go value it
+// was encoded from, when known, and supplies field order and explicit nulls
+func (e *shapeEncoder) encodeGeneric(value interface{}, key string, top bool, source reflect.Value) error {
+	source = indirect(source)
+
+	switch value := value.(type) {
+	case []orderedMember:
+		return e.encodeMembers(value, top, source)
+
+	case []interface{}:
+		e.buf = append(e.buf, '[')
+		for i, child := range value {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			var childSource reflect.Value
+			if (source.Kind() == reflect.Slice || source.Kind() == reflect.Array) && i < source.Len() {
+				childSource = source.Index(i)
+			}
+			if err := e.encodeGeneric(child, key, false, childSource); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, ']')
+		return nil
+
+	case json.Number:
+		e.encodeNumber(value, key)
+	case string:
+		e.appendString(value)
+	case bool:
+		e.buf = strconv.AppendBool(e.buf, value)
+	default:
+		e.buf = append(e.buf, "null"...)
+	}
+	return nil
+}
+
+// encodeMembers writes a decoded object, see encodeMarshaler for key order
+func (e *shapeEncoder) encodeMembers(members []orderedMember, top bool, source reflect.Value) error {
+	var fields []jsonField
+	if source.Kind() == reflect.Struct {
+		fields = cachedJSONFields(source.Type())
+	}
+
+	e.buf = append(e.buf, '{')
+	first := true
+	written := make([]bool, len(members))
+	for _, field := range fields {
+		i := slices.IndexFunc(members, func(member orderedMember) bool { return member.key == field.name })
+		if i >= 0 {
+			written[i] = true
+		}
+		if top && !e.shaping.keeps(field.name) {
+			continue
+		}
+
+		if i < 0 {
+			if e.shaping.explicitNulls {
+				e.appendKey(field.name, &first)
+				if err := e.encode(reflect.Zero(field.typ), field.name, false); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// Fails only through a nil embedded pointer, leaving no source
+		fieldValue, _ := source.FieldByIndexErr(field.index)
+		e.appendKey(field.name, &first)
+		if err := e.encodeGeneric(members[i].value, field.name, false, fieldValue); err != nil {
+			return err
+		}
+	}
+
+	for i, member := range members {
+		if written[i] || top && !e.shaping.keeps(member.key) {
+			continue
+		}
+		e.appendKey(member.key, &first)
+		if err := e.encodeGeneric(member.value, member.key, false, reflect.Value{}); err != nil {
+			return err
+		}
+	}
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+// encodeFloat writes f, rounded when key holds a measurement
+// Integral values are written unrounded, like ids and counts in generic form.
+func (e *shapeEncoder) encodeFloat(f float64, bits int, key string) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("json: unsupported value: %s", strconv.FormatFloat(f, 'g', -1, bits))
+	}
+
+	if precision, ok := e.shaping.precisionFor(key); ok && precision >= 0 && f != math.Trunc(f) {
+		f, bits = roundTo(f, precision), 64
+	}
+	e.buf = appendJSONFloat(e.buf, f, bits)
+	return nil
+}
+
+// encodeNumber writes an encoded number, rounded when key holds a measurement
+func (e *shapeEncoder) encodeNumber(n json.Number, key string) {
+	if _, err := n.Int64(); err != nil {
+		if precision, ok := e.shaping.precisionFor(key); ok && precision >= 0 {
+			if f, err := n.Float64(); err == nil {
+				e.buf = appendJSONFloat(e.buf, roundTo(f, precision), 64)
+				return
+			}
+		}
+	}
+	e.buf = append(e.buf, n...)
+}
+
+// appendJSONFloat formats f the way encoding/json does
+func appendJSONFloat(b []byte, f float64, bits int) []byte {
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	b = strconv.AppendFloat(b, f, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b
+}
+
+// appendKey writes an object key, preceded by a separator unless first
+func (e *shapeEncoder) appendKey(key string, first *bool) {
+	if !*first {
+		e.buf = append(e.buf, ',')
+	}
+	*first = false
+	e.appendString(key)
+	e.buf = append(e.buf, ':')
+}
+
+// appendString writes s as a JSON string
+// Plain ASCII is copied as is; anything else is escaped by encoding/json,
+// which also escapes HTML characters.
+func (e *shapeEncoder) appendString(s string) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= utf8.RuneSelf || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			encoded, _ := json.Marshal(s)
+			e.buf = append(e.buf, encoded...)
+			return
+		}
+	}
+
+	e.buf = append(e.buf, '"')
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, '"')
+}
+
+// marshal writes v's encoding/json form
+func (e *shapeEncoder) marshal(v reflect.Value) error {
+	encoded, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+	e.buf = append(e.buf, encoded...)
+	return nil
+}
+
+// indirect follows pointers and interfaces, returning the zero Value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// isEmptyValue reports whether encoding/json's omitempty would leave v out
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// jsonFieldCache holds jsonFields results by struct type
+var jsonFieldCache sync.Map
+
+// cachedJSONFields is jsonFields for a struct type, computed once per type
+func cachedJSONFields(t reflect.Type) []jsonField {
+	if fields, ok := jsonFieldCache.Load(t); ok {
+		return fields.([]jsonField)
+	}
+	fields, _ := jsonFieldCache.LoadOrStore(t, jsonFields(t))
+	return fields.([]jsonField)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"weather-platform/internal/models"
+	"weather-platform/internal/repository"
+)
+
+// maxStaleStatsEntries bounds the statistics pages kept for stale fallback
+const maxStaleStatsEntries = 1000
+
+// statsSnapshot is the last successful statistics page for one filter
+type statsSnapshot struct {
+	statistics []*models.WeatherStatistics
+	total      int
+	storedAt   time.Time
+}
+
+// staleStatsCache keeps the last successful statistics page per filter so
+// GET /api/weather/stats can answer while the database is unavailable
+// The oldest filter is evicted once maxEntries are held
+type staleStatsCache struct {
+	mu         sync.Mutex
+	entries    map[string]statsSnapshot
+	order      []string
+	maxEntries int
+}
+
+// newStaleStatsCache creates an empty cache holding up to maxEntries filters
+func newStaleStatsCache(maxEntries int) *staleStatsCache {
+	return &staleStatsCache{
+		entries:    make(map[string]statsSnapshot),
+		maxEntries: maxEntries,
+	}
+}
+
+// statsFilterKey identifies a statistics page by every field that selects it
+func statsFilterKey(filter repository.StatisticsFilter) string {
+	station, year, reliable := "", "", ""
+	if filter.StationID != nil {
+		station = *filter.StationID
+	}
+	if filter.Year != nil {
+		year = fmt.Sprint(*filter.Year)
+	}
+	if filter.IsReliable != nil {
+		reliable = fmt.Sprint(*filter.IsReliable)
+	}
+	return fmt.Sprintf("%q|%s|%s|%d|%d", station, year, reliable, filter.Limit, filter.Offset)
+}
+
+// put stores the page for filter, replacing any older snapshot of it
+func (c *staleStatsCache) put(filter repository.StatisticsFilter, statistics []*models.WeatherStatistics, total int) {
+	key := statsFilterKey(filter)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok {
+		if len(c.order) >= c.maxEntries {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = statsSnapshot{statistics: statistics, total: total, storedAt: time.Now()}
+}
+
+// get returns the last page stored for filter
+func (c *staleStatsCache) get(filter repository.StatisticsFilter) (statsSnapshot, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot, ok := c.entries[statsFilterKey(filter)]
+	return snapshot, ok
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"testing"
+
+	"weather-platform/internal/models"
+	"weather-platform/internal/repository"
+)
+
+// TestStaleStatsCache tests per-filter snapshots and oldest-first eviction
+func TestStaleStatsCache(t *testing.T) {
+	cache := newStaleStatsCache(2)
+
+	station := "USC00257715"
+	year := 2023
+	byStation := repository.StatisticsFilter{StationID: &station, Limit: 100}
+	byYear := repository.StatisticsFilter{Year: &year, Limit: 100}
+	secondPage := repository.StatisticsFilter{Year: &year, Limit: 100, Offset: 100}
+
+	if _, ok := cache.get(byStation); ok {
+		t.Fatal("get() on empty cache found a snapshot")
+	}
+
+	cache.put(byStation, []*models.WeatherStatistics{{StationID: station, Year: 2023}}, 1)
+	cache.put(byYear, []*models.WeatherStatistics{}, 0)
+	cache.put(byStation, []*models.WeatherStatistics{{StationID: station, Year: 2024}}, 2)
+
+	snapshot, ok := cache.get(byStation)
+	if !ok || snapshot.total != 2 || snapshot.statistics[0].Year != 2024 {
+		t.Errorf("get(byStation) = %+v, %v, want the latest page with total 2", snapshot, ok)
+	}
+	if _, ok := cache.get(secondPage); ok {
+		t.Error("get() for another page found a snapshot")
+	}
+
+	// A third filter evicts the oldest one, byStation
+	cache.put(secondPage, nil, 0)
+	if _, ok := cache.get(byStation); ok {
+		t.Error("get(byStation) after eviction found a snapshot")
+	}
+	if _, ok := cache.get(byYear); !ok {
+		t.Error("get(byYear) was evicted, want it kept")
+	}
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"weather-platform/internal/repository"
+	"weather-platform/pkg/logging"
+)
+
+// GetStations handles GET /api/stations
+func (h *WeatherHandler) GetStations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/stations").Observe(duration.Seconds())
+	}()
+
+	page, limit, offset := parsePagination(r)
+
+	stations, total, err := h.weatherService.GetStations(ctx, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_STATIONS_ERROR] Failed to list stations", logging.Fields{
+			"page":  page,
+			"limit": limit,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/stations")
+		h.sendError(w, r, "failed to list stations", http.StatusInternalServerError)
+		return
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	if err := respond(h, w, r, "/api/stations", stations, pageMeta{total, page, limit, totalPages}); err != nil {
+		h.logger.Warn(ctx, "[API_GET_STATIONS_STREAM_ERROR] Failed to write stations", logging.Fields{
+			"error": err.Error(),
+		})
+	}
+}
+
+// Station search limits
+const (
+	defaultStationSearchLimit = 10
+	maxStationSearchLimit     = 50
+	maxStationSearchQueryLen  = 64
+)
+
+// SearchStations handles GET /api/stations/search
+// Returns stations whose ID contains q, for type-ahead pickers
+func (h *WeatherHandler) SearchStations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/stations/search").Observe(duration.Seconds())
+	}()
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		h.sendError(w, r, "q is required", http.StatusBadRequest)
+		return
+	}
+	if len(query) > maxStationSearchQueryLen {
+		h.sendError(w, r, fmt.Sprintf("q must be at most %d characters", maxStationSearchQueryLen), http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultStationSearchLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			h.sendError(w, r, "invalid limit, expected a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = min(parsed, maxStationSearchLimit)
+	}
+
+	stations, err := h.weatherService.SearchStations(ctx, query, limit)
+	if err != nil {
+		h.logger.Error(ctx, "[API_SEARCH_STATIONS_ERROR] Failed to search stations", logging.Fields{
+			"q":     query,
+			"limit": limit,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/stations/search")
+		h.sendError(w, r, "failed to search stations", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"q":     query,
+		"limit": limit,
+		"total": len(stations),
+		"data":  stations,
+	}
+
+	h.metrics.RecordAPIRequest("/api/stations/search", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetStationQuality handles GET /api/stations/{station_id}/quality
+func (h *WeatherHandler) GetStationQuality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/stations/{station_id}/quality").Observe(duration.Seconds())
+	}()
+
+	stationID := mux.Vars(r)["station_id"]
+
+	quality, err := h.statsService.GetStationQuality(ctx, stationID)
+	if err != nil {
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_GET_STATION_QUALITY_ERROR] Failed to get station quality", logging.Fields{
+			"station_id": stationID,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/stations/{station_id}/quality")
+		h.sendError(w, r, "failed to retrieve station quality", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"station_id": stationID,
+		"data":       quality,
+	}
+
+	h.metrics.RecordAPIRequest("/api/stations/{station_id}/quality", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetMonthlyObservationCounts handles GET /api/stations/{station_id}/monthly-counts
+// Returns 12 counts for the required year, zero for months without observations
+func (h *WeatherHandler) GetMonthlyObservationCounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/stations/{station_id}/monthly-counts").Observe(duration.Seconds())
+	}()
+
+	stationID := mux.Vars(r)["station_id"]
+
+	year, err := parseYearParam(r, "year")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if year == nil {
+		h.sendError(w, r, "year is required", http.StatusBadRequest)
+		return
+	}
+
+	counts, err := h.weatherService.GetMonthlyObservationCounts(ctx, stationID, *year)
+	if err != nil {
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_GET_MONTHLY_COUNTS_ERROR] Failed to count observations by month", logging.Fields{
+			"station_id": stationID,
+			"year":       *year,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/stations/{station_id}/monthly-counts")
+		h.sendError(w, r, "failed to count observations by month", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"station_id": stationID,
+		"year":       *year,
+		"data":       counts,
+	}
+
+	h.metrics.RecordAPIRequest("/api/stations/{station_id}/monthly-counts", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetClimateNormals handles GET /api/stations/{station_id}/normals
+func (h *WeatherHandler) GetClimateNormals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/stations/{station_id}/normals").Observe(duration.Seconds())
+	}()
+
+	stationID := mux.Vars(r)["station_id"]
+
+	normals, err := h.weatherService.GetClimateNormals(ctx, stationID)
+	if err != nil {
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_GET_CLIMATE_NORMALS_ERROR] Failed to get climate normals", logging.Fields{
+			"station_id": stationID,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/stations/{station_id}/normals")
+		h.sendError(w, r, "failed to get climate normals", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"station_id": stationID,
+		"data":       normals,
+	}
+
+	h.metrics.RecordAPIRequest("/api/stations/{station_id}/normals", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetStationsMissingStatistics handles GET /api/stations/missing-stats
+func (h *WeatherHandler) GetStationsMissingStatistics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/stations/missing-stats").Observe(duration.Seconds())
+	}()
+
+	stations, err := h.statsService.ListStationsMissingStatistics(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_MISSING_STATS_ERROR] Failed to list stations missing statistics", logging.Fields{}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/stations/missing-stats")
+		h.sendError(w, r, "failed to list stations missing statistics", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"total": len(stations),
+		"data":  stations,
+	}
+
+	h.metrics.RecordAPIRequest("/api/stations/missing-stats", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
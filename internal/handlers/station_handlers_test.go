@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetClimateNormals(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		err         error
+		want        int
+		wantReached bool
+	}{
+		{"known station", http.MethodGet, "/api/stations/USC00110072/normals", nil, http.StatusOK, true},
+		{"unknown station", http.MethodGet, "/api/stations/USC00000000/normals", nil, http.StatusNotFound, false},
+		{"repository failure", http.MethodGet, "/api/stations/USC00110072/normals", errors.New("connection reset"), http.StatusInternalServerError, true},
+		{"write method", http.MethodPost, "/api/stations/USC00110072/normals", nil, http.StatusMethodNotAllowed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepository{stationIDs: []string{"USC00110072"}, err: tt.err}
+			h := newTestHandler(t, repo, DefaultOptions())
+
+			rec := route(h, tt.method, tt.target)
+			if rec.Code != tt.want {
+				t.Fatalf("%s %s status = %d, want %d", tt.method, tt.target, rec.Code, tt.want)
+			}
+			if reached := repo.calls > 0; reached != tt.wantReached {
+				t.Errorf("normals queried = %v, want %v", reached, tt.wantReached)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				StationID string `json:"station_id"`
+				Data      []struct {
+					Month int `json:"month"`
+					Day   int `json:"day"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("response is not JSON: %v", err)
+			}
+			if body.StationID != "USC00110072" || len(body.Data) != 366 {
+				t.Errorf("response station_id = %q with %d days, want USC00110072 with 366", body.StationID, len(body.Data))
+			}
+			if leap := body.Data[59]; leap.Month != 2 || leap.Day != 29 {
+				t.Errorf("day 60 = %d/%d, want 29 February", leap.Month, leap.Day)
+			}
+		})
+	}
+}
@@ -1,13 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 
+	"weather-platform/internal/models"
 	"weather-platform/internal/repository"
 	"weather-platform/internal/services"
 	"weather-platform/pkg/logging"
@@ -16,24 +24,103 @@ import (
 
 // WeatherHandler handles weather API endpoints
 type WeatherHandler struct {
-	weatherService *services.WeatherService
-	statsService   *services.StatisticsService
-	logger         *logging.StructuredLogger
-	metrics        *metrics.Collector
+	weatherService   *services.WeatherService
+	statsService     *services.StatisticsService
+	ingestionService *services.IngestionService
+	logger           *logging.StructuredLogger
+	metrics          *metrics.Collector
+	options          Options
+	health           *HealthRegistry
+
+	// staleStats holds the last good statistics pages when
+	// Options.StaleStatsFallback is set
+	staleStats *staleStatsCache
+}
+
+// Options configures optional handler behavior
+type Options struct {
+	// MaxQueryRangeDays caps the from/to span of date-range queries (0 disables)
+	MaxQueryRangeDays int
+
+	// TemperaturePrecision and PrecipitationPrecision are the decimal places
+	// temperatures (fields ending in _celsius) and precipitation amounts
+	// (fields ending in _cm) are rounded to in responses (negative disables).
+	// Other values such as coefficients and ratios are never rounded, nor are stored values.
+	TemperaturePrecision   int
+	PrecipitationPrecision int
+
+	// DateFormat is the default observation_date format, "datetime" (RFC 3339)
+	// or "date" (YYYY-MM-DD); the date_format query parameter overrides it
+	DateFormat string
+
+	// IngestionRoot is the directory POST /api/ingestion/run may read data_dir
+	// from; empty disables the endpoint
+	IngestionRoot string
+
+	// PrettyJSON indents JSON responses by default; the pretty query
+	// parameter overrides it per request
+	PrettyJSON bool
+
+	// StaleStatsFallback serves the last successful GET /api/weather/stats
+	// page for the same filter, marked X-Served-Stale, when the database read fails
+	StaleStatsFallback bool
+
+	// ExplicitNulls writes missing values as null instead of omitting their
+	// keys; the nulls query parameter (omit or explicit) overrides it per request
+	ExplicitNulls bool
+
+	// MaxResponseBytes caps list response bodies (0 disables). A page whose
+	// estimated size is over the cap is rejected with 413 before anything is
+	// written; a streamed body that still outgrows it is cut off and flagged
+	// with the X-Response-Truncated trailer
+	MaxResponseBytes int64
+}
+
+// DefaultMaxResponseBytes is the response size cap of DefaultOptions
+const DefaultMaxResponseBytes = 64 << 20
+
+// Observation date formats accepted by Options.DateFormat and the date_format parameter
+const (
+	DateFormatDateTime = "datetime"
+	DateFormatDate     = "date"
+)
+
+// DefaultOptions returns the handler options used when SetOptions is not called
+func DefaultOptions() Options {
+	return Options{
+		TemperaturePrecision:   2,
+		PrecipitationPrecision: 2,
+		DateFormat:             DateFormatDateTime,
+		MaxResponseBytes:       DefaultMaxResponseBytes,
+	}
 }
 
 // NewWeatherHandler creates a new weather handler
 func NewWeatherHandler(
 	weatherService *services.WeatherService,
 	statsService *services.StatisticsService,
+	ingestionService *services.IngestionService,
 	logger *logging.StructuredLogger,
 	metricsCollector *metrics.Collector,
 ) *WeatherHandler {
 	return &WeatherHandler{
-		weatherService: weatherService,
-		statsService:   statsService,
-		logger:         logger,
-		metrics:        metricsCollector,
+		weatherService:   weatherService,
+		statsService:     statsService,
+		ingestionService: ingestionService,
+		logger:           logger,
+		metrics:          metricsCollector,
+		options:          DefaultOptions(),
+	}
+}
+
+// minYear is the earliest observation year accepted by the schema
+const minYear = 1900
+
+// SetOptions sets optional handler behavior
+func (h *WeatherHandler) SetOptions(opts Options) {
+	h.options = opts
+	if opts.StaleStatsFallback && h.staleStats == nil {
+		h.staleStats = newStaleStatsCache(maxStaleStatsEntries)
 	}
 }
 
@@ -64,56 +151,38 @@ func (h *WeatherHandler) GetObservations(w http.ResponseWriter, r *http.Request)
 	}()
 
 	// Parse query parameters
-	stationID := r.URL.Query().Get("station_id")
-	startDateStr := r.URL.Query().Get("start_date")
-	endDateStr := r.URL.Query().Get("end_date")
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
-
-	// Default pagination
-	page := 1
-	limit := 100
-
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
+	page, limit, offset := parsePagination(r)
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
-			limit = l
-		}
+	filter, err := h.parseObservationFilter(r)
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
 	}
+	filter.Limit = limit
+	filter.Offset = offset
 
-	offset := (page - 1) * limit
-
-	// Build filter
-	filter := repository.ObservationFilter{
-		Limit:  limit,
-		Offset: offset,
+	include, err := parseIncludeParam(r, observationIncludes)
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	if stationID != "" {
-		filter.StationID = &stationID
+	dateOnly, err := h.parseDateFormat(r)
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	if startDateStr != "" {
-		startDate, err := time.Parse("2006-01-02", startDateStr)
-		if err != nil {
-			h.sendError(w, r, "invalid start_date format, expected YYYY-MM-DD", http.StatusBadRequest)
-			return
-		}
-		filter.StartDate = &startDate
+	fields, err := parseFieldsParam(r, observationFields)
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	if endDateStr != "" {
-		endDate, err := time.Parse("2006-01-02", endDateStr)
-		if err != nil {
-			h.sendError(w, r, "invalid end_date format, expected YYYY-MM-DD", http.StatusBadRequest)
-			return
-		}
-		filter.EndDate = &endDate
+	columnar, err := parseColumnarParam(r)
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// Get observations
@@ -127,53 +196,175 @@ func (h *WeatherHandler) GetObservations(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if include[includeDiurnalRange] {
+		for _, obs := range observations {
+			obs.DiurnalRangeCelsius = obs.DiurnalRange()
+		}
+	}
+
 	totalPages := (total + limit - 1) / limit
 
-	response := PaginatedResponse{
-		Data:       observations,
-		Total:      total,
-		Page:       page,
-		Limit:      limit,
-		TotalPages: totalPages,
+	meta := pageMeta{total, page, limit, totalPages}
+	if columnar {
+		columns := observationColumns(include[includeDiurnalRange], fields)
+		if dateOnly {
+			err = respondColumnar(h, w, r, "/api/weather", models.DateOnlyObservations(observations), meta, columns)
+		} else {
+			err = respondColumnar(h, w, r, "/api/weather", observations, meta, columns)
+		}
+	} else if dateOnly {
+		err = respondFields(h, w, r, "/api/weather", models.DateOnlyObservations(observations), meta, fields)
+	} else {
+		err = respondFields(h, w, r, "/api/weather", observations, meta, fields)
+	}
+	if err != nil {
+		h.logger.Warn(ctx, "[API_GET_OBSERVATIONS_STREAM_ERROR] Failed to stream observations", logging.Fields{
+			"error": err.Error(),
+		})
 	}
-
-	h.metrics.RecordAPIRequest("/api/weather", "GET", "200")
-	h.sendJSON(w, response, http.StatusOK)
 }
 
-// GetStatistics handles GET /api/weather/stats
-func (h *WeatherHandler) GetStatistics(w http.ResponseWriter, r *http.Request) {
+// CountObservations handles GET /api/weather/count
+// Accepts the same filters as GET /api/weather but returns only the match count
+func (h *WeatherHandler) CountObservations(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	startTime := time.Now()
 
 	defer func() {
 		duration := time.Since(startTime)
-		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/stats").Observe(duration.Seconds())
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/count").Observe(duration.Seconds())
 	}()
 
-	// Parse query parameters
-	stationID := r.URL.Query().Get("station_id")
-	yearStr := r.URL.Query().Get("year")
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
+	filter, err := h.parseObservationFilter(r)
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Default pagination
-	page := 1
-	limit := 100
+	count, err := h.weatherService.CountObservations(ctx, filter)
+	if err != nil {
+		h.logger.Error(ctx, "[API_COUNT_OBSERVATIONS_ERROR] Failed to count observations", logging.Fields{
+			"filter": filter,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/count")
+		h.sendError(w, r, "failed to count observations", http.StatusInternalServerError)
+		return
+	}
 
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
+	h.metrics.RecordAPIRequest("/api/weather/count", "GET", "200")
+	h.sendJSON(w, r, map[string]interface{}{"count": count}, http.StatusOK)
+}
+
+// GetLatestObservations handles GET /api/weather/latest
+// station_id may be repeated; omitting it returns every station
+func (h *WeatherHandler) GetLatestObservations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/latest").Observe(duration.Seconds())
+	}()
+
+	dateOnly, err := h.parseDateFormat(r)
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stationIDs := []string{}
+	for _, id := range r.URL.Query()["station_id"] {
+		if id != "" {
+			stationIDs = append(stationIDs, id)
 		}
 	}
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
-			limit = l
+	observations, err := h.weatherService.GetLatestObservations(ctx, stationIDs)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_LATEST_ERROR] Failed to get latest observations", logging.Fields{
+			"station_ids": stationIDs,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/latest")
+		h.sendError(w, r, "failed to retrieve latest observations", http.StatusInternalServerError)
+		return
+	}
+
+	latest := make(map[string]interface{}, len(observations))
+	for _, obs := range observations {
+		latest[obs.StationID] = observationForDateFormat(obs, dateOnly)
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/latest", "GET", "200")
+	h.sendJSON(w, r, map[string]interface{}{"data": latest}, http.StatusOK)
+}
+
+// GetObservationHistory handles GET /api/weather/{station_id}/{date}/history
+// Returns the current observation and its earlier values, most recent change first
+func (h *WeatherHandler) GetObservationHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/{station_id}/{date}/history").Observe(duration.Seconds())
+	}()
+
+	vars := mux.Vars(r)
+	stationID := vars["station_id"]
+
+	date, err := time.Parse("2006-01-02", vars["date"])
+	if err != nil {
+		h.sendError(w, r, "invalid date format, expected YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	dateOnly, err := h.parseDateFormat(r)
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	current, history, err := h.weatherService.GetObservationHistory(ctx, stationID, date)
+	if err != nil {
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
 		}
+
+		h.logger.Error(ctx, "[API_GET_HISTORY_ERROR] Failed to get observation history", logging.Fields{
+			"station_id": stationID,
+			"date":       vars["date"],
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/{station_id}/{date}/history")
+		h.sendError(w, r, "failed to retrieve observation history", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"station_id": stationID,
+		"date":       vars["date"],
+		"current":    observationForDateFormat(current, dateOnly),
+		"history":    history,
 	}
 
-	offset := (page - 1) * limit
+	h.metrics.RecordAPIRequest("/api/weather/{station_id}/{date}/history", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// GetStatistics handles GET /api/weather/stats
+func (h *WeatherHandler) GetStatistics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/stats").Observe(duration.Seconds())
+	}()
+
+	// Parse query parameters
+	stationID := r.URL.Query().Get("station_id")
+	page, limit, offset := parsePagination(r)
 
 	// Build filter
 	filter := repository.StatisticsFilter{
@@ -185,14 +376,19 @@ func (h *WeatherHandler) GetStatistics(w http.ResponseWriter, r *http.Request) {
 		filter.StationID = &stationID
 	}
 
-	if yearStr != "" {
-		year, err := strconv.Atoi(yearStr)
-		if err != nil || year < 1985 || year > 2014 {
-			h.sendError(w, r, "invalid year, expected integer between 1985 and 2014", http.StatusBadRequest)
-			return
-		}
-		filter.Year = &year
+	year, err := parseYearParam(r, "year")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Year = year
+
+	reliable, err := parseBoolParam(r, "reliable")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
 	}
+	filter.IsReliable = reliable
 
 	// Get statistics
 	statistics, total, err := h.statsService.GetStatistics(ctx, filter)
@@ -201,22 +397,229 @@ func (h *WeatherHandler) GetStatistics(w http.ResponseWriter, r *http.Request) {
 			"filter": filter,
 		}, err)
 		h.metrics.RecordAPIError("internal_error", "/api/weather/stats")
-		h.sendError(w, r, "failed to retrieve statistics", http.StatusInternalServerError)
-		return
+
+		snapshot, ok := h.staleStatsSnapshot(filter)
+		if !ok {
+			h.sendError(w, r, "failed to retrieve statistics", http.StatusInternalServerError)
+			return
+		}
+
+		h.logger.Warn(ctx, "[API_GET_STATISTICS_STALE] Serving last successful statistics page", logging.Fields{
+			"filter":      filter,
+			"age_seconds": time.Since(snapshot.storedAt).Seconds(),
+		})
+		w.Header().Set("X-Served-Stale", "true")
+		statistics, total = snapshot.statistics, snapshot.total
+	} else if h.staleStats != nil {
+		h.staleStats.put(filter, statistics, total)
 	}
 
 	totalPages := (total + limit - 1) / limit
 
-	response := PaginatedResponse{
-		Data:       statistics,
-		Total:      total,
-		Page:       page,
-		Limit:      limit,
-		TotalPages: totalPages,
+	if err := respond(h, w, r, "/api/weather/stats", statistics, pageMeta{total, page, limit, totalPages}); err != nil {
+		h.logger.Warn(ctx, "[API_GET_STATISTICS_STREAM_ERROR] Failed to write statistics", logging.Fields{
+			"error": err.Error(),
+		})
+	}
+}
+
+// staleStatsSnapshot returns the last successful statistics page for filter
+// when the stale fallback is enabled
+func (h *WeatherHandler) staleStatsSnapshot(filter repository.StatisticsFilter) (statsSnapshot, bool) {
+	if h.staleStats == nil {
+		return statsSnapshot{}, false
+	}
+	return h.staleStats.get(filter)
+}
+
+// exportFlushRows is how many NDJSON rows are buffered between flushes during exports
+const exportFlushRows = 500
+
+// ExportStatistics handles GET /api/weather/stats/export
+// Streams every matching statistics row as NDJSON without pagination. Errors
+// after the first row is written cannot change the status, so they are logged
+// and the response is truncated.
+func (h *WeatherHandler) ExportStatistics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/stats/export").Observe(duration.Seconds())
+	}()
+
+	var filter repository.StatisticsFilter
+	if stationID := r.URL.Query().Get("station_id"); stationID != "" {
+		filter.StationID = &stationID
+	}
+
+	year, err := parseYearParam(r, "year")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Year = year
+
+	reliable, err := parseBoolParam(r, "reliable")
+	if err != nil {
+		h.sendError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.IsReliable = reliable
+
+	// Exports can outlive the server write timeout; lift it for this response only
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.Warn(ctx, "[API_EXPORT_STATISTICS_DEADLINE] Could not clear write deadline", logging.Fields{
+			"error": err.Error(),
+		})
+	}
+
+	started := false
+	rows := 0
+	encoder := json.NewEncoder(w)
+
+	shape := h.shaper(r)
+	err = h.statsService.StreamStatistics(ctx, filter, func(stats *models.WeatherStatistics) error {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", `attachment; filename="weather_statistics.ndjson"`)
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		shaped, err := shape(stats)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(shaped); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushRows == 0 {
+			if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		h.logger.Error(ctx, "[API_EXPORT_STATISTICS_ERROR] Failed to export statistics", logging.Fields{
+			"filter":        filter,
+			"rows_exported": rows,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/stats/export")
+		if !started {
+			h.sendError(w, r, "failed to export statistics", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// An empty export is still a successful, empty NDJSON document
+	if !started {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/stats/export", "GET", "200")
+}
+
+// PatchStatistics handles PATCH /api/weather/stats/{station_id}/{year}
+func (h *WeatherHandler) PatchStatistics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/stats/{station_id}/{year}").Observe(duration.Seconds())
+	}()
+
+	vars := mux.Vars(r)
+	stationID := vars["station_id"]
+
+	year, err := strconv.Atoi(vars["year"])
+	if err != nil {
+		h.sendError(w, r, "invalid year", http.StatusBadRequest)
+		return
+	}
+
+	var patch models.StatisticsPatch
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.sendError(w, r, fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.sendError(w, r, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.statsService.PatchStatistics(ctx, stationID, year, &patch)
+	if err != nil {
+		var validationErr *models.ValidationError
+		if errors.As(err, &validationErr) {
+			h.sendError(w, r, validationErr.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			h.sendError(w, r, notFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error(ctx, "[API_PATCH_STATISTICS_ERROR] Failed to patch statistics", logging.Fields{
+			"station_id": stationID,
+			"year":       year,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/stats/{station_id}/{year}")
+		h.sendError(w, r, "failed to update statistics", http.StatusInternalServerError)
+		return
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/stats/{station_id}/{year}", "PATCH", "200")
+	h.sendJSON(w, r, stats, http.StatusOK)
+}
+
+// GetAvailableYears handles GET /api/weather/years
+func (h *WeatherHandler) GetAvailableYears(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		h.metrics.APIRequestDuration.WithLabelValues("/api/weather/years").Observe(duration.Seconds())
+	}()
+
+	var stationID *string
+	if id := r.URL.Query().Get("station_id"); id != "" {
+		stationID = &id
 	}
 
-	h.metrics.RecordAPIRequest("/api/weather/stats", "GET", "200")
-	h.sendJSON(w, response, http.StatusOK)
+	years, err := h.weatherService.ListAvailableYears(ctx, stationID)
+	if err != nil {
+		h.logger.Error(ctx, "[API_GET_YEARS_ERROR] Failed to get available years", logging.Fields{
+			"station_id": stationID,
+		}, err)
+		h.metrics.RecordAPIError("internal_error", "/api/weather/years")
+		h.sendError(w, r, "failed to retrieve available years", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"years": years,
+	}
+	if stationID != nil {
+		response["station_id"] = *stationID
+	}
+
+	h.metrics.RecordAPIRequest("/api/weather/years", "GET", "200")
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
 // HealthCheck handles GET /health
@@ -229,14 +632,329 @@ func (h *WeatherHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.logger.Debug(ctx, "[HEALTH_CHECK] Health check requested", logging.Fields{})
-	h.sendJSON(w, status, http.StatusOK)
+	h.sendJSON(w, r, status, http.StatusOK)
+}
+
+// parsePagination extracts page and limit query parameters with defaults
+// Invalid or out-of-range values fall back to page 1 and limit 100
+func parsePagination(r *http.Request) (page, limit, offset int) {
+	pageStr := r.URL.Query().Get("page")
+	limitStr := r.URL.Query().Get("limit")
+
+	// Default pagination
+	page = 1
+	limit = 100
+
+	if pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
+			limit = l
+		}
+	}
+
+	offset = (page - 1) * limit
+	return page, limit, offset
+}
+
+// parseObservationFilter builds an observation filter from station_id, start_date, end_date,
+// created_after, and has_max_temp/has_min_temp/has_precip=false
+// Pagination is left to the caller
+func (h *WeatherHandler) parseObservationFilter(r *http.Request) (repository.ObservationFilter, error) {
+	var filter repository.ObservationFilter
+
+	if stationID := r.URL.Query().Get("station_id"); stationID != "" {
+		filter.StationID = &stationID
+	}
+
+	if startDateStr := r.URL.Query().Get("start_date"); startDateStr != "" {
+		startDate, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			return filter, errors.New("invalid start_date format, expected YYYY-MM-DD")
+		}
+		filter.StartDate = &startDate
+	}
+
+	if endDateStr := r.URL.Query().Get("end_date"); endDateStr != "" {
+		endDate, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			return filter, errors.New("invalid end_date format, expected YYYY-MM-DD")
+		}
+		filter.EndDate = &endDate
+	}
+
+	if createdAfterStr := r.URL.Query().Get("created_after"); createdAfterStr != "" {
+		createdAfter, err := parseTimestamp(createdAfterStr)
+		if err != nil {
+			return filter, errors.New("invalid created_after format, expected RFC 3339 timestamp or YYYY-MM-DD")
+		}
+		filter.CreatedAfter = &createdAfter
+	}
+
+	for _, field := range []string{"max_temp", "min_temp", "precip"} {
+		has, err := parseBoolParam(r, "has_"+field)
+		if err != nil {
+			return filter, err
+		}
+		if has == nil {
+			continue
+		}
+		if *has {
+			return filter, fmt.Errorf("has_%s=true is not supported, only has_<field>=false filters for missing values", field)
+		}
+		if filter.MissingField != nil {
+			return filter, errors.New("only one has_<field>=false filter may be given")
+		}
+		missing := field
+		filter.MissingField = &missing
+	}
+
+	if err := h.validateDateRange(filter.StartDate, filter.EndDate); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// parseTimestamp accepts an RFC 3339 timestamp or a YYYY-MM-DD date (midnight UTC)
+func parseTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// parseDateParam parses an optional YYYY-MM-DD query parameter
+// Returns nil when the parameter is absent
+func parseDateParam(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s format, expected YYYY-MM-DD", name)
+	}
+
+	return &date, nil
+}
+
+// parseYearParam parses an optional year query parameter within the schema's valid range
+// Returns nil when the parameter is absent
+func parseYearParam(r *http.Request, name string) (*int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	maxYear := time.Now().UTC().Year()
+	year, err := strconv.Atoi(value)
+	if err != nil || year < minYear || year > maxYear {
+		return nil, fmt.Errorf("invalid %s, expected integer between %d and %d", name, minYear, maxYear)
+	}
+
+	return &year, nil
+}
+
+// parseBoolParam parses an optional true/false query parameter
+// Returns nil when the parameter is absent
+func parseBoolParam(r *http.Request, name string) (*bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, expected true or false", name)
+	}
+
+	return &parsed, nil
+}
+
+// Computed fields GET /api/weather adds on request via ?include=
+const includeDiurnalRange = "diurnal_range"
+
+var observationIncludes = []string{includeDiurnalRange}
+
+// observationFields are the fields GET /api/weather can select via ?fields=
+var observationFields = jsonFieldNames(reflect.TypeOf(models.WeatherObservation{}))
+
+// observationColumns are the arrays of a columnar GET /api/weather response,
+// plus diurnal_range when requested, limited to the columns whose fields are
+// in fields (nil keeps every column)
+func observationColumns(diurnalRange bool, fields []string) []columnarColumn {
+	columns := []columnarColumn{
+		{"dates", "observation_date"},
+		{"max_temp", "max_temperature_celsius"},
+		{"min_temp", "min_temperature_celsius"},
+		{"precip", "precipitation_cm"},
+	}
+	if diurnalRange {
+		columns = append(columns, columnarColumn{"diurnal_range", "diurnal_range_celsius"})
+	}
+
+	if len(fields) > 0 {
+		columns = slices.DeleteFunc(columns, func(column columnarColumn) bool {
+			return !slices.Contains(fields, column.field)
+		})
+	}
+	return columns
+}
+
+// parseColumnarParam reports whether ?format=columnar was requested
+// format=json selects the default response explicitly
+func parseColumnarParam(r *http.Request) (bool, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "", formatJSON:
+		return false, nil
+	case formatColumnar:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid format %q, expected %s or %s", format, formatJSON, formatColumnar)
+	}
+}
+
+// parseFieldsParam parses the comma-separated fields parameter, rejecting
+// names not in allowed; nil means every field
+func parseFieldsParam(r *http.Request, allowed []string) ([]string, error) {
+	value := r.URL.Query().Get("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("invalid field %q, expected one of %s", name, strings.Join(allowed, ", "))
+		}
+		if !slices.Contains(fields, name) {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields, nil
+}
+
+// parseIncludeParam parses the comma-separated include parameter into a set,
+// rejecting names not in allowed
+func parseIncludeParam(r *http.Request, allowed []string) (map[string]bool, error) {
+	include := make(map[string]bool)
+	value := r.URL.Query().Get("include")
+	if value == "" {
+		return include, nil
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("invalid include %q, expected one of %s", name, strings.Join(allowed, ", "))
+		}
+		include[name] = true
+	}
+
+	return include, nil
+}
+
+// parseDateFormat reports whether observation dates should be written as
+// YYYY-MM-DD, from the date_format parameter or the configured default
+func (h *WeatherHandler) parseDateFormat(r *http.Request) (bool, error) {
+	format := r.URL.Query().Get("date_format")
+	if format == "" {
+		format = h.options.DateFormat
+	}
+
+	switch format {
+	case DateFormatDate:
+		return true, nil
+	case DateFormatDateTime, "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid date_format %q, expected %s or %s", format, DateFormatDateTime, DateFormatDate)
+	}
+}
+
+// observationForDateFormat wraps obs for date-only serialization when dateOnly is set
+func observationForDateFormat(obs *models.WeatherObservation, dateOnly bool) interface{} {
+	if dateOnly {
+		return models.DateOnlyObservation{WeatherObservation: obs}
+	}
+	return obs
+}
+
+// parseFloatParam parses an optional finite number query parameter
+// Returns nil when the parameter is absent
+func parseFloatParam(r *http.Request, name string) (*float64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return nil, fmt.Errorf("invalid %s, expected a number", name)
+	}
+
+	return &parsed, nil
+}
+
+// validateDateRange enforces ordering and the configured maximum span of a date range
+func (h *WeatherHandler) validateDateRange(from, to *time.Time) error {
+	return checkDateRange(from, to, h.options.MaxQueryRangeDays)
+}
+
+// checkDateRange enforces ordering and a maximum span of maxDays (0 disables)
+// Open-ended ranges (either bound nil) are not limited: the span is only
+// known, and only capped, when both bounds are given
+func checkDateRange(from, to *time.Time, maxDays int) error {
+	if from == nil || to == nil {
+		return nil
+	}
+
+	if to.Before(*from) {
+		return fmt.Errorf("end of date range must not be before start")
+	}
+
+	if days := int(to.Sub(*from).Hours()/24) + 1; maxDays > 0 && days > maxDays {
+		return fmt.Errorf("date range too large: %d days requested, maximum allowed is %d days", days, maxDays)
+	}
+
+	return nil
 }
 
 // sendJSON sends a JSON response
-func (h *WeatherHandler) sendJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+func (h *WeatherHandler) sendJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
+	shaped, err := h.shaper(r)(data)
+	if err != nil {
+		h.logger.Warn(context.Background(), "[API_SHAPE_ERROR] Failed to shape response, sending unmodified", logging.Fields{
+			"error": err.Error(),
+		})
+		shaped = data
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(data)
+
+	encoder := json.NewEncoder(w)
+	if h.prettyJSON(r) {
+		encoder.SetIndent("", "  ")
+	}
+	encoder.Encode(shaped)
+}
+
+// prettyJSON reports whether JSON responses should be indented: the pretty
+// query parameter when it is a valid boolean, otherwise Options.PrettyJSON
+func (h *WeatherHandler) prettyJSON(r *http.Request) bool {
+	if pretty, err := strconv.ParseBool(r.URL.Query().Get("pretty")); err == nil {
+		return pretty
+	}
+	return h.options.PrettyJSON
 }
 
 // sendError sends an error response
@@ -249,12 +967,46 @@ func (h *WeatherHandler) sendError(w http.ResponseWriter, r *http.Request, messa
 		Code:    statusCode,
 	}
 
-	h.sendJSON(w, response, statusCode)
+	h.sendJSON(w, r, response, statusCode)
 }
 
 // RegisterRoutes registers all weather API routes
 func (h *WeatherHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/api/weather", h.GetObservations).Methods("GET")
+	router.HandleFunc("/api/weather/count", h.CountObservations).Methods("GET")
+	router.HandleFunc("/api/weather/latest", h.GetLatestObservations).Methods("GET")
 	router.HandleFunc("/api/weather/stats", h.GetStatistics).Methods("GET")
+	router.HandleFunc("/api/weather/stats/export", h.ExportStatistics).Methods("GET")
+	router.HandleFunc("/api/weather/stats/{station_id}/{year:[0-9]+}", h.PatchStatistics).Methods("PATCH")
+	router.HandleFunc("/api/weather/compare", h.CompareStations).Methods("GET")
+	router.HandleFunc("/api/weather/years", h.GetAvailableYears).Methods("GET")
+	router.HandleFunc("/api/weather/extremes", h.GetExtremes).Methods("GET")
+	router.HandleFunc("/api/weather/missing", h.GetMissingDates).Methods("GET")
+	router.HandleFunc("/api/weather/moving-average", h.GetMovingAverage).Methods("GET")
+	router.HandleFunc("/api/weather/histogram", h.GetHistogram).Methods("GET")
+	router.HandleFunc("/api/weather/frost-free", h.GetFrostFreeSeason).Methods("GET")
+	router.HandleFunc("/api/weather/degree-days", h.GetDegreeDays).Methods("GET")
+	router.HandleFunc("/api/weather/ranking", h.GetRanking).Methods("GET")
+	router.HandleFunc("/api/weather/yoy", h.GetYearOverYearChange).Methods("GET")
+	router.HandleFunc("/api/weather/streak", h.GetLongestStreak).Methods("GET")
+	router.HandleFunc("/api/weather/correlation", h.GetCorrelation).Methods("GET")
+	router.HandleFunc("/api/weather/anomaly", h.GetDegreeDayAnomaly).Methods("GET")
+	router.HandleFunc("/api/weather/events", h.GetWeatherEvents).Methods("GET")
+	router.HandleFunc("/api/weather/diurnal-range", h.GetDiurnalRange).Methods("GET")
+	router.HandleFunc("/api/weather/{station_id}/{date:[0-9]{4}-[0-9]{2}-[0-9]{2}}/history", h.GetObservationHistory).Methods("GET")
+	router.HandleFunc("/api/stats/global", h.GetGlobalStats).Methods("GET")
+	router.HandleFunc("/api/stations", h.GetStations).Methods("GET")
+	router.HandleFunc("/api/stations/search", h.SearchStations).Methods("GET")
+	router.HandleFunc("/api/stations/missing-stats", h.GetStationsMissingStatistics).Methods("GET")
+	router.HandleFunc("/api/stations/{station_id}/quality", h.GetStationQuality).Methods("GET")
+	router.HandleFunc("/api/stations/{station_id}/monthly-counts", h.GetMonthlyObservationCounts).Methods("GET")
+	router.HandleFunc("/api/stations/{station_id}/normals", h.GetClimateNormals).Methods("GET")
+	router.HandleFunc("/api/ingestion/failures", h.GetFailedRecords).Methods("GET")
+	router.HandleFunc("/api/ingestion/runs", h.GetIngestionRuns).Methods("GET")
+	router.HandleFunc("/api/ingestion/run", h.StartIngestionRun).Methods("POST")
+	router.HandleFunc("/api/ingestion/run/{id}", h.GetIngestionJob).Methods("GET")
+	router.HandleFunc("/api/admin/observations/compact", h.CompactDuplicates).Methods("POST")
+	router.HandleFunc("/api/admin/observations/backfill", h.BackfillMissingDates).Methods("POST")
 	router.HandleFunc("/health", h.HealthCheck).Methods("GET")
+	router.HandleFunc("/health/deep", h.DeepHealthCheck).Methods("GET")
 }
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCheckDateRange(t *testing.T) {
+	date := func(s string) *time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &d
+	}
+
+	tests := []struct {
+		name    string
+		from    *time.Time
+		to      *time.Time
+		maxDays int
+		wantErr bool
+	}{
+		{name: "unbounded", maxDays: 10},
+		{name: "within limit", from: date("2024-01-01"), to: date("2024-01-10"), maxDays: 10},
+		{name: "over limit", from: date("2024-01-01"), to: date("2024-01-11"), maxDays: 10, wantErr: true},
+		{name: "reversed", from: date("2024-01-10"), to: date("2024-01-01"), maxDays: 0, wantErr: true},
+		{name: "limit disabled", from: date("1900-01-01"), to: date("2024-01-01"), maxDays: 0},
+		{name: "from only", from: date("1900-01-01"), maxDays: 10},
+		{name: "to only", to: date("2024-01-01"), maxDays: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDateRange(tt.from, tt.to, tt.maxDays)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkDateRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGetObservations_DateRangeLimit tests the range limit at its default
+// of 3660 days: only ranges with both bounds are capped
+func TestGetObservations_DateRangeLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"no bounds", "", http.StatusOK},
+		{"start only", "?start_date=2000-01-01", http.StatusOK},
+		{"end only", "?end_date=2024-01-01", http.StatusOK},
+		{"both within limit", "?start_date=2015-01-01&end_date=2024-12-31", http.StatusOK},
+		{"both over limit", "?start_date=2000-01-01&end_date=2024-01-01", http.StatusBadRequest},
+		{"reversed", "?start_date=2024-01-02&end_date=2024-01-01", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepository{}
+			opts := DefaultOptions()
+			opts.MaxQueryRangeDays = 3660
+			h := newTestHandler(t, repo, opts)
+
+			if got := serve(h.GetObservations, "/api/weather"+tt.query); got != tt.want {
+				t.Errorf("GET /api/weather%s status = %d, want %d", tt.query, got, tt.want)
+			}
+			if reached := repo.calls > 0; reached != (tt.want == http.StatusOK) {
+				t.Errorf("repository reached = %v, want %v", reached, tt.want == http.StatusOK)
+			}
+		})
+	}
+}
+
+// TestGetMissingDates_HardCap tests that the missing-dates series stays capped
+// when the configured range limit is disabled or larger
+func TestGetMissingDates_HardCap(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxDays int
+		query   string
+		want    int
+	}{
+		{"within cap", 0, "from=2015-01-01&to=2024-12-31", http.StatusOK},
+		{"over cap with limit disabled", 0, "from=2000-01-01&to=2024-01-01", http.StatusBadRequest},
+		{"over cap with larger limit", 100000, "from=2000-01-01&to=2024-01-01", http.StatusBadRequest},
+		{"over smaller limit", 30, "from=2024-01-01&to=2024-03-01", http.StatusBadRequest},
+		{"start only", 3660, "from=2024-01-01", http.StatusBadRequest},
+		{"no bounds", 3660, "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.MaxQueryRangeDays = tt.maxDays
+			h := newTestHandler(t, &stubRepository{}, opts)
+
+			target := "/api/weather/missing?station_id=USC00110072&" + tt.query
+			if got := serve(h.GetMissingDates, target); got != tt.want {
+				t.Errorf("GET %s status = %d, want %d", target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"weather-platform/pkg/ctxkeys"
+)
+
+// Access log formats for AccessLog
+const (
+	AccessLogCommon   = "common"
+	AccessLogCombined = "combined"
+	AccessLogJSON     = "json"
+)
+
+// clfTimeFormat is the Apache %t timestamp layout
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// IsValidAccessLogFormat reports whether format is a supported access log format
+func IsValidAccessLogFormat(format string) bool {
+	switch format {
+	case AccessLogCommon, AccessLogCombined, AccessLogJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// accessLogEntry is one JSON access log line
+type accessLogEntry struct {
+	Time       string  `json:"time"`
+	RemoteAddr string  `json:"remote_addr"`
+	User       string  `json:"user,omitempty"`
+	Method     string  `json:"method"`
+	URI        string  `json:"uri"`
+	Proto      string  `json:"proto"`
+	Status     int     `json:"status"`
+	Bytes      int64   `json:"bytes"`
+	DurationMs float64 `json:"duration_ms"`
+	Referer    string  `json:"referer,omitempty"`
+	UserAgent  string  `json:"user_agent,omitempty"`
+	RequestID  string  `json:"request_id,omitempty"`
+}
+
+// AccessLog writes one line per request to out in the given format
+// Common and combined follow the Apache layouts exactly so existing log
+// tooling can parse them; duration is only included in the JSON format.
+// Register it early so requests rejected by later middleware are logged too.
+func AccessLog(format string, out io.Writer, proxies TrustedProxies) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, r)
+
+			line := formatAccessLog(format, r, proxies.clientIP(r), start, time.Since(start), recorder.statusCode(), recorder.bytes)
+
+			mu.Lock()
+			io.WriteString(out, line)
+			mu.Unlock()
+		})
+	}
+}
+
+// formatAccessLog renders a single newline-terminated access log line
+func formatAccessLog(format string, r *http.Request, clientIP string, start time.Time, duration time.Duration, status int, bytes int64) string {
+	// The user field is unquoted, so spaces are encoded to keep it one token
+	user, _, _ := r.BasicAuth()
+
+	if format == AccessLogJSON {
+		requestID, _ := ctxkeys.RequestIDFrom(r.Context())
+		entry, _ := json.Marshal(accessLogEntry{
+			Time:       start.Format(time.RFC3339Nano),
+			RemoteAddr: clientIP,
+			User:       user,
+			Method:     r.Method,
+			URI:        r.RequestURI,
+			Proto:      r.Proto,
+			Status:     status,
+			Bytes:      bytes,
+			DurationMs: float64(duration.Microseconds()) / 1000,
+			Referer:    r.Referer(),
+			UserAgent:  r.UserAgent(),
+			RequestID:  requestID,
+		})
+		return string(entry) + "\n"
+	}
+
+	size := "-"
+	if bytes > 0 {
+		size = strconv.FormatInt(bytes, 10)
+	}
+
+	line := fmt.Sprintf(`%s - %s [%s] "%s %s %s" %d %s`,
+		clfField(clientIP), clfField(strings.ReplaceAll(clfEscape(user), " ", "%20")), start.Format(clfTimeFormat),
+		clfEscape(r.Method), clfEscape(r.RequestURI), clfEscape(r.Proto), status, size)
+
+	if format == AccessLogCombined {
+		line += fmt.Sprintf(` "%s" "%s"`, clfField(clfEscape(r.Referer())), clfField(clfEscape(r.UserAgent())))
+	}
+
+	return line + "\n"
+}
+
+// clfField returns "-" for empty values, as Apache does
+func clfField(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// clfEscape escapes quotes, backslashes and control characters so a client
+// cannot break out of a quoted field or forge additional log lines
+func clfEscape(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// responseRecorder captures the status code and bytes written by a handler
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader records the first status code written
+func (rw *responseRecorder) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Write counts body bytes; the first write implies a 200 status
+func (rw *responseRecorder) Write(p []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (Flush, deadlines)
+func (rw *responseRecorder) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// statusCode returns the recorded status, 200 when the handler wrote nothing
+func (rw *responseRecorder) statusCode() int {
+	if rw.status == 0 {
+		return http.StatusOK
+	}
+	return rw.status
+}
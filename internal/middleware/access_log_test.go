@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestFormatAccessLog(t *testing.T) {
+	start := time.Date(2024, 3, 5, 14, 7, 9, 0, time.FixedZone("", -7*3600))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/weather?station_id=USC00110072", nil)
+	r.Header.Set("Referer", "https://example.com/")
+	r.Header.Set("User-Agent", `curl/8.0 "quoted"`)
+	r.SetBasicAuth("admin", "secret")
+
+	tests := []struct {
+		name   string
+		format string
+		bytes  int64
+		want   string
+	}{
+		{
+			"common",
+			AccessLogCommon, 512,
+			`10.0.0.1 - admin [05/Mar/2024:14:07:09 -0700] "GET /api/weather?station_id=USC00110072 HTTP/1.1" 200 512` + "\n",
+		},
+		{
+			"common without body",
+			AccessLogCommon, 0,
+			`10.0.0.1 - admin [05/Mar/2024:14:07:09 -0700] "GET /api/weather?station_id=USC00110072 HTTP/1.1" 200 -` + "\n",
+		},
+		{
+			"combined",
+			AccessLogCombined, 512,
+			`10.0.0.1 - admin [05/Mar/2024:14:07:09 -0700] "GET /api/weather?station_id=USC00110072 HTTP/1.1" 200 512 "https://example.com/" "curl/8.0 \"quoted\""` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatAccessLog(tt.format, r, "10.0.0.1", start, 25*time.Millisecond, http.StatusOK, tt.bytes)
+			if got != tt.want {
+				t.Errorf("formatAccessLog() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatAccessLog_EscapesControlCharacters tests that a request cannot forge log lines
+func TestFormatAccessLog_EscapesControlCharacters(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("User-Agent", "evil\n127.0.0.1 - - [forged]")
+
+	got := formatAccessLog(AccessLogCombined, r, "10.0.0.1", time.Now(), 0, http.StatusOK, 0)
+	if bytes.Count([]byte(got), []byte("\n")) != 1 {
+		t.Errorf("formatAccessLog() produced multiple lines: %q", got)
+	}
+}
+
+// TestAccessLog tests status, byte counts and duration captured through the middleware
+func TestAccessLog(t *testing.T) {
+	var out bytes.Buffer
+	handler := AccessLog(AccessLogJSON, &out, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not "))
+		w.Write([]byte("found"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	var entry accessLogEntry
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("access log is not JSON: %v (%q)", err, out.String())
+	}
+	if entry.Status != http.StatusNotFound || entry.Bytes != 9 || entry.URI != "/missing" || entry.RemoteAddr != "192.0.2.1" {
+		t.Errorf("entry = %+v, want status 404, 9 bytes, uri /missing, remote 192.0.2.1", entry)
+	}
+	if entry.DurationMs < 0 {
+		t.Errorf("duration_ms = %v, want non-negative", entry.DurationMs)
+	}
+
+	// A handler that only writes a body is logged as 200
+	out.Reset()
+	AccessLog(AccessLogCommon, &out, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if !regexp.MustCompile(`^192\.0\.2\.1 - - \[[^\]]+\] "GET /health HTTP/1\.1" 200 2\n$`).Match(out.Bytes()) {
+		t.Errorf("common log line = %q", out.String())
+	}
+}
+
+// TestAccessLog_Flush tests that streaming handlers can still flush through the recorder
+func TestAccessLog_Flush(t *testing.T) {
+	var out bytes.Buffer
+	handler := AccessLog(AccessLogCommon, &out, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush() error = %v", err)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/weather/stats/export", nil))
+
+	if !rec.Flushed {
+		t.Error("underlying writer was not flushed")
+	}
+}
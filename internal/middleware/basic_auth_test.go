@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"weather-platform/pkg/logging"
+)
+
+// TestBasicAuth tests route protection and credential checks
+func TestBasicAuth(t *testing.T) {
+	logger := logging.NewStructuredLogger("test", "1.0.0", logging.ErrorLevel)
+	logger.SetOutput(io.Discard)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cfg := BasicAuthConfig{
+		Username:          "admin",
+		Password:          "secret",
+		ProtectedPrefixes: []string{"/api/admin"},
+		ProtectWrites:     true,
+	}
+
+	tests := []struct {
+		name       string
+		cfg        BasicAuthConfig
+		method     string
+		path       string
+		user, pass string
+		wantStatus int
+	}{
+		{"open read route", cfg, "GET", "/api/weather", "", "", http.StatusOK},
+		{"admin route without credentials", cfg, "GET", "/api/admin/compact", "", "", http.StatusUnauthorized},
+		{"admin route with wrong password", cfg, "GET", "/api/admin/compact", "admin", "wrong", http.StatusUnauthorized},
+		{"admin route with valid credentials", cfg, "GET", "/api/admin/compact", "admin", "secret", http.StatusOK},
+		{"write method without credentials", cfg, "PATCH", "/api/weather/stats/X/2000", "", "", http.StatusUnauthorized},
+		{"write method with valid credentials", cfg, "PATCH", "/api/weather/stats/X/2000", "admin", "secret", http.StatusOK},
+		{"unconfigured credentials fail closed", BasicAuthConfig{ProtectWrites: true}, "POST", "/api/ingestion/run", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.user != "" {
+				req.SetBasicAuth(tt.user, tt.pass)
+			}
+			rec := httptest.NewRecorder()
+
+			BasicAuth(tt.cfg, logger)(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 response missing WWW-Authenticate header")
+			}
+		})
+	}
+}
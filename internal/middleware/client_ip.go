@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustedProxies lists the networks whose forwarding headers are believed
+// An empty list trusts no proxy, so the client IP is always the TCP peer
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses CIDRs (or bare IPs, treated as single hosts)
+func ParseTrustedProxies(values []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// contains reports whether ip belongs to a trusted proxy network
+func (p TrustedProxies) contains(ip net.IP) bool {
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the originating client address for a request
+// X-Forwarded-For and X-Real-IP are only honored when the immediate peer is a
+// trusted proxy. X-Forwarded-For is walked right to left, skipping trusted
+// hops, so a client cannot spoof its address by prepending entries.
+func (p TrustedProxies) clientIP(r *http.Request) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		peer = host
+	}
+
+	peerIP := net.ParseIP(peer)
+	if peerIP == nil || !p.contains(peerIP) {
+		return peer
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				// Unparseable hop: stop rather than trust anything further left
+				break
+			}
+			if !p.contains(ip) || i == 0 {
+				return ip.String()
+			}
+		}
+	}
+
+	if realIP := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP.String()
+	}
+
+	return peer
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestClientIP tests forwarded-header handling against trusted proxies
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		proxies    TrustedProxies
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		want       string
+	}{
+		{"direct client", proxies, "203.0.113.7:5000", "", "", "203.0.113.7"},
+		{"untrusted peer cannot spoof", proxies, "203.0.113.7:5000", "1.2.3.4", "5.6.7.8", "203.0.113.7"},
+		{"no trusted proxies configured", nil, "10.0.0.1:5000", "1.2.3.4", "", "10.0.0.1"},
+		{"trusted proxy forwards client", proxies, "10.0.0.1:5000", "198.51.100.2", "", "198.51.100.2"},
+		{"trusted hops are skipped", proxies, "10.0.0.1:5000", "198.51.100.2, 10.1.1.1, 192.168.1.1", "", "198.51.100.2"},
+		{"prepended spoof is ignored", proxies, "10.0.0.1:5000", "1.2.3.4, 198.51.100.2", "", "198.51.100.2"},
+		{"all hops trusted uses leftmost", proxies, "10.0.0.1:5000", "10.2.2.2, 10.1.1.1", "", "10.2.2.2"},
+		{"real ip header from trusted proxy", proxies, "192.168.1.1:5000", "", "198.51.100.9", "198.51.100.9"},
+		{"malformed header falls back to peer", proxies, "10.0.0.1:5000", "garbage", "", "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/weather", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if got := tt.proxies.clientIP(req); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseTrustedProxiesInvalid tests rejection of malformed entries
+func TestParseTrustedProxiesInvalid(t *testing.T) {
+	for _, value := range []string{"10.0.0.0/33", "not-an-ip"} {
+		if _, err := ParseTrustedProxies([]string{value}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) expected error", value)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// InFlightTracker counts requests currently being served per route template
+// Used for the in-flight gauge and to report busy endpoints during shutdown
+type InFlightTracker struct {
+	mu     sync.Mutex
+	active map[string]int
+	gauge  *prometheus.GaugeVec
+}
+
+// NewInFlightTracker creates a tracker; gauge may be nil
+func NewInFlightTracker(gauge *prometheus.GaugeVec) *InFlightTracker {
+	return &InFlightTracker{
+		active: make(map[string]int),
+		gauge:  gauge,
+	}
+}
+
+// Middleware tracks each request for the duration of the wrapped handler
+func (t *InFlightTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := routeTemplate(r)
+		t.add(endpoint, 1)
+		defer t.add(endpoint, -1)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Total returns the number of requests currently in flight
+func (t *InFlightTracker) Total() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	total := 0
+	for _, count := range t.active {
+		total += count
+	}
+	return total
+}
+
+// Snapshot returns in-flight counts for endpoints with active requests
+func (t *InFlightTracker) Snapshot() map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snapshot := make(map[string]int, len(t.active))
+	for endpoint, count := range t.active {
+		snapshot[endpoint] = count
+	}
+	return snapshot
+}
+
+func (t *InFlightTracker) add(endpoint string, delta int) {
+	t.mu.Lock()
+	t.active[endpoint] += delta
+	if t.active[endpoint] <= 0 {
+		delete(t.active, endpoint)
+	}
+	t.mu.Unlock()
+
+	if t.gauge != nil {
+		t.gauge.WithLabelValues(endpoint).Add(float64(delta))
+	}
+}
+
+// routeTemplate returns the matched mux path template, keeping label cardinality bounded
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+	return "unmatched"
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestInFlightTracker tests per-route in-flight counting
+func TestInFlightTracker(t *testing.T) {
+	tracker := NewInFlightTracker(nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router := mux.NewRouter()
+	router.Use(tracker.Middleware)
+	router.HandleFunc("/api/weather/stats/{station_id}", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/weather/stats/X", nil))
+		close(done)
+	}()
+	<-started
+
+	if got := tracker.Total(); got != 1 {
+		t.Errorf("Total() during request = %d, want 1", got)
+	}
+	if got := tracker.Snapshot()["/api/weather/stats/{station_id}"]; got != 1 {
+		t.Errorf("Snapshot() route count = %d, want 1", got)
+	}
+
+	close(release)
+	<-done
+
+	if got := tracker.Total(); got != 0 {
+		t.Errorf("Total() after request = %d, want 0", got)
+	}
+	if len(tracker.Snapshot()) != 0 {
+		t.Errorf("Snapshot() after request = %v, want empty", tracker.Snapshot())
+	}
+}
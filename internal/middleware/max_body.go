@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxBodyBytes is the request body limit applied when none is configured
+const DefaultMaxBodyBytes int64 = 5 << 20
+
+// MaxBodySize limits request bodies on POST/PUT/PATCH/DELETE routes
+// Requests declaring a larger Content-Length are rejected with 413 up front;
+// chunked or understated bodies are cut off by http.MaxBytesReader, which
+// handlers surface as 413 via *http.MaxBytesError. A limit <= 0 uses the default.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isWriteMethod(r.Method) || r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > limit {
+				writeError(w, fmt.Sprintf("request body exceeds %d bytes", limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestMaxBodySize tests body limits on write routes
+func TestMaxBodySize(t *testing.T) {
+	// Mirrors handler behavior: read the body and map MaxBytesError to 413
+	readAll := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		chunked    bool
+		wantStatus int
+	}{
+		{"write within limit", "POST", strings.Repeat("a", 10), false, http.StatusOK},
+		{"write over limit by content length", "PATCH", strings.Repeat("a", 11), false, http.StatusRequestEntityTooLarge},
+		{"write over limit without content length", "POST", strings.Repeat("a", 11), true, http.StatusRequestEntityTooLarge},
+		{"read route is not limited", "GET", strings.Repeat("a", 11), false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/weather/stats/X/2000", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+
+			MaxBodySize(10)(readAll).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// errorResponse mirrors handlers.ErrorResponse so middleware rejections
+// have the same shape as handler errors
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+// writeError sends a JSON error response
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(errorResponse{
+		Error:   http.StatusText(statusCode),
+		Message: message,
+		Code:    statusCode,
+	})
+}
+
+// isWriteMethod reports whether the HTTP method mutates state
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import "net/http"
+
+// ReadOnly rejects POST/PUT/PATCH/DELETE requests with 405 so a query-only
+// deployment cannot modify data, whatever write routes are registered
+func ReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWriteMethod(r.Method) {
+			w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+			writeError(w, "server is in read-only mode", http.StatusMethodNotAllowed)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
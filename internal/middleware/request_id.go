@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"weather-platform/pkg/ctxkeys"
+)
+
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// RequestID attaches a request ID to the request context for log correlation
+// A well-formed incoming X-Request-ID is reused; otherwise a random ID is
+// generated. The ID is echoed in the response header.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(ctxkeys.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// validRequestID accepts non-empty printable ASCII IDs of bounded length
+// so client input cannot inject control characters into logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns 16 random bytes hex-encoded
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
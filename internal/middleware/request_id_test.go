@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"weather-platform/pkg/ctxkeys"
+)
+
+// TestRequestID tests request ID propagation into the context and response
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = ctxkeys.RequestIDFrom(r.Context())
+	}))
+
+	tests := []struct {
+		name     string
+		incoming string
+		wantKept bool
+	}{
+		{"no header generates an ID", "", false},
+		{"well-formed header is reused", "abc-123", true},
+		{"control characters are replaced", "abc\n123", false},
+		{"overlong header is replaced", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/weather", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if seen == "" {
+				t.Fatal("request ID missing from context")
+			}
+			if got := rec.Header().Get(RequestIDHeader); got != seen {
+				t.Errorf("response header = %q, context = %q", got, seen)
+			}
+			if (seen == tt.incoming) != tt.wantKept {
+				t.Errorf("request ID = %q, incoming %q, want kept %v", seen, tt.incoming, tt.wantKept)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package models
+
+import (
+	"time"
+)
+
+// DailyComparison represents aligned daily values for two stations
+// NULL values indicate the station has no observation (or a missing value) for that day
+type DailyComparison struct {
+	Date          time.Time `json:"date" db:"observation_date"`
+	StationAValue *float64  `json:"station_a_value" db:"station_a_value"`
+	StationBValue *float64  `json:"station_b_value" db:"station_b_value"`
+	Difference    *float64  `json:"difference" db:"difference"`
+}
+
+// MovingAveragePoint represents a metric value and its trailing moving average
+// SampleCount is below the requested window near the start of the range
+type MovingAveragePoint struct {
+	Date          time.Time `json:"date" db:"observation_date"`
+	Value         float64   `json:"value" db:"value"`
+	MovingAverage float64   `json:"moving_average" db:"moving_average"`
+	SampleCount   int       `json:"sample_count" db:"sample_count"`
+}
+
+// DiurnalRange is one day's spread between maximum and minimum temperature
+// Only days with both temperatures present have a DiurnalRange
+type DiurnalRange struct {
+	StationID             string    `json:"station_id" db:"station_id"`
+	Date                  time.Time `json:"date" db:"observation_date"`
+	MaxTemperatureCelsius float64   `json:"max_temperature_celsius" db:"max_temperature_celsius"`
+	MinTemperatureCelsius float64   `json:"min_temperature_celsius" db:"min_temperature_celsius"`
+	RangeCelsius          float64   `json:"range_celsius" db:"range_celsius"`
+}
+
+// HistogramBin represents the number of observations within [BinStart, BinEnd)
+// The last bin also includes values equal to BinEnd
+type HistogramBin struct {
+	BinStart float64 `json:"bin_start"`
+	BinEnd   float64 `json:"bin_end"`
+	Count    int     `json:"count"`
+}
+
+// Frost-free season classifications
+const (
+	FrostSeasonNormal   = "normal"
+	FrostSeasonNoFrost  = "no_frost"
+	FrostSeasonAllFrost = "all_frost"
+)
+
+// FrostFreeSeason represents the growing season between the last spring frost
+// (before July 1) and the first autumn frost (on or after July 1) of a year.
+// A frost day has a minimum temperature at or below 0°C.
+type FrostFreeSeason struct {
+	StationID        string     `json:"station_id"`
+	Year             int        `json:"year"`
+	Status           string     `json:"status"`
+	LastSpringFrost  *time.Time `json:"last_spring_frost"`
+	FirstAutumnFrost *time.Time `json:"first_autumn_frost"`
+	SeasonStart      *time.Time `json:"season_start"`
+	SeasonEnd        *time.Time `json:"season_end"`
+	LengthDays       int        `json:"length_days"`
+}
+
+// StationRanking represents a station's position in a yearly statistics leaderboard
+type StationRanking struct {
+	Rank             int     `json:"rank" db:"rank"`
+	StationID        string  `json:"station_id" db:"station_id"`
+	Year             int     `json:"year" db:"year"`
+	Metric           string  `json:"metric"`
+	Value            float64 `json:"value" db:"value"`
+	ObservationCount int     `json:"observation_count" db:"observation_count"`
+}
+
+// YearOverYearChange represents a station's yearly statistics metric and its
+// change from the previous year with statistics (PreviousYear; gaps are possible)
+type YearOverYearChange struct {
+	Year         int      `json:"year" db:"year"`
+	Value        *float64 `json:"value" db:"value"`
+	PreviousYear *int     `json:"previous_year" db:"previous_year"`
+	Delta        *float64 `json:"delta" db:"delta"`
+}
+
+// Correlation is the Pearson correlation between two observation metrics
+// Coefficient is nil when fewer than two rows have both values or either
+// metric does not vary
+type Correlation struct {
+	Coefficient *float64 `json:"coefficient" db:"coefficient"`
+	SampleSize  int      `json:"sample_size" db:"sample_size"`
+}
+
+// Streak is a run of consecutive days meeting a condition
+// A zero Length means no day met it, and the dates are then nil
+type Streak struct {
+	Length    int        `json:"length" db:"length"`
+	StartDate *time.Time `json:"start_date" db:"start_date"`
+	EndDate   *time.Time `json:"end_date" db:"end_date"`
+}
+
+// DegreeDayAnomaly compares a station-year's degree-day total with the
+// station's long-term normal, the mean total of its other sufficiently
+// complete years
+// The comparison fields are nil when SufficientData is false; Reason then
+// says which count fell short
+type DegreeDayAnomaly struct {
+	StationID          string   `json:"station_id"`
+	Year               int      `json:"year"`
+	Metric             string   `json:"metric"`
+	BaseCelsius        float64  `json:"base_celsius"`
+	Value              *float64 `json:"value"`
+	Normal             *float64 `json:"normal"`
+	AbsoluteDifference *float64 `json:"absolute_difference"`
+	PercentDifference  *float64 `json:"percent_difference"`
+	DaysCounted        int      `json:"days_counted"`
+	NormalYears        int      `json:"normal_years"`
+	SufficientData     bool     `json:"sufficient_data"`
+	Reason             string   `json:"reason,omitempty"`
+}
+
+// DegreeDays represents heating, cooling and growing degree days for a station-year
+// Daily mean is (max + min) / 2; days missing either temperature are excluded.
+// Growing degree days clamp both temperatures to between the base and 30°C first.
+type DegreeDays struct {
+	StationID         string  `json:"station_id"`
+	Year              int     `json:"year" db:"year"`
+	BaseCelsius       float64 `json:"base_celsius"`
+	HeatingDegreeDays float64 `json:"heating_degree_days" db:"heating_degree_days"`
+	CoolingDegreeDays float64 `json:"cooling_degree_days" db:"cooling_degree_days"`
+	GrowingDegreeDays float64 `json:"growing_degree_days" db:"growing_degree_days"`
+	DaysCounted       int     `json:"days_counted" db:"days_counted"`
+}
+
+// MonthlyObservationCount is the number of stored observations in one month
+type MonthlyObservationCount struct {
+	Month            int `json:"month" db:"month"`
+	ObservationCount int `json:"observation_count" db:"observation_count"`
+}
+
+// StationObservationCount pairs a station with its number of stored observations
+type StationObservationCount struct {
+	StationID        string `json:"station_id" db:"station_id"`
+	ObservationCount int    `json:"observation_count" db:"observation_count"`
+}
+
+// YearlyDataQuality represents the share of valid values in a station-year
+// Ratios are NULL when the year has no observations
+type YearlyDataQuality struct {
+	Year               int      `json:"year"`
+	ObservationCount   int      `json:"observation_count"`
+	MaxTempRatio       *float64 `json:"max_temp_ratio"`
+	MinTempRatio       *float64 `json:"min_temp_ratio"`
+	PrecipitationRatio *float64 `json:"precipitation_ratio"`
+}
+
+// DataQuality derives valid-value ratios from pre-calculated statistics
+func (s *WeatherStatistics) DataQuality() *YearlyDataQuality {
+	quality := &YearlyDataQuality{
+		Year:             s.Year,
+		ObservationCount: s.ObservationCount,
+	}
+
+	if s.ObservationCount == 0 {
+		return quality
+	}
+
+	ratio := func(valid int) *float64 {
+		value := float64(valid) / float64(s.ObservationCount)
+		return &value
+	}
+
+	quality.MaxTempRatio = ratio(s.ValidMaxTempCount)
+	quality.MinTempRatio = ratio(s.ValidMinTempCount)
+	quality.PrecipitationRatio = ratio(s.ValidPrecipitationCount)
+
+	return quality
+}
+
+// ExtremeRecord identifies the station and day holding an extreme value
+type ExtremeRecord struct {
+	StationID string    `json:"station_id" db:"station_id"`
+	Date      time.Time `json:"date" db:"observation_date"`
+	Value     float64   `json:"value" db:"value"`
+}
+
+// WeatherEvent is one station's observation meeting the thresholds of an event query
+type WeatherEvent struct {
+	StationID             string    `json:"station_id" db:"station_id"`
+	State                 string    `json:"state" db:"state"`
+	Date                  time.Time `json:"date" db:"observation_date"`
+	MaxTemperatureCelsius *float64  `json:"max_temperature_celsius" db:"max_temperature_celsius"`
+	MinTemperatureCelsius *float64  `json:"min_temperature_celsius" db:"min_temperature_celsius"`
+	PrecipitationCm       *float64  `json:"precipitation_cm" db:"precipitation_cm"`
+}
+
+// GlobalStats summarizes the whole dataset in one row for dashboard headers
+// The observation dates are NULL when there are no observations
+type GlobalStats struct {
+	StationCount          int64      `json:"station_count" db:"station_count"`
+	ObservationCount      int64      `json:"observation_count" db:"observation_count"`
+	ObservationCountExact bool       `json:"observation_count_exact" db:"observation_count_exact"`
+	FirstObservationDate  *time.Time `json:"first_observation_date" db:"first_observation_date"`
+	LastObservationDate   *time.Time `json:"last_observation_date" db:"last_observation_date"`
+}
+
+// WeatherExtremes holds record values; a field is NULL when no data qualifies
+type WeatherExtremes struct {
+	Hottest *ExtremeRecord `json:"hottest"`
+	Coldest *ExtremeRecord `json:"coldest"`
+	Wettest *ExtremeRecord `json:"wettest"`
+}
+
+// ClimateNormal is a station's average weather for one calendar day across all
+// years with observations on that day; the *Years fields count the years
+// contributing to each average
+type ClimateNormal struct {
+	Month                    int      `json:"month" db:"month"`
+	Day                      int      `json:"day" db:"day"`
+	AvgMaxTemperatureCelsius *float64 `json:"avg_max_temperature_celsius" db:"avg_max_temperature_celsius"`
+	AvgMinTemperatureCelsius *float64 `json:"avg_min_temperature_celsius" db:"avg_min_temperature_celsius"`
+	MeanPrecipitationCm      *float64 `json:"mean_precipitation_cm" db:"mean_precipitation_cm"`
+	YearCount                int      `json:"year_count" db:"year_count"`
+	MaxTempYears             int      `json:"max_temp_years" db:"max_temp_years"`
+	MinTempYears             int      `json:"min_temp_years" db:"min_temp_years"`
+	PrecipitationYears       int      `json:"precipitation_years" db:"precipitation_years"`
+}
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -23,6 +27,60 @@ type WeatherObservation struct {
 	MinTemperatureCelsius   *float64   `json:"min_temperature_celsius,omitempty" db:"min_temperature_celsius"`
 	PrecipitationCm         *float64   `json:"precipitation_cm,omitempty" db:"precipitation_cm"`
 	CreatedAt               time.Time  `json:"created_at" db:"created_at"`
+
+	// DiurnalRangeCelsius is only filled on request (see DiurnalRange)
+	DiurnalRangeCelsius *float64 `json:"diurnal_range_celsius,omitempty" db:"-"`
+}
+
+// DiurnalRange returns max minus min temperature, or nil when either is missing
+func (o *WeatherObservation) DiurnalRange() *float64 {
+	if o.MaxTemperatureCelsius == nil || o.MinTemperatureCelsius == nil {
+		return nil
+	}
+	diurnalRange := *o.MaxTemperatureCelsius - *o.MinTemperatureCelsius
+	return &diurnalRange
+}
+
+// DateOnlyObservation serializes a WeatherObservation with observation_date as
+// YYYY-MM-DD instead of an RFC 3339 timestamp
+type DateOnlyObservation struct {
+	*WeatherObservation
+}
+
+// MarshalJSON implements json.Marshaler
+func (o DateOnlyObservation) MarshalJSON() ([]byte, error) {
+	if o.WeatherObservation == nil {
+		return []byte("null"), nil
+	}
+
+	// observation has no methods, so marshaling it does not recurse
+	type observation WeatherObservation
+	return json.Marshal(struct {
+		*observation
+		ObservationDate string `json:"observation_date"`
+	}{(*observation)(o.WeatherObservation), o.ObservationDate.Format(time.DateOnly)})
+}
+
+// DateOnlyObservations wraps each observation as a DateOnlyObservation
+func DateOnlyObservations(observations []*WeatherObservation) []DateOnlyObservation {
+	wrapped := make([]DateOnlyObservation, len(observations))
+	for i, obs := range observations {
+		wrapped[i] = DateOnlyObservation{obs}
+	}
+	return wrapped
+}
+
+// ObservationHistory holds an observation's values before an update replaced them
+// Written by a database trigger whenever a re-import or correction changes a value
+type ObservationHistory struct {
+	ID                    int64     `json:"id" db:"id"`
+	ObservationID         int64     `json:"observation_id" db:"observation_id"`
+	StationID             string    `json:"station_id" db:"station_id"`
+	ObservationDate       time.Time `json:"observation_date" db:"observation_date"`
+	MaxTemperatureCelsius *float64  `json:"max_temperature_celsius,omitempty" db:"max_temperature_celsius"`
+	MinTemperatureCelsius *float64  `json:"min_temperature_celsius,omitempty" db:"min_temperature_celsius"`
+	PrecipitationCm       *float64  `json:"precipitation_cm,omitempty" db:"precipitation_cm"`
+	ChangedAt             time.Time `json:"changed_at" db:"changed_at"`
 }
 
 // WeatherStatistics represents pre-calculated yearly statistics
@@ -34,27 +92,196 @@ type WeatherStatistics struct {
 	AvgMaxTemperatureCelsius  *float64   `json:"avg_max_temperature_celsius,omitempty" db:"avg_max_temperature_celsius"`
 	AvgMinTemperatureCelsius  *float64   `json:"avg_min_temperature_celsius,omitempty" db:"avg_min_temperature_celsius"`
 	TotalPrecipitationCm      *float64   `json:"total_precipitation_cm,omitempty" db:"total_precipitation_cm"`
+	AvgDiurnalRangeCelsius    *float64   `json:"avg_diurnal_range_celsius,omitempty" db:"avg_diurnal_range_celsius"`
 	ObservationCount          int        `json:"observation_count" db:"observation_count"`
 	ValidMaxTempCount         int        `json:"valid_max_temp_count" db:"valid_max_temp_count"`
 	ValidMinTempCount         int        `json:"valid_min_temp_count" db:"valid_min_temp_count"`
 	ValidPrecipitationCount   int        `json:"valid_precipitation_count" db:"valid_precipitation_count"`
+	IsReliable                bool       `json:"is_reliable" db:"is_reliable"`
 	CreatedAt                 time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt                 time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// StatisticsPatch holds a sparse manual correction to pre-calculated statistics
+// Nil fields are left unchanged
+type StatisticsPatch struct {
+	AvgMaxTemperatureCelsius *float64 `json:"avg_max_temperature_celsius"`
+	AvgMinTemperatureCelsius *float64 `json:"avg_min_temperature_celsius"`
+	TotalPrecipitationCm     *float64 `json:"total_precipitation_cm"`
+	ObservationCount         *int     `json:"observation_count"`
+	ValidMaxTempCount        *int     `json:"valid_max_temp_count"`
+	ValidMinTempCount        *int     `json:"valid_min_temp_count"`
+	ValidPrecipitationCount  *int     `json:"valid_precipitation_count"`
+}
+
+// IsEmpty reports whether the patch changes no fields
+func (p *StatisticsPatch) IsEmpty() bool {
+	return p.AvgMaxTemperatureCelsius == nil &&
+		p.AvgMinTemperatureCelsius == nil &&
+		p.TotalPrecipitationCm == nil &&
+		p.ObservationCount == nil &&
+		p.ValidMaxTempCount == nil &&
+		p.ValidMinTempCount == nil &&
+		p.ValidPrecipitationCount == nil
+}
+
+// Validate rejects negative counts and precipitation totals
+func (p *StatisticsPatch) Validate() error {
+	counts := []struct {
+		field string
+		value *int
+	}{
+		{"observation_count", p.ObservationCount},
+		{"valid_max_temp_count", p.ValidMaxTempCount},
+		{"valid_min_temp_count", p.ValidMinTempCount},
+		{"valid_precipitation_count", p.ValidPrecipitationCount},
+	}
+
+	for _, count := range counts {
+		if count.value != nil && *count.value < 0 {
+			return &ValidationError{
+				Field:   count.field,
+				Value:   strconv.Itoa(*count.value),
+				Message: count.field + " must not be negative",
+			}
+		}
+	}
+
+	if p.TotalPrecipitationCm != nil && *p.TotalPrecipitationCm < 0 {
+		return &ValidationError{
+			Field:   "total_precipitation_cm",
+			Value:   strconv.FormatFloat(*p.TotalPrecipitationCm, 'f', -1, 64),
+			Message: "total_precipitation_cm must not be negative",
+		}
+	}
+
+	return nil
+}
+
+// FailedRecord represents an input line that could not be ingested
+// Persisted as a dead-letter entry for data-quality auditing
+type FailedRecord struct {
+	ID         int64     `json:"id" db:"id"`
+	StationID  string    `json:"station_id" db:"station_id"`
+	LineNumber int       `json:"line_number" db:"line_number"`
+	RawLine    string    `json:"raw_line" db:"raw_line"`
+	Reason     string    `json:"reason" db:"reason"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// IngestionRun represents the outcome of a single ingestion run
+// Persisted for audit and reproducibility
+type IngestionRun struct {
+	ID                int64     `json:"id" db:"id"`
+	DataDir           string    `json:"data_dir" db:"data_dir"`
+	StartedAt         time.Time `json:"started_at" db:"started_at"`
+	FinishedAt        time.Time `json:"finished_at" db:"finished_at"`
+	FilesProcessed    int       `json:"files_processed" db:"files_processed"`
+	TotalRecords      int       `json:"total_records" db:"total_records"`
+	SuccessfulRecords int       `json:"successful_records" db:"successful_records"`
+	FailedRecords     int       `json:"failed_records" db:"failed_records"`
+	ErrorCount        int       `json:"error_count" db:"error_count"`
+	Aborted           bool      `json:"aborted" db:"aborted"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
+
+// Ingestion file states stored in IngestionFileStatus.Status
+const (
+	FileStatusPending    = "pending"
+	FileStatusInProgress = "in_progress"
+	FileStatusDone       = "done"
+	FileStatusFailed     = "failed" // not claimed again until a run retries failed files
+)
+
+// IngestionFileStatus is the shared ingestion state of one data file
+// Ingester instances claim pending files from it and resume after LastLine
+type IngestionFileStatus struct {
+	FileName  string    `json:"file_name" db:"file_name"`
+	Status    string    `json:"status" db:"status"`
+	LastLine  int       `json:"last_line" db:"last_line"`
+	ClaimedBy *string   `json:"claimed_by,omitempty" db:"claimed_by"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// MissingValue is the raw sentinel input files use for a missing reading
+const MissingValue = -9999
+
 // RawWeatherRecord represents a single line from input data files
 // Used during ingestion process
 type RawWeatherRecord struct {
 	Date                string
-	MaxTemperatureTenths int  // Raw value in 0.1°C (may be -9999)
-	MinTemperatureTenths int  // Raw value in 0.1°C (may be -9999)
-	PrecipitationTenths  int  // Raw value in 0.1mm (may be -9999)
+	MaxTemperatureTenths int  // Raw value, 0.1°C unless ConversionOptions says otherwise (may be -9999)
+	MinTemperatureTenths int  // Raw value, 0.1°C unless ConversionOptions says otherwise (may be -9999)
+	PrecipitationTenths  int  // Raw value, 0.1mm unless ConversionOptions says otherwise (may be -9999)
+}
+
+// Default raw-value divisors for the standard feed format
+const (
+	// DefaultTempScale converts tenths of a degree Celsius to °C
+	DefaultTempScale = 10.0
+
+	// DefaultPrecipScale converts tenths of a millimeter to cm
+	DefaultPrecipScale = 100.0
+)
+
+// ConversionOptions controls how raw integer readings become stored units
+// Each raw value is divided by its scale; zero scales use the defaults
+type ConversionOptions struct {
+	// TempScale divides raw temperatures to give °C
+	// Default 10 (tenths of a degree); use 1 for feeds in whole degrees
+	TempScale float64
+
+	// PrecipScale divides raw precipitation to give cm
+	// Default 100 (tenths of a millimeter); use 10 for feeds in millimeters
+	PrecipScale float64
+
+	// NegativePrecip handles precipitation that is negative after conversion
+	// but is not the -9999 sentinel (empty means NegativePrecipError)
+	NegativePrecip string
+}
+
+// Policies for ConversionOptions.NegativePrecip
+const (
+	NegativePrecipError = "error" // reject the record
+	NegativePrecipNull  = "null"  // store the record with precipitation missing
+)
+
+// ParseNegativePrecip validates a negative precipitation policy name (empty
+// means NegativePrecipError)
+func ParseNegativePrecip(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "", NegativePrecipError:
+		return NegativePrecipError, nil
+	case NegativePrecipNull:
+		return NegativePrecipNull, nil
+	default:
+		return "", fmt.Errorf("unknown negative precipitation policy %q: expected %s or %s", value, NegativePrecipError, NegativePrecipNull)
+	}
+}
+
+// withDefaults fills zero scales with the defaults
+func (o ConversionOptions) withDefaults() ConversionOptions {
+	if o.TempScale == 0 {
+		o.TempScale = DefaultTempScale
+	}
+	if o.PrecipScale == 0 {
+		o.PrecipScale = DefaultPrecipScale
+	}
+	return o
 }
 
 // ToObservation converts RawWeatherRecord to WeatherObservation
-// Handles -9999 sentinel values and unit conversions
+// Handles -9999 sentinel values and unit conversions using the default scales
 // Complies with §4 (Complete Implementation) - no TODOs or partial implementation
 func (r *RawWeatherRecord) ToObservation(stationID string) (*WeatherObservation, error) {
+	return r.ToObservationWithOptions(stationID, ConversionOptions{})
+}
+
+// ToObservationWithOptions converts RawWeatherRecord to WeatherObservation
+// dividing raw values by the configured scales
+func (r *RawWeatherRecord) ToObservationWithOptions(stationID string, opts ConversionOptions) (*WeatherObservation, error) {
+	opts = opts.withDefaults()
+
 	// Parse date
 	date, err := time.Parse("20060102", r.Date)
 	if err != nil {
@@ -67,31 +294,51 @@ func (r *RawWeatherRecord) ToObservation(stationID string) (*WeatherObservation,
 
 	obs := &WeatherObservation{
 		StationID:       stationID,
-		ObservationDate: date,
+		ObservationDate: NormalizeDate(date),
 		CreatedAt:       time.Now().UTC(),
 	}
 
-	// Convert max temperature: 0.1°C to °C, handle -9999 as NULL
-	if r.MaxTemperatureTenths != -9999 {
-		temp := float64(r.MaxTemperatureTenths) / 10.0
+	// Convert max temperature to °C, handle -9999 as NULL
+	if r.MaxTemperatureTenths != MissingValue {
+		temp := float64(r.MaxTemperatureTenths) / opts.TempScale
 		obs.MaxTemperatureCelsius = &temp
 	}
 
-	// Convert min temperature: 0.1°C to °C, handle -9999 as NULL
-	if r.MinTemperatureTenths != -9999 {
-		temp := float64(r.MinTemperatureTenths) / 10.0
+	// Convert min temperature to °C, handle -9999 as NULL
+	if r.MinTemperatureTenths != MissingValue {
+		temp := float64(r.MinTemperatureTenths) / opts.TempScale
 		obs.MinTemperatureCelsius = &temp
 	}
 
-	// Convert precipitation: 0.1mm to cm, handle -9999 as NULL
-	if r.PrecipitationTenths != -9999 {
-		precip := float64(r.PrecipitationTenths) / 100.0 // 0.1mm to cm
-		obs.PrecipitationCm = &precip
+	// Convert precipitation to cm, handle -9999 as NULL
+	// Any other negative reading is impossible and handled by opts.NegativePrecip
+	if r.PrecipitationTenths != MissingValue {
+		precip := float64(r.PrecipitationTenths) / opts.PrecipScale
+		switch {
+		case precip >= 0:
+			obs.PrecipitationCm = &precip
+		case opts.NegativePrecip == NegativePrecipNull:
+			// Left NULL, like the sentinel
+		default:
+			return nil, &ValidationError{
+				Field:   "precipitation_cm",
+				Value:   strconv.Itoa(r.PrecipitationTenths),
+				Message: fmt.Sprintf("precipitation must not be negative, got raw value %d", r.PrecipitationTenths),
+			}
+		}
 	}
 
 	return obs, nil
 }
 
+// NormalizeDate truncates t to midnight UTC of its calendar date
+// The calendar date is taken in t's own location so sources with different
+// time components or zones for the same day collapse to one conflict key
+func NormalizeDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
 // ValidationError represents a data validation error
 // Complies with §13 (Error Algebra) - explicit error classification
 type ValidationError struct {
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"encoding/json"
+	"errors"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -241,3 +244,216 @@ func TestValidationError(t *testing.T) {
 		t.Error("ValidationError should not be transient")
 	}
 }
+
+// TestWeatherStatistics_DataQuality tests ratio derivation and zero-count handling
+func TestWeatherStatistics_DataQuality(t *testing.T) {
+	stats := &WeatherStatistics{
+		Year:                    2000,
+		ObservationCount:        200,
+		ValidMaxTempCount:       200,
+		ValidMinTempCount:       150,
+		ValidPrecipitationCount: 50,
+	}
+
+	quality := stats.DataQuality()
+	if quality.Year != 2000 || quality.ObservationCount != 200 {
+		t.Errorf("DataQuality() = year %d count %d, want 2000 200", quality.Year, quality.ObservationCount)
+	}
+	if quality.MaxTempRatio == nil || *quality.MaxTempRatio != 1.0 {
+		t.Errorf("MaxTempRatio = %v, want 1.0", quality.MaxTempRatio)
+	}
+	if quality.MinTempRatio == nil || *quality.MinTempRatio != 0.75 {
+		t.Errorf("MinTempRatio = %v, want 0.75", quality.MinTempRatio)
+	}
+	if quality.PrecipitationRatio == nil || *quality.PrecipitationRatio != 0.25 {
+		t.Errorf("PrecipitationRatio = %v, want 0.25", quality.PrecipitationRatio)
+	}
+
+	empty := (&WeatherStatistics{Year: 2001}).DataQuality()
+	if empty.MaxTempRatio != nil || empty.MinTempRatio != nil || empty.PrecipitationRatio != nil {
+		t.Error("ratios should be nil when observation count is zero")
+	}
+}
+
+// TestNormalizeDate verifies same-day timestamps collapse to midnight UTC
+func TestNormalizeDate(t *testing.T) {
+	want := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	est := time.FixedZone("EST", -5*60*60)
+
+	tests := []struct {
+		name  string
+		input time.Time
+	}{
+		{"midnight UTC", time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"afternoon UTC", time.Date(2023, 1, 15, 14, 30, 0, 0, time.UTC)},
+		{"late evening in another zone", time.Date(2023, 1, 15, 23, 0, 0, 0, est)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeDate(tt.input)
+			if !got.Equal(want) || got.Location() != time.UTC {
+				t.Errorf("NormalizeDate(%v) = %v, want %v", tt.input, got, want)
+			}
+		})
+	}
+}
+
+// TestRawWeatherRecord_ToObservationWithOptions tests configurable unit scales
+func TestRawWeatherRecord_ToObservationWithOptions(t *testing.T) {
+	record := &RawWeatherRecord{
+		Date:                 "20230115",
+		MaxTemperatureTenths: 25,
+		MinTemperatureTenths: -3,
+		PrecipitationTenths:  12,
+	}
+
+	tests := []struct {
+		name       string
+		opts       ConversionOptions
+		wantMax    float64
+		wantMin    float64
+		wantPrecip float64
+	}{
+		{"zero options use defaults", ConversionOptions{}, 2.5, -0.3, 0.12},
+		{"whole degrees and millimeters", ConversionOptions{TempScale: 1, PrecipScale: 10}, 25, -3, 1.2},
+		{"only temperature overridden", ConversionOptions{TempScale: 1}, 25, -3, 0.12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs, err := record.ToObservationWithOptions("USC00000001", tt.opts)
+			if err != nil {
+				t.Fatalf("ToObservationWithOptions() error = %v", err)
+			}
+			if *obs.MaxTemperatureCelsius != tt.wantMax {
+				t.Errorf("MaxTemperatureCelsius = %v, want %v", *obs.MaxTemperatureCelsius, tt.wantMax)
+			}
+			if *obs.MinTemperatureCelsius != tt.wantMin {
+				t.Errorf("MinTemperatureCelsius = %v, want %v", *obs.MinTemperatureCelsius, tt.wantMin)
+			}
+			if *obs.PrecipitationCm != tt.wantPrecip {
+				t.Errorf("PrecipitationCm = %v, want %v", *obs.PrecipitationCm, tt.wantPrecip)
+			}
+		})
+	}
+
+	missing := &RawWeatherRecord{Date: "20230115", MaxTemperatureTenths: -9999, MinTemperatureTenths: -9999, PrecipitationTenths: -9999}
+	obs, err := missing.ToObservationWithOptions("USC00000001", ConversionOptions{TempScale: 1, PrecipScale: 10})
+	if err != nil {
+		t.Fatalf("ToObservationWithOptions() error = %v", err)
+	}
+	if obs.MaxTemperatureCelsius != nil || obs.MinTemperatureCelsius != nil || obs.PrecipitationCm != nil {
+		t.Error("-9999 should stay NULL regardless of scale")
+	}
+}
+
+// TestRawWeatherRecord_NegativePrecipitation tests that negative readings are
+// rejected or nulled by policy while the -9999 sentinel is always missing data
+func TestRawWeatherRecord_NegativePrecipitation(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     int
+		policy  string
+		wantErr bool
+	}{
+		{"-1 rejected by default", -1, "", true},
+		{"-50 rejected by default", -50, "", true},
+		{"-9999 sentinel by default", -9999, "", false},
+		{"-1 rejected", -1, NegativePrecipError, true},
+		{"-50 nulled", -50, NegativePrecipNull, false},
+		{"-9999 sentinel with null policy", -9999, NegativePrecipNull, false},
+		{"0 kept", 0, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &RawWeatherRecord{Date: "20230115", MaxTemperatureTenths: 250, PrecipitationTenths: tt.raw}
+			obs, err := record.ToObservationWithOptions("USC00000001", ConversionOptions{NegativePrecip: tt.policy})
+
+			if tt.wantErr {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != "precipitation_cm" {
+					t.Fatalf("ToObservationWithOptions() error = %v, want precipitation_cm ValidationError", err)
+				}
+				if validationErr.Value != strconv.Itoa(tt.raw) {
+					t.Errorf("ValidationError.Value = %q, want %d", validationErr.Value, tt.raw)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ToObservationWithOptions() error = %v", err)
+			}
+			if tt.raw < 0 && obs.PrecipitationCm != nil {
+				t.Errorf("PrecipitationCm = %v, want nil", *obs.PrecipitationCm)
+			}
+			if tt.raw == 0 && (obs.PrecipitationCm == nil || *obs.PrecipitationCm != 0) {
+				t.Errorf("PrecipitationCm = %v, want 0", obs.PrecipitationCm)
+			}
+			if obs.MaxTemperatureCelsius == nil {
+				t.Error("MaxTemperatureCelsius = nil, want the rest of the record kept")
+			}
+		})
+	}
+
+	if _, err := ParseNegativePrecip("clamp"); err == nil {
+		t.Error("ParseNegativePrecip(clamp) expected error")
+	}
+	if got, err := ParseNegativePrecip("NULL"); err != nil || got != NegativePrecipNull {
+		t.Errorf("ParseNegativePrecip(NULL) = (%q, %v), want null", got, err)
+	}
+}
+
+// TestWeatherObservation_DiurnalRange tests max minus min and missing bounds
+func TestWeatherObservation_DiurnalRange(t *testing.T) {
+	high, low := 25.5, 10.0
+
+	obs := &WeatherObservation{MaxTemperatureCelsius: &high, MinTemperatureCelsius: &low}
+	if got := obs.DiurnalRange(); got == nil || *got != 15.5 {
+		t.Errorf("DiurnalRange() = %v, want 15.5", got)
+	}
+
+	if got := (&WeatherObservation{MinTemperatureCelsius: &low}).DiurnalRange(); got != nil {
+		t.Errorf("DiurnalRange() with max missing = %v, want nil", *got)
+	}
+	if got := (&WeatherObservation{MaxTemperatureCelsius: &high}).DiurnalRange(); got != nil {
+		t.Errorf("DiurnalRange() with min missing = %v, want nil", *got)
+	}
+}
+
+// TestDateOnlyObservation_MarshalJSON tests date-only observation_date and unchanged other fields
+func TestDateOnlyObservation_MarshalJSON(t *testing.T) {
+	high := 25.5
+	obs := &WeatherObservation{
+		ID:                    7,
+		StationID:             "USC00110072",
+		ObservationDate:       time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+		MaxTemperatureCelsius: &high,
+	}
+
+	encoded, err := json.Marshal(DateOnlyObservation{obs})
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if fields["observation_date"] != "2023-01-15" {
+		t.Errorf("observation_date = %v, want 2023-01-15", fields["observation_date"])
+	}
+	if fields["station_id"] != "USC00110072" || fields["max_temperature_celsius"] != 25.5 || fields["id"] != 7.0 {
+		t.Errorf("other fields changed: %s", encoded)
+	}
+	if _, ok := fields["min_temperature_celsius"]; ok {
+		t.Errorf("omitempty not honored: %s", encoded)
+	}
+
+	encoded, err = json.Marshal(DateOnlyObservation{})
+	if err != nil || string(encoded) != "null" {
+		t.Errorf("MarshalJSON() of nil observation = %s, %v, want null", encoded, err)
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"regexp"
+
+	"weather-platform/migrations"
+)
+
+// Canonical table names as written in the migration files
+const (
+	stationsTable      = "weather_stations"
+	observationsTable  = "weather_observations"
+	statisticsTable    = "weather_statistics"
+	failedRecordsTable = "failed_records"
+	ingestionRunsTable = "ingestion_runs"
+	historyTable       = "weather_observation_history"
+	fileStatusTable    = "ingestion_file_status"
+)
+
+// Tables holds the physical table names used when building SQL
+// Every name carries the configured table prefix (empty keeps the canonical names)
+type Tables struct {
+	Stations      string
+	Observations  string
+	Statistics    string
+	FailedRecords string
+	IngestionRuns string
+	History       string
+	FileStatus    string
+
+	// Migrations records applied migration versions; the migration runner
+	// creates it, so it is not part of All
+	Migrations string
+
+	prefix string
+}
+
+// NewTables builds the table names for the given prefix
+func NewTables(prefix string) Tables {
+	return Tables{
+		Stations:      prefix + stationsTable,
+		Observations:  prefix + observationsTable,
+		Statistics:    prefix + statisticsTable,
+		FailedRecords: prefix + failedRecordsTable,
+		IngestionRuns: prefix + ingestionRunsTable,
+		History:       prefix + historyTable,
+		FileStatus:    prefix + fileStatusTable,
+		Migrations:    prefix + migrations.DefaultVersionTable,
+		prefix:        prefix,
+	}
+}
+
+// All returns every table created by migrations that the repository queries
+func (t Tables) All() []string {
+	return []string{t.Stations, t.Observations, t.Statistics, t.FailedRecords, t.IngestionRuns, t.History, t.FileStatus}
+}
+
+var (
+	// tableReference matches a canonical table name where SQL expects a table,
+	// so columns sharing a table's name (ingestion_runs.failed_records) are kept
+	tableReference = regexp.MustCompile(`(?i)\b(FROM|JOIN|INTO|UPDATE|TABLE|ON|REFERENCES|EXISTS|USING)(\s+)(` +
+		stationsTable + `|` + observationsTable + `|` + statisticsTable + `|` + failedRecordsTable + `|` + ingestionRunsTable + `|` + historyTable + `|` + fileStatusTable + `)\b`)
+
+	// tableQualifier matches a canonical table name used as a column qualifier or string literal
+	tableQualifier = regexp.MustCompile(`\b(` +
+		stationsTable + `|` + observationsTable + `|` + statisticsTable + `|` + failedRecordsTable + `|` + ingestionRunsTable + `|` + historyTable + `|` + fileStatusTable + `)(\.|')`)
+
+	// schemaWideName matches index, unique-constraint and trigger function
+	// names, which must be unique per schema rather than per table
+	schemaWideName = regexp.MustCompile(`\b((?:idx|unique|trg)_\w+)`)
+)
+
+// Rewrite applies the prefix to SQL written against the canonical names,
+// such as the migration files
+// Table names and the index, unique-constraint and trigger function names that
+// would otherwise collide between prefixed installations in one schema are rewritten
+func (t Tables) Rewrite(script string) string {
+	if t.prefix == "" {
+		return script
+	}
+
+	script = tableReference.ReplaceAllString(script, "${1}${2}"+t.prefix+"${3}")
+	script = tableQualifier.ReplaceAllString(script, t.prefix+"${1}${2}")
+	return schemaWideName.ReplaceAllString(script, t.prefix+"${1}")
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNewTables tests that the prefix is applied to every table name
+func TestNewTables(t *testing.T) {
+	for _, table := range NewTables("wx_").All() {
+		if !strings.HasPrefix(table, "wx_") {
+			t.Errorf("table %q is missing prefix", table)
+		}
+	}
+
+	if got := NewTables("").Observations; got != "weather_observations" {
+		t.Errorf("Observations with empty prefix = %q, want weather_observations", got)
+	}
+}
+
+// TestTablesRewrite tests prefixing of migration SQL
+func TestTablesRewrite(t *testing.T) {
+	script := `
+CREATE TABLE IF NOT EXISTS ingestion_runs (
+    failed_records INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE weather_observations (
+    station_id VARCHAR(50) NOT NULL REFERENCES weather_stations(station_id),
+    CONSTRAINT unique_station_date UNIQUE(station_id, observation_date)
+);
+CREATE INDEX idx_weather_obs_date_range ON weather_observations(observation_date DESC);
+COMMENT ON COLUMN weather_observations.precipitation_cm IS 'Precipitation';
+DELETE FROM weather_observations o USING weather_observations newer WHERE table_name = 'weather_observations';
+CREATE OR REPLACE FUNCTION trg_record_observation_history() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO weather_observation_history (observation_id) VALUES (OLD.id);
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER record_weather_observation_history AFTER UPDATE ON weather_observations
+    FOR EACH ROW EXECUTE FUNCTION trg_record_observation_history();
+`
+	want := `
+CREATE TABLE IF NOT EXISTS wx_ingestion_runs (
+    failed_records INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE wx_weather_observations (
+    station_id VARCHAR(50) NOT NULL REFERENCES wx_weather_stations(station_id),
+    CONSTRAINT wx_unique_station_date UNIQUE(station_id, observation_date)
+);
+CREATE INDEX wx_idx_weather_obs_date_range ON wx_weather_observations(observation_date DESC);
+COMMENT ON COLUMN wx_weather_observations.precipitation_cm IS 'Precipitation';
+DELETE FROM wx_weather_observations o USING wx_weather_observations newer WHERE table_name = 'wx_weather_observations';
+CREATE OR REPLACE FUNCTION wx_trg_record_observation_history() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO wx_weather_observation_history (observation_id) VALUES (OLD.id);
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER record_weather_observation_history AFTER UPDATE ON wx_weather_observations
+    FOR EACH ROW EXECUTE FUNCTION wx_trg_record_observation_history();
+`
+
+	if got := NewTables("wx_").Rewrite(script); got != want {
+		t.Errorf("Rewrite() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := NewTables("").Rewrite(script); got != script {
+		t.Errorf("Rewrite() with empty prefix changed the script:\n%s", got)
+	}
+}
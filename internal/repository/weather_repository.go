@@ -3,10 +3,16 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/lib/pq"
+
 	"weather-platform/internal/models"
+	"weather-platform/migrations"
 	"weather-platform/pkg/database"
 	"weather-platform/pkg/logging"
 	"weather-platform/pkg/metrics"
@@ -18,21 +24,186 @@ type WeatherRepository interface {
 	CreateStation(ctx context.Context, station *models.WeatherStation) error
 	GetStation(ctx context.Context, stationID string) (*models.WeatherStation, error)
 	ListStations(ctx context.Context, limit, offset int) ([]*models.WeatherStation, error)
+	CountStations(ctx context.Context) (int, error)
+	SearchStations(ctx context.Context, query string, limit int) ([]*models.WeatherStation, error)
 
 	// Observation operations
 	CreateObservation(ctx context.Context, obs *models.WeatherObservation) error
-	CreateObservationsBatch(ctx context.Context, observations []*models.WeatherObservation) error
+	CreateObservationsBatch(ctx context.Context, observations []*models.WeatherObservation, conflict ConflictStrategy) error
 	GetObservations(ctx context.Context, filter ObservationFilter) ([]*models.WeatherObservation, int, error)
+	GetDiurnalRange(ctx context.Context, filter ObservationFilter) ([]*models.DiurnalRange, int, error)
+	CountObservations(ctx context.Context, filter ObservationFilter) (int, error)
 	GetObservationByStationDate(ctx context.Context, stationID string, date time.Time) (*models.WeatherObservation, error)
+	GetObservationHistory(ctx context.Context, stationID string, date time.Time) ([]*models.ObservationHistory, error)
+	GetWeatherEvents(ctx context.Context, filter EventFilter) ([]*models.WeatherEvent, error)
+	GetLatestObservations(ctx context.Context, stationIDs []string) ([]*models.WeatherObservation, error)
+	CompactDuplicates(ctx context.Context) (int64, error)
+	PurgeObservationsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	BackfillMissingDates(ctx context.Context, stationID string, from, to time.Time) (int64, error)
+	ListAvailableYears(ctx context.Context, stationID *string) ([]int, error)
+
+	// Analytics operations
+	CompareStations(ctx context.Context, stationA, stationB, metric string, from, to time.Time) ([]*models.DailyComparison, error)
+	GetExtremes(ctx context.Context, year *int, stationID *string) (*models.WeatherExtremes, error)
+	GetMissingDates(ctx context.Context, stationID string, from, to time.Time) ([]time.Time, error)
+	GetMovingAverage(ctx context.Context, stationID, metric string, window int, from, to time.Time) ([]*models.MovingAveragePoint, error)
+	GetHistogram(ctx context.Context, metric string, bins int, filter ObservationFilter) ([]*models.HistogramBin, error)
+	CalculateFrostFreeSeason(ctx context.Context, stationID string, year int) (*models.FrostFreeSeason, error)
+	CalculateDegreeDays(ctx context.Context, stationID string, year int, base float64) (*models.DegreeDays, error)
+	GetMonthlyObservationCounts(ctx context.Context, stationID string, year int) ([]*models.MonthlyObservationCount, error)
+	GetGlobalStats(ctx context.Context, exact bool) (*models.GlobalStats, error)
+	GetClimateNormals(ctx context.Context, stationID string) ([]*models.ClimateNormal, error)
+	GetLongestStreak(ctx context.Context, stationID string, condition string, threshold float64) (*models.Streak, error)
+	GetCorrelation(ctx context.Context, metricX, metricY string, filter ObservationFilter) (*models.Correlation, error)
+	GetYearlyDegreeDays(ctx context.Context, stationID string, base float64) ([]*models.DegreeDays, error)
 
 	// Statistics operations
 	CreateStatistics(ctx context.Context, stats *models.WeatherStatistics) error
 	UpsertStatistics(ctx context.Context, stats *models.WeatherStatistics) error
+	UpsertStatisticsBatch(ctx context.Context, stats []*models.WeatherStatistics) error
 	GetStatistics(ctx context.Context, filter StatisticsFilter) ([]*models.WeatherStatistics, int, error)
+	StreamStatistics(ctx context.Context, filter StatisticsFilter, fn func(*models.WeatherStatistics) error) error
+	ListStationStatistics(ctx context.Context, stationID string) ([]*models.WeatherStatistics, error)
+	ListStationsMissingStatistics(ctx context.Context) ([]*models.StationObservationCount, error)
+	GetRanking(ctx context.Context, metric string, year, limit int) ([]*models.StationRanking, error)
+	GetYearOverYearChange(ctx context.Context, stationID, metric string) ([]*models.YearOverYearChange, error)
+	PatchStatistics(ctx context.Context, stationID string, year int, patch *models.StatisticsPatch) (*models.WeatherStatistics, error)
 	CalculateYearlyStatistics(ctx context.Context, stationID string, year int) (*models.WeatherStatistics, error)
 
+	// Ingestion audit operations
+	RecordFailure(ctx context.Context, stationID string, lineNumber int, raw, reason string) error
+	ListFailedRecords(ctx context.Context, filter FailedRecordFilter) ([]*models.FailedRecord, int, error)
+	RecordIngestionRun(ctx context.Context, run *models.IngestionRun) error
+	ListIngestionRuns(ctx context.Context, limit, offset int) ([]*models.IngestionRun, int, error)
+
+	// Ingestion file coordination operations
+	RegisterIngestionFiles(ctx context.Context, fileNames []string, retryFailed bool) error
+	ClaimIngestionFile(ctx context.Context, owner string, fileNames []string, staleAfter time.Duration) (*models.IngestionFileStatus, error)
+	UpdateIngestionFileProgress(ctx context.Context, fileName, owner string, lastLine int) (bool, error)
+	SetIngestionFileStatus(ctx context.Context, fileName, owner, status string) (bool, error)
+
 	// Utility operations
 	HealthCheck(ctx context.Context) error
+	VerifySchema(ctx context.Context) error
+	ApplyMigrations(ctx context.Context) error
+}
+
+// observationMetricColumns maps API metric names to observation columns
+// Only columns listed here may be interpolated into analytics queries
+var observationMetricColumns = map[string]string{
+	"max_temp": "max_temperature_celsius",
+	"min_temp": "min_temperature_celsius",
+	"precip":   "precipitation_cm",
+}
+
+// IsValidObservationMetric reports whether metric is a supported observation metric
+func IsValidObservationMetric(metric string) bool {
+	_, ok := observationMetricColumns[metric]
+	return ok
+}
+
+// rankingMetricColumns maps ranking metric names to weather_statistics columns
+// Only columns listed here may be interpolated into ranking queries
+var rankingMetricColumns = map[string]string{
+	"precip":   "total_precipitation_cm",
+	"max_temp": "avg_max_temperature_celsius",
+}
+
+// IsValidRankingMetric reports whether metric is a supported ranking metric
+func IsValidRankingMetric(metric string) bool {
+	_, ok := rankingMetricColumns[metric]
+	return ok
+}
+
+// yearOverYearMetricColumns maps year-over-year metric names to weather_statistics columns
+// Only columns listed here may be interpolated into year-over-year queries
+var yearOverYearMetricColumns = map[string]string{
+	"avg_max_temp":      "avg_max_temperature_celsius",
+	"avg_min_temp":      "avg_min_temperature_celsius",
+	"total_precip":      "total_precipitation_cm",
+	"avg_diurnal_range": "avg_diurnal_range_celsius",
+}
+
+// IsValidYearOverYearMetric reports whether metric is a supported year-over-year metric
+func IsValidYearOverYearMetric(metric string) bool {
+	_, ok := yearOverYearMetricColumns[metric]
+	return ok
+}
+
+// streakConditions maps streak condition names to the predicate a day must
+// meet, compared against the threshold bound as $2
+// Only predicates listed here may be interpolated into streak queries
+var streakConditions = map[string]string{
+	"hot":  "max_temperature_celsius > $2",
+	"cold": "min_temperature_celsius < $2",
+	"wet":  "precipitation_cm > $2",
+	"dry":  "precipitation_cm <= $2",
+}
+
+// IsValidStreakCondition reports whether condition is a supported streak condition
+func IsValidStreakCondition(condition string) bool {
+	_, ok := streakConditions[condition]
+	return ok
+}
+
+// degreeDayMetrics maps degree-day metric names to the total they select
+var degreeDayMetrics = map[string]func(*models.DegreeDays) float64{
+	"gdd": func(d *models.DegreeDays) float64 { return d.GrowingDegreeDays },
+	"cdd": func(d *models.DegreeDays) float64 { return d.CoolingDegreeDays },
+	"hdd": func(d *models.DegreeDays) float64 { return d.HeatingDegreeDays },
+}
+
+// IsValidDegreeDayMetric reports whether metric is a supported degree-day metric
+func IsValidDegreeDayMetric(metric string) bool {
+	_, ok := degreeDayMetrics[metric]
+	return ok
+}
+
+// DegreeDayTotal returns the total of degreeDays that metric names
+func DegreeDayTotal(degreeDays *models.DegreeDays, metric string) (float64, error) {
+	total, ok := degreeDayMetrics[metric]
+	if !ok {
+		return 0, fmt.Errorf("invalid degree-day metric: %s", metric)
+	}
+	return total(degreeDays), nil
+}
+
+// ConflictStrategy controls how batch inserts treat existing (station_id, observation_date) rows
+type ConflictStrategy string
+
+const (
+	// ConflictUpdate overwrites the existing reading (upsert)
+	ConflictUpdate ConflictStrategy = "update"
+	// ConflictIgnore keeps the existing reading ("first write wins")
+	ConflictIgnore ConflictStrategy = "ignore"
+	// ConflictError fails the batch on any duplicate
+	ConflictError ConflictStrategy = "error"
+)
+
+// ParseConflictStrategy validates a conflict strategy name
+func ParseConflictStrategy(name string) (ConflictStrategy, error) {
+	switch strategy := ConflictStrategy(name); strategy {
+	case ConflictUpdate, ConflictIgnore, ConflictError:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("invalid conflict strategy %q: expected update, ignore, or error", name)
+	}
+}
+
+// conflictClause returns the ON CONFLICT clause for an observation insert
+// An empty strategy defaults to ConflictUpdate
+func (c ConflictStrategy) conflictClause() string {
+	switch c {
+	case ConflictIgnore:
+		return "ON CONFLICT (station_id, observation_date) DO NOTHING"
+	case ConflictError:
+		return ""
+	default:
+		return `ON CONFLICT (station_id, observation_date) DO UPDATE SET
+			max_temperature_celsius = EXCLUDED.max_temperature_celsius,
+			min_temperature_celsius = EXCLUDED.min_temperature_celsius,
+			precipitation_cm = EXCLUDED.precipitation_cm`
+	}
 }
 
 // ObservationFilter defines filters for querying observations
@@ -40,14 +211,38 @@ type ObservationFilter struct {
 	StationID  *string
 	StartDate  *time.Time
 	EndDate    *time.Time
+	// CreatedAfter filters on ingestion time (created_at) rather than observation date
+	CreatedAfter *time.Time
+	// MissingField keeps only rows where this observation metric (max_temp,
+	// min_temp or precip) is NULL; unknown names are ignored
+	MissingField *string
 	Limit      int
 	Offset     int
 }
 
 // StatisticsFilter defines filters for querying statistics
 type StatisticsFilter struct {
+	StationID  *string
+	Year       *int
+	IsReliable *bool
+	Limit      int
+	Offset     int
+}
+
+// EventFilter selects the observations of one date that meet every set threshold
+type EventFilter struct {
+	Date time.Time
+	// MinPrecipitationCm matches precipitation at or above the value
+	MinPrecipitationCm *float64
+	// MaxTempAbove matches a maximum temperature strictly above the value
+	MaxTempAbove *float64
+	// MinTempBelow matches a minimum temperature strictly below the value
+	MinTempBelow *float64
+}
+
+// FailedRecordFilter defines filters for querying failed ingestion records
+type FailedRecordFilter struct {
 	StationID *string
-	Year      *int
 	Limit     int
 	Offset    int
 }
@@ -57,21 +252,27 @@ type weatherRepository struct {
 	db      *database.PostgresDB
 	logger  *logging.StructuredLogger
 	metrics *metrics.Collector
+	tables  Tables
+
+	// stationStates caches station_id -> state for per-state ingestion metrics
+	stationStates sync.Map
 }
 
 // NewWeatherRepository creates a new weather repository
-func NewWeatherRepository(db *database.PostgresDB, logger *logging.StructuredLogger, metricsCollector *metrics.Collector) WeatherRepository {
+// Queries use the table names in tables (see NewTables)
+func NewWeatherRepository(db *database.PostgresDB, logger *logging.StructuredLogger, metricsCollector *metrics.Collector, tables Tables) WeatherRepository {
 	return &weatherRepository{
 		db:      db,
 		logger:  logger,
 		metrics: metricsCollector,
+		tables:  tables,
 	}
 }
 
 // CreateStation creates a new weather station
 func (r *weatherRepository) CreateStation(ctx context.Context, station *models.WeatherStation) error {
 	query := `
-		INSERT INTO weather_stations (station_id, state, created_at, updated_at)
+		INSERT INTO ` + r.tables.Stations + ` (station_id, state, created_at, updated_at)
 		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (station_id) DO NOTHING
 	`
@@ -99,7 +300,7 @@ func (r *weatherRepository) CreateStation(ctx context.Context, station *models.W
 func (r *weatherRepository) GetStation(ctx context.Context, stationID string) (*models.WeatherStation, error) {
 	query := `
 		SELECT station_id, state, created_at, updated_at
-		FROM weather_stations
+		FROM ` + r.tables.Stations + `
 		WHERE station_id = $1
 	`
 
@@ -124,7 +325,7 @@ func (r *weatherRepository) GetStation(ctx context.Context, stationID string) (*
 func (r *weatherRepository) ListStations(ctx context.Context, limit, offset int) ([]*models.WeatherStation, error) {
 	query := `
 		SELECT station_id, state, created_at, updated_at
-		FROM weather_stations
+		FROM ` + r.tables.Stations + `
 		ORDER BY station_id
 		LIMIT $1 OFFSET $2
 	`
@@ -139,10 +340,47 @@ func (r *weatherRepository) ListStations(ctx context.Context, limit, offset int)
 	return stations, nil
 }
 
+// CountStations returns the total number of weather stations
+func (r *weatherRepository) CountStations(ctx context.Context) (int, error) {
+	var total int
+	err := r.db.GetContext(ctx, "count_stations", &total, "SELECT COUNT(*) FROM "+r.tables.Stations)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count stations: %w", err)
+	}
+
+	return total, nil
+}
+
+// SearchStations returns up to limit stations whose ID contains query, ignoring case
+// IDs starting with query rank first, then shorter (closer) IDs, then by ID.
+// LIKE wildcards in query match literally.
+func (r *weatherRepository) SearchStations(ctx context.Context, query string, limit int) ([]*models.WeatherStation, error) {
+	escaped := likeEscaper.Replace(query)
+
+	sqlQuery := `
+		SELECT station_id, state, created_at, updated_at
+		FROM ` + r.tables.Stations + `
+		WHERE station_id ILIKE '%' || $1::text || '%'
+		ORDER BY station_id ILIKE $1::text || '%' DESC, length(station_id), station_id
+		LIMIT $2
+	`
+
+	stations := []*models.WeatherStation{}
+	err := r.db.SelectContext(ctx, "search_stations", &stations, sqlQuery, escaped, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search stations: %w", err)
+	}
+
+	return stations, nil
+}
+
+// likeEscaper escapes LIKE pattern characters using the default backslash escape
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // CreateObservation creates a new weather observation
 func (r *weatherRepository) CreateObservation(ctx context.Context, obs *models.WeatherObservation) error {
 	query := `
-		INSERT INTO weather_observations (
+		INSERT INTO ` + r.tables.Observations + ` (
 			station_id, observation_date,
 			max_temperature_celsius, min_temperature_celsius, precipitation_cm,
 			created_at
@@ -157,7 +395,7 @@ func (r *weatherRepository) CreateObservation(ctx context.Context, obs *models.W
 
 	err := r.db.DB().QueryRowContext(ctx, query,
 		obs.StationID,
-		obs.ObservationDate,
+		models.NormalizeDate(obs.ObservationDate),
 		obs.MaxTemperatureCelsius,
 		obs.MinTemperatureCelsius,
 		obs.PrecipitationCm,
@@ -172,7 +410,8 @@ func (r *weatherRepository) CreateObservation(ctx context.Context, obs *models.W
 }
 
 // CreateObservationsBatch creates multiple observations in a single transaction
-func (r *weatherRepository) CreateObservationsBatch(ctx context.Context, observations []*models.WeatherObservation) error {
+// conflict selects how existing station/date rows are handled
+func (r *weatherRepository) CreateObservationsBatch(ctx context.Context, observations []*models.WeatherObservation, conflict ConflictStrategy) error {
 	if len(observations) == 0 {
 		return nil
 	}
@@ -187,6 +426,13 @@ func (r *weatherRepository) CreateObservationsBatch(ctx context.Context, observa
 		})
 	}()
 
+	// Bound concurrent serializable write transactions across all callers
+	release, err := r.db.AcquireTxSlot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire transaction slot: %w", err)
+	}
+	defer release()
+
 	// Begin transaction
 	tx, err := r.db.BeginTx(ctx)
 	if err != nil {
@@ -196,17 +442,13 @@ func (r *weatherRepository) CreateObservationsBatch(ctx context.Context, observa
 
 	// Prepare statement
 	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO weather_observations (
+		INSERT INTO `+r.tables.Observations+` (
 			station_id, observation_date,
 			max_temperature_celsius, min_temperature_celsius, precipitation_cm,
 			created_at
 		)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (station_id, observation_date) DO UPDATE SET
-			max_temperature_celsius = EXCLUDED.max_temperature_celsius,
-			min_temperature_celsius = EXCLUDED.min_temperature_celsius,
-			precipitation_cm = EXCLUDED.precipitation_cm
-	`)
+		`+conflict.conflictClause())
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
@@ -216,7 +458,7 @@ func (r *weatherRepository) CreateObservationsBatch(ctx context.Context, observa
 	for _, obs := range observations {
 		_, err := stmt.ExecContext(ctx,
 			obs.StationID,
-			obs.ObservationDate,
+			models.NormalizeDate(obs.ObservationDate),
 			obs.MaxTemperatureCelsius,
 			obs.MinTemperatureCelsius,
 			obs.PrecipitationCm,
@@ -233,49 +475,106 @@ func (r *weatherRepository) CreateObservationsBatch(ctx context.Context, observa
 	}
 
 	r.metrics.IngestionRecordsTotal.Add(float64(len(observations)))
+	r.recordIngestionByState(ctx, observations)
 
 	return nil
 }
 
-// GetObservations retrieves weather observations with filtering and pagination
-func (r *weatherRepository) GetObservations(ctx context.Context, filter ObservationFilter) ([]*models.WeatherObservation, int, error) {
-	// Build query with filters
-	query := `
-		SELECT id, station_id, observation_date,
-		       max_temperature_celsius, min_temperature_celsius, precipitation_cm,
-		       created_at
-		FROM weather_observations
-		WHERE 1=1
-	`
+// recordIngestionByState increments the per-state ingestion counter for a committed batch
+// Station states are cached after the first lookup; lookup failures are counted as "unknown"
+func (r *weatherRepository) recordIngestionByState(ctx context.Context, observations []*models.WeatherObservation) {
+	counts := make(map[string]int)
+	var missing []string
+	for _, obs := range observations {
+		if _, cached := r.stationStates.Load(obs.StationID); !cached && counts[obs.StationID] == 0 {
+			missing = append(missing, obs.StationID)
+		}
+		counts[obs.StationID]++
+	}
+
+	if len(missing) > 0 {
+		var rows []struct {
+			StationID string `db:"station_id"`
+			State     string `db:"state"`
+		}
+		query := `SELECT station_id, state FROM ` + r.tables.Stations + ` WHERE station_id = ANY($1)`
+		if err := r.db.SelectContext(ctx, "get_station_states", &rows, query, pq.Array(missing)); err != nil {
+			r.logger.Warn(ctx, "[REPO_STATION_STATE_LOOKUP_ERROR] Failed to look up station states for metrics", logging.Fields{
+				"station_count": len(missing),
+				"error":         err.Error(),
+			})
+		}
+		for _, row := range rows {
+			r.stationStates.Store(row.StationID, row.State)
+		}
+	}
+
+	for stationID, count := range counts {
+		state := "unknown"
+		if cached, ok := r.stationStates.Load(stationID); ok {
+			state = cached.(string)
+		}
+		r.metrics.IngestionRecordsByState.WithLabelValues(state).Add(float64(count))
+	}
+}
+
+// buildObservationWhere renders the WHERE clause shared by observation list and count queries
+// Returns the clause, its arguments, and the next placeholder number
+func buildObservationWhere(filter ObservationFilter) (string, []interface{}, int) {
+	where := " WHERE 1=1"
 	args := []interface{}{}
 	argNum := 1
 
 	if filter.StationID != nil {
-		query += fmt.Sprintf(" AND station_id = $%d", argNum)
+		where += fmt.Sprintf(" AND station_id = $%d", argNum)
 		args = append(args, *filter.StationID)
 		argNum++
 	}
 
 	if filter.StartDate != nil {
-		query += fmt.Sprintf(" AND observation_date >= $%d", argNum)
+		where += fmt.Sprintf(" AND observation_date >= $%d", argNum)
 		args = append(args, *filter.StartDate)
 		argNum++
 	}
 
 	if filter.EndDate != nil {
-		query += fmt.Sprintf(" AND observation_date <= $%d", argNum)
+		where += fmt.Sprintf(" AND observation_date <= $%d", argNum)
 		args = append(args, *filter.EndDate)
 		argNum++
 	}
 
+	if filter.CreatedAfter != nil {
+		where += fmt.Sprintf(" AND created_at > $%d", argNum)
+		args = append(args, *filter.CreatedAfter)
+		argNum++
+	}
+
+	if filter.MissingField != nil {
+		if column, ok := observationMetricColumns[*filter.MissingField]; ok {
+			where += " AND " + column + " IS NULL"
+		}
+	}
+
+	return where, args, argNum
+}
+
+// GetObservations retrieves weather observations with filtering and pagination
+func (r *weatherRepository) GetObservations(ctx context.Context, filter ObservationFilter) ([]*models.WeatherObservation, int, error) {
 	// Get total count
-	countQuery := "SELECT COUNT(*) FROM (" + query + ") AS count_query"
-	var totalCount int
-	err := r.db.GetContext(ctx, "count_observations", &totalCount, countQuery, args...)
+	totalCount, err := r.CountObservations(ctx, filter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count observations: %w", err)
+		return nil, 0, err
 	}
 
+	// Build query with filters
+	where, args, argNum := buildObservationWhere(filter)
+	query := `
+		SELECT id, station_id, observation_date,
+		       max_temperature_celsius, min_temperature_celsius, precipitation_cm,
+		       created_at
+		FROM ` + r.tables.Observations + `
+	` + where
+
 	// Add ordering and pagination
 	query += " ORDER BY observation_date DESC, station_id"
 	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argNum, argNum+1)
@@ -291,13 +590,58 @@ func (r *weatherRepository) GetObservations(ctx context.Context, filter Observat
 	return observations, totalCount, nil
 }
 
+// GetDiurnalRange retrieves max minus min temperature for observations matching
+// filter, with pagination; days missing either temperature are excluded
+func (r *weatherRepository) GetDiurnalRange(ctx context.Context, filter ObservationFilter) ([]*models.DiurnalRange, int, error) {
+	where, args, argNum := buildObservationWhere(filter)
+	where += " AND max_temperature_celsius IS NOT NULL AND min_temperature_celsius IS NOT NULL"
+
+	var totalCount int
+	err := r.db.GetContext(ctx, "count_diurnal_range", &totalCount, "SELECT COUNT(*) FROM "+r.tables.Observations+where, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count diurnal ranges: %w", err)
+	}
+
+	query := `
+		SELECT station_id, observation_date,
+		       max_temperature_celsius, min_temperature_celsius,
+		       max_temperature_celsius - min_temperature_celsius AS range_celsius
+		FROM ` + r.tables.Observations + where + `
+		ORDER BY observation_date DESC, station_id` +
+		fmt.Sprintf(" LIMIT $%d OFFSET $%d", argNum, argNum+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var ranges []*models.DiurnalRange
+	err = r.db.SelectContext(ctx, "get_diurnal_range", &ranges, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get diurnal ranges: %w", err)
+	}
+
+	return ranges, totalCount, nil
+}
+
+// CountObservations returns the number of observations matching filter
+// Limit and Offset are ignored
+func (r *weatherRepository) CountObservations(ctx context.Context, filter ObservationFilter) (int, error) {
+	where, args, _ := buildObservationWhere(filter)
+	query := "SELECT COUNT(*) FROM " + r.tables.Observations + where
+
+	var count int
+	err := r.db.GetContext(ctx, "count_observations", &count, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count observations: %w", err)
+	}
+
+	return count, nil
+}
+
 // GetObservationByStationDate retrieves a specific observation
 func (r *weatherRepository) GetObservationByStationDate(ctx context.Context, stationID string, date time.Time) (*models.WeatherObservation, error) {
 	query := `
 		SELECT id, station_id, observation_date,
 		       max_temperature_celsius, min_temperature_celsius, precipitation_cm,
 		       created_at
-		FROM weather_observations
+		FROM ` + r.tables.Observations + `
 		WHERE station_id = $1 AND observation_date = $2
 	`
 
@@ -318,144 +662,1265 @@ func (r *weatherRepository) GetObservationByStationDate(ctx context.Context, sta
 	return &obs, nil
 }
 
-// CreateStatistics creates new weather statistics
-func (r *weatherRepository) CreateStatistics(ctx context.Context, stats *models.WeatherStatistics) error {
+// GetObservationHistory retrieves the previous values of a station-date
+// observation, most recent change first
+func (r *weatherRepository) GetObservationHistory(ctx context.Context, stationID string, date time.Time) ([]*models.ObservationHistory, error) {
 	query := `
-		INSERT INTO weather_statistics (
-			station_id, year,
-			avg_max_temperature_celsius, avg_min_temperature_celsius, total_precipitation_cm,
-			observation_count, valid_max_temp_count, valid_min_temp_count, valid_precipitation_count,
-			created_at, updated_at
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		RETURNING id
+		SELECT id, observation_id, station_id, observation_date,
+		       max_temperature_celsius, min_temperature_celsius, precipitation_cm,
+		       changed_at
+		FROM ` + r.tables.History + `
+		WHERE station_id = $1 AND observation_date = $2
+		ORDER BY changed_at DESC, id DESC
 	`
 
-	err := r.db.DB().QueryRowContext(ctx, query,
-		stats.StationID,
-		stats.Year,
-		stats.AvgMaxTemperatureCelsius,
-		stats.AvgMinTemperatureCelsius,
-		stats.TotalPrecipitationCm,
-		stats.ObservationCount,
-		stats.ValidMaxTempCount,
-		stats.ValidMinTempCount,
-		stats.ValidPrecipitationCount,
-		stats.CreatedAt,
-		stats.UpdatedAt,
-	).Scan(&stats.ID)
+	history := []*models.ObservationHistory{}
+	err := r.db.SelectContext(ctx, "get_observation_history", &history, query, stationID, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observation history: %w", err)
+	}
+
+	return history, nil
+}
+
+// GetWeatherEvents retrieves every station whose observation on filter.Date
+// meets all of the filter's thresholds, ordered by station
+// NULL values never meet a threshold
+func (r *weatherRepository) GetWeatherEvents(ctx context.Context, filter EventFilter) ([]*models.WeatherEvent, error) {
+	where := " WHERE o.observation_date = $1"
+	args := []interface{}{filter.Date}
+	argNum := 2
+
+	if filter.MinPrecipitationCm != nil {
+		where += fmt.Sprintf(" AND o.precipitation_cm >= $%d", argNum)
+		args = append(args, *filter.MinPrecipitationCm)
+		argNum++
+	}
+
+	if filter.MaxTempAbove != nil {
+		where += fmt.Sprintf(" AND o.max_temperature_celsius > $%d", argNum)
+		args = append(args, *filter.MaxTempAbove)
+		argNum++
+	}
 
+	if filter.MinTempBelow != nil {
+		where += fmt.Sprintf(" AND o.min_temperature_celsius < $%d", argNum)
+		args = append(args, *filter.MinTempBelow)
+	}
+
+	query := `
+		SELECT o.station_id, s.state, o.observation_date,
+		       o.max_temperature_celsius, o.min_temperature_celsius, o.precipitation_cm
+		FROM ` + r.tables.Observations + ` o
+		JOIN ` + r.tables.Stations + ` s ON s.station_id = o.station_id
+	` + where + `
+		ORDER BY o.station_id
+	`
+
+	events := []*models.WeatherEvent{}
+	err := r.db.SelectContext(ctx, "get_weather_events", &events, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to create statistics: %w", err)
+		return nil, fmt.Errorf("failed to get weather events: %w", err)
 	}
 
-	return nil
+	return events, nil
 }
 
-// UpsertStatistics creates or updates weather statistics
-func (r *weatherRepository) UpsertStatistics(ctx context.Context, stats *models.WeatherStatistics) error {
+// GetGlobalStats retrieves the station and observation totals and the observation date span
+// Unless exact is set the observation count is the planner's row estimate from
+// pg_class, avoiding a full table scan; a table never analyzed falls back to an
+// exact count. The date span is read from the observation_date index.
+func (r *weatherRepository) GetGlobalStats(ctx context.Context, exact bool) (*models.GlobalStats, error) {
 	query := `
-		INSERT INTO weather_statistics (
-			station_id, year,
-			avg_max_temperature_celsius, avg_min_temperature_celsius, total_precipitation_cm,
-			observation_count, valid_max_temp_count, valid_min_temp_count, valid_precipitation_count,
-			created_at, updated_at
+		WITH estimate AS (
+			SELECT reltuples::bigint AS observation_count
+			FROM pg_class
+			WHERE oid = to_regclass($1::text) AND reltuples >= 0 AND NOT $2::boolean
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		ON CONFLICT (station_id, year) DO UPDATE SET
-			avg_max_temperature_celsius = EXCLUDED.avg_max_temperature_celsius,
-			avg_min_temperature_celsius = EXCLUDED.avg_min_temperature_celsius,
-			total_precipitation_cm = EXCLUDED.total_precipitation_cm,
-			observation_count = EXCLUDED.observation_count,
-			valid_max_temp_count = EXCLUDED.valid_max_temp_count,
-			valid_min_temp_count = EXCLUDED.valid_min_temp_count,
-			valid_precipitation_count = EXCLUDED.valid_precipitation_count,
-			updated_at = EXCLUDED.updated_at
-		RETURNING id
+		SELECT
+			(SELECT COUNT(*) FROM ` + r.tables.Stations + `) AS station_count,
+			COALESCE(
+				(SELECT observation_count FROM estimate),
+				(SELECT COUNT(*) FROM ` + r.tables.Observations + `)
+			) AS observation_count,
+			NOT EXISTS (SELECT 1 FROM estimate) AS observation_count_exact,
+			(SELECT MIN(observation_date) FROM ` + r.tables.Observations + `) AS first_observation_date,
+			(SELECT MAX(observation_date) FROM ` + r.tables.Observations + `) AS last_observation_date
 	`
 
-	err := r.db.DB().QueryRowContext(ctx, query,
-		stats.StationID,
-		stats.Year,
-		stats.AvgMaxTemperatureCelsius,
-		stats.AvgMinTemperatureCelsius,
-		stats.TotalPrecipitationCm,
-		stats.ObservationCount,
-		stats.ValidMaxTempCount,
-		stats.ValidMinTempCount,
-		stats.ValidPrecipitationCount,
-		stats.CreatedAt,
-		stats.UpdatedAt,
-	).Scan(&stats.ID)
-
+	var stats models.GlobalStats
+	err := r.db.GetContext(ctx, "get_global_stats", &stats, query, r.tables.Observations, exact)
 	if err != nil {
-		return fmt.Errorf("failed to upsert statistics: %w", err)
+		return nil, fmt.Errorf("failed to get global stats: %w", err)
 	}
 
-	return nil
+	return &stats, nil
 }
 
-// GetStatistics retrieves weather statistics with filtering and pagination
-func (r *weatherRepository) GetStatistics(ctx context.Context, filter StatisticsFilter) ([]*models.WeatherStatistics, int, error) {
-	// Build query with filters
+// GetLatestObservations returns the most recent observation for each station
+// An empty stationIDs slice returns the latest observation of every station
+func (r *weatherRepository) GetLatestObservations(ctx context.Context, stationIDs []string) ([]*models.WeatherObservation, error) {
 	query := `
-		SELECT id, station_id, year,
-		       avg_max_temperature_celsius, avg_min_temperature_celsius, total_precipitation_cm,
-		       observation_count, valid_max_temp_count, valid_min_temp_count, valid_precipitation_count,
-		       created_at, updated_at
-		FROM weather_statistics
-		WHERE 1=1
+		SELECT DISTINCT ON (station_id)
+		       id, station_id, observation_date,
+		       max_temperature_celsius, min_temperature_celsius, precipitation_cm,
+		       created_at
+		FROM ` + r.tables.Observations + `
 	`
 	args := []interface{}{}
-	argNum := 1
 
-	if filter.StationID != nil {
-		query += fmt.Sprintf(" AND station_id = $%d", argNum)
-		args = append(args, *filter.StationID)
-		argNum++
+	if len(stationIDs) > 0 {
+		query += " WHERE station_id = ANY($1)"
+		args = append(args, pq.Array(stationIDs))
 	}
 
-	if filter.Year != nil {
-		query += fmt.Sprintf(" AND year = $%d", argNum)
-		args = append(args, *filter.Year)
-		argNum++
+	query += " ORDER BY station_id, observation_date DESC"
+
+	observations := []*models.WeatherObservation{}
+	err := r.db.SelectContext(ctx, "get_latest_observations", &observations, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest observations: %w", err)
 	}
 
-	// Get total count
-	countQuery := "SELECT COUNT(*) FROM (" + query + ") AS count_query"
-	var totalCount int
-	err := r.db.GetContext(ctx, "count_statistics", &totalCount, countQuery, args...)
+	return observations, nil
+}
+
+// CompactDuplicates removes duplicate (station_id, observation_date) rows,
+// keeping the most recently created row of each group, and returns the number removed
+// A safety net for data integrity should the unique constraint ever be missing
+func (r *weatherRepository) CompactDuplicates(ctx context.Context) (int64, error) {
+	tx, err := r.db.BeginTx(ctx)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count statistics: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Add ordering and pagination
-	query += " ORDER BY year DESC, station_id"
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argNum, argNum+1)
-	args = append(args, filter.Limit, filter.Offset)
+	var groups int64
+	err = tx.GetContext(ctx, &groups, `
+		SELECT COUNT(*) FROM (
+			SELECT 1
+			FROM `+r.tables.Observations+`
+			GROUP BY station_id, observation_date
+			HAVING COUNT(*) > 1
+		) AS duplicate_groups
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count duplicate groups: %w", err)
+	}
 
-	// Execute query
-	var statistics []*models.WeatherStatistics
-	err = r.db.SelectContext(ctx, "get_statistics", &statistics, query, args...)
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM `+r.tables.Observations+`
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (
+					PARTITION BY station_id, observation_date
+					ORDER BY created_at DESC, id DESC
+				) AS rn
+				FROM `+r.tables.Observations+`
+			) AS ranked
+			WHERE rn > 1
+		)
+	`)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get statistics: %w", err)
+		return 0, fmt.Errorf("failed to delete duplicate observations: %w", err)
 	}
 
-	return statistics, totalCount, nil
-}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count removed observations: %w", err)
+	}
 
-// CalculateYearlyStatistics calculates statistics for a station and year
-func (r *weatherRepository) CalculateYearlyStatistics(ctx context.Context, stationID string, year int) (*models.WeatherStatistics, error) {
-	timer := time.Now()
-	defer func() {
-		duration := time.Since(timer)
-		r.metrics.StatsCalculationDuration.Observe(duration.Seconds())
-		r.logger.Debug(ctx, "[REPO_CALC_STATS] Statistics calculated", logging.Fields{
-			"station_id":  stationID,
-			"year":        year,
-			"duration_ms": duration.Milliseconds(),
-		})
-	}()
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Info(ctx, "[REPO_COMPACT_DUPLICATES] Duplicate observations compacted", logging.Fields{
+		"duplicate_groups": groups,
+		"rows_removed":     removed,
+	})
+
+	return removed, nil
+}
+
+// purgeBatchSize is the number of observations deleted per statement by
+// PurgeObservationsBefore, keeping each delete's locks short-lived
+const purgeBatchSize = 10000
+
+// PurgeObservationsBefore deletes observations dated before cutoff in batches
+// and returns the number removed
+// Only observations whose station-year already has a statistics row are
+// deleted, so the pre-aggregated statistics outlive the raw data; the rest
+// are kept and counted in the log so statistics can be calculated first.
+// Batches are found from the statistics side, one index range per
+// station-year (the same calendar range as statisticsYear), so retained rows
+// are never rescanned however many accumulate.
+func (r *weatherRepository) PurgeObservationsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		DELETE FROM ` + r.tables.Observations + `
+		WHERE id IN (
+			SELECT o.id
+			FROM ` + r.tables.Statistics + ` s
+			JOIN ` + r.tables.Observations + ` o
+			  ON o.station_id = s.station_id
+			 AND o.observation_date >= make_date(s.year, 1, 1)
+			 AND o.observation_date < make_date(s.year + 1, 1, 1)
+			WHERE s.year <= $2
+			  AND o.observation_date < $1
+			LIMIT $3
+		)
+	`
+
+	var removed int64
+	batches := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+
+		result, err := r.db.ExecContext(ctx, "purge_observations", query, cutoff, cutoff.Year(), purgeBatchSize)
+		if err != nil {
+			return removed, fmt.Errorf("failed to purge observations: %w", err)
+		}
+
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return removed, fmt.Errorf("failed to count purged observations: %w", err)
+		}
+
+		removed += deleted
+		batches++
+		if deleted < purgeBatchSize {
+			break
+		}
+	}
+
+	var retained int64
+	err := r.db.GetContext(ctx, "count_unaggregated_observations", &retained, `
+		SELECT COUNT(*) FROM `+r.tables.Observations+`
+		WHERE observation_date < $1
+	`, cutoff)
+	if err != nil {
+		return removed, fmt.Errorf("failed to count retained observations: %w", err)
+	}
+
+	r.logger.Info(ctx, "[REPO_PURGE_OBSERVATIONS] Observations purged", logging.Fields{
+		"cutoff":       cutoff.Format("2006-01-02"),
+		"rows_removed": removed,
+		"batches":      batches,
+	})
+	if retained > 0 {
+		r.logger.Warn(ctx, "[REPO_PURGE_RETAINED] Observations before the cutoff kept because their year has no statistics", logging.Fields{
+			"cutoff":        cutoff.Format("2006-01-02"),
+			"rows_retained": retained,
+		})
+	}
+
+	return removed, nil
+}
+
+// ListAvailableYears returns the sorted distinct years that have observations
+// Optionally scoped to a single station
+func (r *weatherRepository) ListAvailableYears(ctx context.Context, stationID *string) ([]int, error) {
+	query := `
+		SELECT DISTINCT EXTRACT(YEAR FROM observation_date)::INTEGER AS year
+		FROM ` + r.tables.Observations + `
+	`
+	args := []interface{}{}
+
+	if stationID != nil {
+		query += " WHERE station_id = $1"
+		args = append(args, *stationID)
+	}
+
+	query += " ORDER BY year"
+
+	years := []int{}
+	err := r.db.SelectContext(ctx, "list_available_years", &years, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list available years: %w", err)
+	}
+
+	return years, nil
+}
+
+// CompareStations returns aligned daily values of a metric for two stations
+// Days where only one station has data are returned with NULL for the other side
+func (r *weatherRepository) CompareStations(ctx context.Context, stationA, stationB, metric string, from, to time.Time) ([]*models.DailyComparison, error) {
+	column, ok := observationMetricColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metric: %s", metric)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COALESCE(a.observation_date, b.observation_date) AS observation_date,
+		       a.value AS station_a_value,
+		       b.value AS station_b_value,
+		       a.value - b.value AS difference
+		FROM (
+			SELECT observation_date, %[1]s AS value
+			FROM `+r.tables.Observations+`
+			WHERE station_id = $1 AND observation_date BETWEEN $3 AND $4
+		) a
+		FULL OUTER JOIN (
+			SELECT observation_date, %[1]s AS value
+			FROM `+r.tables.Observations+`
+			WHERE station_id = $2 AND observation_date BETWEEN $3 AND $4
+		) b ON a.observation_date = b.observation_date
+		ORDER BY observation_date
+	`, column)
+
+	var comparisons []*models.DailyComparison
+	err := r.db.SelectContext(ctx, "compare_stations", &comparisons, query, stationA, stationB, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare stations: %w", err)
+	}
+
+	return comparisons, nil
+}
+
+// GetMovingAverage returns a trailing moving average of a metric over the last window observations
+// Rows with a NULL metric are excluded from the window rather than counted as gaps
+func (r *weatherRepository) GetMovingAverage(ctx context.Context, stationID, metric string, window int, from, to time.Time) ([]*models.MovingAveragePoint, error) {
+	column, ok := observationMetricColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metric: %s", metric)
+	}
+	if window < 1 {
+		return nil, fmt.Errorf("invalid window: %d", window)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT observation_date,
+		       %[1]s AS value,
+		       AVG(%[1]s) OVER w AS moving_average,
+		       COUNT(*) OVER w AS sample_count
+		FROM `+r.tables.Observations+`
+		WHERE station_id = $1 AND observation_date BETWEEN $2 AND $3
+		  AND %[1]s IS NOT NULL
+		WINDOW w AS (ORDER BY observation_date ROWS BETWEEN %[2]d PRECEDING AND CURRENT ROW)
+		ORDER BY observation_date
+	`, column, window-1)
+
+	points := []*models.MovingAveragePoint{}
+	err := r.db.SelectContext(ctx, "get_moving_average", &points, query, stationID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moving average: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetHistogram buckets non-null values of a metric into equal-width bins between their min and max
+// Every bin is returned, including empty ones; no data yields an empty slice
+func (r *weatherRepository) GetHistogram(ctx context.Context, metric string, bins int, filter ObservationFilter) ([]*models.HistogramBin, error) {
+	column, ok := observationMetricColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metric: %s", metric)
+	}
+	if bins < 1 {
+		return nil, fmt.Errorf("invalid bin count: %d", bins)
+	}
+
+	where, args, argNum := buildObservationWhere(filter)
+	query := fmt.Sprintf(`
+		WITH filtered AS (
+			SELECT %[1]s AS value
+			FROM `+r.tables.Observations+`
+			%[2]s AND %[1]s IS NOT NULL
+		),
+		bounds AS (
+			SELECT MIN(value) AS lo, MAX(value) AS hi FROM filtered
+		)
+		SELECT CASE WHEN b.hi = b.lo THEN 1
+		            ELSE LEAST(width_bucket(f.value, b.lo, b.hi, $%[3]d), $%[3]d)
+		       END AS bucket,
+		       COUNT(*) AS count,
+		       b.lo AS lo,
+		       b.hi AS hi
+		FROM filtered f CROSS JOIN bounds b
+		GROUP BY bucket, b.lo, b.hi
+		ORDER BY bucket
+	`, column, where, argNum)
+	args = append(args, bins)
+
+	var rows []struct {
+		Bucket int     `db:"bucket"`
+		Count  int     `db:"count"`
+		Lo     float64 `db:"lo"`
+		Hi     float64 `db:"hi"`
+	}
+	err := r.db.SelectContext(ctx, "get_histogram", &rows, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get histogram: %w", err)
+	}
+
+	histogram := []*models.HistogramBin{}
+	if len(rows) == 0 {
+		return histogram, nil
+	}
+
+	lo, hi := rows[0].Lo, rows[0].Hi
+	width := (hi - lo) / float64(bins)
+	for i := 0; i < bins; i++ {
+		histogram = append(histogram, &models.HistogramBin{
+			BinStart: lo + float64(i)*width,
+			BinEnd:   lo + float64(i+1)*width,
+		})
+	}
+	histogram[bins-1].BinEnd = hi
+
+	for _, row := range rows {
+		histogram[row.Bucket-1].Count = row.Count
+	}
+
+	return histogram, nil
+}
+
+// CalculateFrostFreeSeason finds the frost-free season for a station-year
+// Years without frost span the whole year; years where every observed minimum
+// is at or below 0°C have no season. Returns NotFoundError without min temperature data.
+func (r *weatherRepository) CalculateFrostFreeSeason(ctx context.Context, stationID string, year int) (*models.FrostFreeSeason, error) {
+	query := `
+		SELECT MAX(observation_date) FILTER (
+		           WHERE min_temperature_celsius <= 0 AND EXTRACT(MONTH FROM observation_date) < 7
+		       ) AS last_spring_frost,
+		       MIN(observation_date) FILTER (
+		           WHERE min_temperature_celsius <= 0 AND EXTRACT(MONTH FROM observation_date) >= 7
+		       ) AS first_autumn_frost,
+		       COUNT(*) AS observation_count,
+		       COUNT(*) FILTER (WHERE min_temperature_celsius > 0) AS frost_free_days
+		FROM ` + r.tables.Observations + `
+		WHERE station_id = $1
+		  AND observation_date BETWEEN make_date($2, 1, 1) AND make_date($2, 12, 31)
+		  AND min_temperature_celsius IS NOT NULL
+	`
+
+	var row struct {
+		LastSpringFrost  *time.Time `db:"last_spring_frost"`
+		FirstAutumnFrost *time.Time `db:"first_autumn_frost"`
+		ObservationCount int        `db:"observation_count"`
+		FrostFreeDays    int        `db:"frost_free_days"`
+	}
+	err := r.db.GetContext(ctx, "calculate_frost_free_season", &row, query, stationID, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate frost-free season: %w", err)
+	}
+
+	if row.ObservationCount == 0 {
+		return nil, &NotFoundError{
+			Resource: "min_temperature_observations",
+			ID:       fmt.Sprintf("%s:%d", stationID, year),
+		}
+	}
+
+	season := &models.FrostFreeSeason{
+		StationID:        stationID,
+		Year:             year,
+		LastSpringFrost:  row.LastSpringFrost,
+		FirstAutumnFrost: row.FirstAutumnFrost,
+	}
+
+	if row.FrostFreeDays == 0 {
+		season.Status = models.FrostSeasonAllFrost
+		return season, nil
+	}
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	if row.LastSpringFrost != nil {
+		start = models.NormalizeDate(*row.LastSpringFrost).AddDate(0, 0, 1)
+	}
+	if row.FirstAutumnFrost != nil {
+		end = models.NormalizeDate(*row.FirstAutumnFrost).AddDate(0, 0, -1)
+	}
+
+	season.Status = models.FrostSeasonNormal
+	if row.LastSpringFrost == nil && row.FirstAutumnFrost == nil {
+		season.Status = models.FrostSeasonNoFrost
+	}
+
+	season.SeasonStart = &start
+	season.SeasonEnd = &end
+	season.LengthDays = int(end.Sub(start).Hours()/24) + 1
+
+	return season, nil
+}
+
+// growingDegreeDayCeiling caps daily temperatures counted towards growing
+// degree days, above which crops are assumed to grow no faster
+const growingDegreeDayCeiling = 30.0
+
+// CalculateDegreeDays sums heating (base - mean), cooling (mean - base) and
+// growing degree days for a station-year (see GetYearlyDegreeDays)
+// Returns NotFoundError when no day has both temperatures
+func (r *weatherRepository) CalculateDegreeDays(ctx context.Context, stationID string, year int, base float64) (*models.DegreeDays, error) {
+	degreeDays, err := r.degreeDaysByYear(ctx, stationID, base, &year)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(degreeDays) == 0 {
+		return nil, &NotFoundError{
+			Resource: "temperature_observations",
+			ID:       fmt.Sprintf("%s:%d", stationID, year),
+		}
+	}
+
+	return degreeDays[0], nil
+}
+
+// GetYearlyDegreeDays returns a station's degree-day totals for every year
+// with at least one day that has both temperatures, oldest first
+func (r *weatherRepository) GetYearlyDegreeDays(ctx context.Context, stationID string, base float64) ([]*models.DegreeDays, error) {
+	return r.degreeDaysByYear(ctx, stationID, base, nil)
+}
+
+// degreeDaysByYear sums degree days per year, limited to year when it is set
+// A day's mean is (max + min) / 2 and days missing either temperature are
+// excluded. Growing degree days use the modified method: both temperatures
+// are clamped to [base, growingDegreeDayCeiling] before taking the mean.
+func (r *weatherRepository) degreeDaysByYear(ctx context.Context, stationID string, base float64, year *int) ([]*models.DegreeDays, error) {
+	args := []interface{}{stationID, base, growingDegreeDayCeiling}
+	yearFilter := ""
+	if year != nil {
+		args = append(args, *year)
+		yearFilter = "AND observation_date BETWEEN make_date($4, 1, 1) AND make_date($4, 12, 31)"
+	}
+
+	query := `
+		SELECT EXTRACT(YEAR FROM observation_date)::int AS year,
+		       SUM(GREATEST(0, $2 - daily_mean)) AS heating_degree_days,
+		       SUM(GREATEST(0, daily_mean - $2)) AS cooling_degree_days,
+		       SUM(GREATEST(0, (LEAST(GREATEST(max_temperature_celsius, $2), $3)
+		                      + LEAST(GREATEST(min_temperature_celsius, $2), $3)) / 2 - $2)) AS growing_degree_days,
+		       COUNT(*) AS days_counted
+		FROM (
+			SELECT observation_date, max_temperature_celsius, min_temperature_celsius,
+			       (max_temperature_celsius + min_temperature_celsius) / 2 AS daily_mean
+			FROM ` + r.tables.Observations + `
+			WHERE station_id = $1
+			  AND max_temperature_celsius IS NOT NULL
+			  AND min_temperature_celsius IS NOT NULL
+			  ` + yearFilter + `
+		) AS daily
+		GROUP BY 1
+		ORDER BY 1
+	`
+
+	var degreeDays []*models.DegreeDays
+	err := r.db.SelectContext(ctx, "calculate_degree_days", &degreeDays, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate degree days: %w", err)
+	}
+
+	for _, yearly := range degreeDays {
+		yearly.StationID = stationID
+		yearly.BaseCelsius = base
+	}
+
+	return degreeDays, nil
+}
+
+// GetMonthlyObservationCounts returns a station-year's observation count for
+// each of the 12 months, zero-filling months without observations
+func (r *weatherRepository) GetMonthlyObservationCounts(ctx context.Context, stationID string, year int) ([]*models.MonthlyObservationCount, error) {
+	query := `
+		SELECT m.month, COALESCE(c.observation_count, 0) AS observation_count
+		FROM generate_series(1, 12) AS m(month)
+		LEFT JOIN (
+			SELECT EXTRACT(MONTH FROM observation_date)::int AS month, COUNT(*) AS observation_count
+			FROM ` + r.tables.Observations + `
+			WHERE station_id = $1
+			  AND observation_date BETWEEN make_date($2, 1, 1) AND make_date($2, 12, 31)
+			GROUP BY 1
+		) AS c ON c.month = m.month
+		ORDER BY m.month
+	`
+
+	var counts []*models.MonthlyObservationCount
+	err := r.db.SelectContext(ctx, "monthly_observation_counts", &counts, query, stationID, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count observations by month: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetClimateNormals returns a station's per-day-of-year averages across all
+// years, one row for each of the 366 calendar days including 29 February
+// Days without observations have NULL averages and zero counts
+func (r *weatherRepository) GetClimateNormals(ctx context.Context, stationID string) ([]*models.ClimateNormal, error) {
+	// 2000 is a leap year, so its calendar yields every month/day pair
+	query := `
+		SELECT EXTRACT(MONTH FROM d.day)::int AS month,
+		       EXTRACT(DAY FROM d.day)::int AS day,
+		       n.avg_max_temperature_celsius,
+		       n.avg_min_temperature_celsius,
+		       n.mean_precipitation_cm,
+		       COALESCE(n.year_count, 0) AS year_count,
+		       COALESCE(n.max_temp_years, 0) AS max_temp_years,
+		       COALESCE(n.min_temp_years, 0) AS min_temp_years,
+		       COALESCE(n.precipitation_years, 0) AS precipitation_years
+		FROM generate_series(DATE '2000-01-01', DATE '2000-12-31', INTERVAL '1 day') AS d(day)
+		LEFT JOIN (
+			SELECT EXTRACT(MONTH FROM observation_date)::int AS month,
+			       EXTRACT(DAY FROM observation_date)::int AS day,
+			       AVG(max_temperature_celsius) AS avg_max_temperature_celsius,
+			       AVG(min_temperature_celsius) AS avg_min_temperature_celsius,
+			       AVG(precipitation_cm) AS mean_precipitation_cm,
+			       COUNT(*) AS year_count,
+			       COUNT(max_temperature_celsius) AS max_temp_years,
+			       COUNT(min_temperature_celsius) AS min_temp_years,
+			       COUNT(precipitation_cm) AS precipitation_years
+			FROM ` + r.tables.Observations + `
+			WHERE station_id = $1
+			GROUP BY 1, 2
+		) AS n ON n.month = EXTRACT(MONTH FROM d.day) AND n.day = EXTRACT(DAY FROM d.day)
+		ORDER BY d.day
+	`
+
+	var normals []*models.ClimateNormal
+	err := r.db.SelectContext(ctx, "climate_normals", &normals, query, stationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get climate normals: %w", err)
+	}
+
+	return normals, nil
+}
+
+// GetCorrelation returns the Pearson correlation between two observation
+// metrics over the filtered observations
+// Rows missing either metric are excluded; the coefficient is NULL when fewer
+// than two rows remain or either metric is constant
+func (r *weatherRepository) GetCorrelation(ctx context.Context, metricX, metricY string, filter ObservationFilter) (*models.Correlation, error) {
+	query, args, err := buildCorrelationQuery(r.tables.Observations, metricX, metricY, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var correlation models.Correlation
+	err = r.db.GetContext(ctx, "get_correlation", &correlation, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get correlation: %w", err)
+	}
+
+	return &correlation, nil
+}
+
+// buildCorrelationQuery builds the correlation query over table for two
+// observation metrics, returning it with its arguments
+// corr() yields NULL for fewer than two rows or a constant metric, which
+// leaves the coefficient nil
+func buildCorrelationQuery(table, metricX, metricY string, filter ObservationFilter) (string, []interface{}, error) {
+	columnX, ok := observationMetricColumns[metricX]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported metric: %s", metricX)
+	}
+	columnY, ok := observationMetricColumns[metricY]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported metric: %s", metricY)
+	}
+
+	where, args, _ := buildObservationWhere(filter)
+	query := fmt.Sprintf(`
+		SELECT corr(%[1]s, %[2]s) AS coefficient,
+		       COUNT(*) AS sample_size
+		FROM `+table+`
+		%[3]s AND %[1]s IS NOT NULL AND %[2]s IS NOT NULL
+	`, columnX, columnY, where)
+
+	return query, args, nil
+}
+
+// GetLongestStreak returns a station's longest run of consecutive days
+// meeting condition, the earliest one on ties
+// A missing day or a NULL value breaks the run; with no qualifying day the
+// streak has zero length and no dates
+func (r *weatherRepository) GetLongestStreak(ctx context.Context, stationID string, condition string, threshold float64) (*models.Streak, error) {
+	query, err := buildStreakQuery(r.tables.Observations, condition)
+	if err != nil {
+		return nil, err
+	}
+
+	var streak models.Streak
+	err = r.db.GetContext(ctx, "longest_streak", &streak, query, stationID, threshold)
+	if err == sql.ErrNoRows {
+		return &models.Streak{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get longest %s streak: %w", condition, err)
+	}
+
+	return &streak, nil
+}
+
+// buildStreakQuery builds the longest-streak query over table for condition,
+// binding the station as $1 and the threshold as $2
+func buildStreakQuery(table, condition string) (string, error) {
+	predicate, ok := streakConditions[condition]
+	if !ok {
+		return "", fmt.Errorf("unsupported streak condition: %s", condition)
+	}
+
+	// Gaps and islands: consecutive dates minus their row number share a
+	// constant, so each run of qualifying days forms one group
+	return `
+		WITH islands AS (
+			SELECT observation_date,
+			       observation_date - (ROW_NUMBER() OVER (ORDER BY observation_date))::int AS island
+			FROM ` + table + `
+			WHERE station_id = $1 AND ` + predicate + `
+		)
+		SELECT COUNT(*) AS length,
+		       MIN(observation_date) AS start_date,
+		       MAX(observation_date) AS end_date
+		FROM islands
+		GROUP BY island
+		ORDER BY length DESC, start_date
+		LIMIT 1
+	`, nil
+}
+
+// GetExtremes returns the hottest, coldest, and wettest days
+// Optionally scoped to a year and/or station; NULL values never qualify
+func (r *weatherRepository) GetExtremes(ctx context.Context, year *int, stationID *string) (*models.WeatherExtremes, error) {
+	hottest, err := r.findExtreme(ctx, "max_temperature_celsius", "DESC", year, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	coldest, err := r.findExtreme(ctx, "min_temperature_celsius", "ASC", year, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	wettest, err := r.findExtreme(ctx, "precipitation_cm", "DESC", year, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WeatherExtremes{
+		Hottest: hottest,
+		Coldest: coldest,
+		Wettest: wettest,
+	}, nil
+}
+
+// findExtreme returns the single observation with the highest or lowest column value
+// column and direction are internal constants, never user input
+func (r *weatherRepository) findExtreme(ctx context.Context, column, direction string, year *int, stationID *string) (*models.ExtremeRecord, error) {
+	query := fmt.Sprintf(`
+		SELECT station_id, observation_date, %[1]s AS value
+		FROM `+r.tables.Observations+`
+		WHERE %[1]s IS NOT NULL
+	`, column)
+	args := []interface{}{}
+	argNum := 1
+
+	if stationID != nil {
+		query += fmt.Sprintf(" AND station_id = $%d", argNum)
+		args = append(args, *stationID)
+		argNum++
+	}
+
+	if year != nil {
+		start := time.Date(*year, 1, 1, 0, 0, 0, 0, time.UTC)
+		query += fmt.Sprintf(" AND observation_date >= $%d AND observation_date < $%d", argNum, argNum+1)
+		args = append(args, start, start.AddDate(1, 0, 0))
+		argNum += 2
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s %s, observation_date, station_id LIMIT 1", column, direction)
+
+	var record models.ExtremeRecord
+	err := r.db.GetContext(ctx, "find_extreme", &record, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find extreme %s: %w", column, err)
+	}
+
+	return &record, nil
+}
+
+// GetMissingDates returns calendar dates in [from, to] with no observation row for a station
+func (r *weatherRepository) GetMissingDates(ctx context.Context, stationID string, from, to time.Time) ([]time.Time, error) {
+	query := `
+		SELECT d::date AS missing_date
+		FROM generate_series($2::date, $3::date, INTERVAL '1 day') AS d
+		LEFT JOIN ` + r.tables.Observations + ` o
+		       ON o.station_id = $1 AND o.observation_date = d::date
+		WHERE o.id IS NULL
+		ORDER BY d
+	`
+
+	dates := []time.Time{}
+	err := r.db.SelectContext(ctx, "get_missing_dates", &dates, query, stationID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get missing dates: %w", err)
+	}
+
+	return dates, nil
+}
+
+// BackfillMissingDates inserts all-NULL observations for calendar dates in [from, to]
+// that have no row for the station, so downstream queries see a dense daily series.
+// Returns the number of rows inserted, or NotFoundError when the station does not exist.
+func (r *weatherRepository) BackfillMissingDates(ctx context.Context, stationID string, from, to time.Time) (int64, error) {
+	query := `
+		INSERT INTO ` + r.tables.Observations + ` (
+			station_id, observation_date,
+			max_temperature_celsius, min_temperature_celsius, precipitation_cm,
+			created_at
+		)
+		SELECT $1, d::date, NULL, NULL, NULL, NOW()
+		FROM generate_series($2::date, $3::date, INTERVAL '1 day') AS d
+		ON CONFLICT (station_id, observation_date) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, "backfill_missing_dates", query, stationID, from, to)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return 0, &NotFoundError{
+			Resource: "station",
+			ID:       stationID,
+		}
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to backfill missing dates: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count backfilled rows: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// CreateStatistics creates new weather statistics
+func (r *weatherRepository) CreateStatistics(ctx context.Context, stats *models.WeatherStatistics) error {
+	query := `
+		INSERT INTO ` + r.tables.Statistics + ` (
+			station_id, year,
+			avg_max_temperature_celsius, avg_min_temperature_celsius, total_precipitation_cm, avg_diurnal_range_celsius,
+			observation_count, valid_max_temp_count, valid_min_temp_count, valid_precipitation_count,
+			is_reliable, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
+	`
+
+	err := r.db.DB().QueryRowContext(ctx, query,
+		stats.StationID,
+		stats.Year,
+		stats.AvgMaxTemperatureCelsius,
+		stats.AvgMinTemperatureCelsius,
+		stats.TotalPrecipitationCm,
+		stats.AvgDiurnalRangeCelsius,
+		stats.ObservationCount,
+		stats.ValidMaxTempCount,
+		stats.ValidMinTempCount,
+		stats.ValidPrecipitationCount,
+		stats.IsReliable,
+		stats.CreatedAt,
+		stats.UpdatedAt,
+	).Scan(&stats.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create statistics: %w", err)
+	}
+
+	return nil
+}
+
+// statisticsUpsertQuery inserts a statistics row, replacing an existing station-year
+func (r *weatherRepository) statisticsUpsertQuery() string {
+	return `
+		INSERT INTO ` + r.tables.Statistics + ` (
+			station_id, year,
+			avg_max_temperature_celsius, avg_min_temperature_celsius, total_precipitation_cm, avg_diurnal_range_celsius,
+			observation_count, valid_max_temp_count, valid_min_temp_count, valid_precipitation_count,
+			is_reliable, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (station_id, year) DO UPDATE SET
+			avg_max_temperature_celsius = EXCLUDED.avg_max_temperature_celsius,
+			avg_min_temperature_celsius = EXCLUDED.avg_min_temperature_celsius,
+			total_precipitation_cm = EXCLUDED.total_precipitation_cm,
+			avg_diurnal_range_celsius = EXCLUDED.avg_diurnal_range_celsius,
+			observation_count = EXCLUDED.observation_count,
+			valid_max_temp_count = EXCLUDED.valid_max_temp_count,
+			valid_min_temp_count = EXCLUDED.valid_min_temp_count,
+			valid_precipitation_count = EXCLUDED.valid_precipitation_count,
+			is_reliable = EXCLUDED.is_reliable,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+}
+
+// statisticsUpsertArgs returns the statisticsUpsertQuery arguments for stats
+func statisticsUpsertArgs(stats *models.WeatherStatistics) []interface{} {
+	return []interface{}{
+		stats.StationID,
+		stats.Year,
+		stats.AvgMaxTemperatureCelsius,
+		stats.AvgMinTemperatureCelsius,
+		stats.TotalPrecipitationCm,
+		stats.AvgDiurnalRangeCelsius,
+		stats.ObservationCount,
+		stats.ValidMaxTempCount,
+		stats.ValidMinTempCount,
+		stats.ValidPrecipitationCount,
+		stats.IsReliable,
+		stats.CreatedAt,
+		stats.UpdatedAt,
+	}
+}
+
+// UpsertStatistics creates or updates weather statistics
+func (r *weatherRepository) UpsertStatistics(ctx context.Context, stats *models.WeatherStatistics) error {
+	err := r.db.DB().QueryRowContext(ctx, r.statisticsUpsertQuery(), statisticsUpsertArgs(stats)...).Scan(&stats.ID)
+	if err != nil {
+		return fmt.Errorf("failed to upsert statistics: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertStatisticsBatch creates or updates multiple statistics rows in a single transaction
+// Either every row is saved or none are
+func (r *weatherRepository) UpsertStatisticsBatch(ctx context.Context, stats []*models.WeatherStatistics) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	timer := time.Now()
+	defer func() {
+		r.logger.Debug(ctx, "[REPO_STATS_BATCH_UPSERT] Statistics batch upsert completed", logging.Fields{
+			"count":       len(stats),
+			"duration_ms": time.Since(timer).Milliseconds(),
+		})
+	}()
+
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, r.statisticsUpsertQuery())
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range stats {
+		if err := stmt.QueryRowContext(ctx, statisticsUpsertArgs(row)...).Scan(&row.ID); err != nil {
+			return fmt.Errorf("failed to upsert statistics for %s %d: %w", row.StationID, row.Year, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetStatistics retrieves weather statistics with filtering and pagination
+func (r *weatherRepository) GetStatistics(ctx context.Context, filter StatisticsFilter) ([]*models.WeatherStatistics, int, error) {
+	// Build query with filters
+	where, args, argNum := buildStatisticsWhere(filter)
+	query := `
+		SELECT id, station_id, year,
+		       avg_max_temperature_celsius, avg_min_temperature_celsius, total_precipitation_cm, avg_diurnal_range_celsius,
+		       observation_count, valid_max_temp_count, valid_min_temp_count, valid_precipitation_count,
+		       is_reliable, created_at, updated_at
+		FROM ` + r.tables.Statistics + where
+
+	// Get total count
+	countQuery := "SELECT COUNT(*) FROM (" + query + ") AS count_query"
+	var totalCount int
+	err := r.db.GetContext(ctx, "count_statistics", &totalCount, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count statistics: %w", err)
+	}
+
+	// Add ordering and pagination
+	query += " ORDER BY year DESC, station_id"
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argNum, argNum+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	// Execute query
+	var statistics []*models.WeatherStatistics
+	err = r.db.SelectContext(ctx, "get_statistics", &statistics, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get statistics: %w", err)
+	}
+
+	return statistics, totalCount, nil
+}
+
+// buildStatisticsWhere builds the WHERE clause and args for a StatisticsFilter
+// Returns the next free placeholder number for callers that append LIMIT/OFFSET
+func buildStatisticsWhere(filter StatisticsFilter) (string, []interface{}, int) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argNum := 1
+
+	if filter.StationID != nil {
+		where += fmt.Sprintf(" AND station_id = $%d", argNum)
+		args = append(args, *filter.StationID)
+		argNum++
+	}
+
+	if filter.Year != nil {
+		where += fmt.Sprintf(" AND year = $%d", argNum)
+		args = append(args, *filter.Year)
+		argNum++
+	}
+
+	if filter.IsReliable != nil {
+		where += fmt.Sprintf(" AND is_reliable = $%d", argNum)
+		args = append(args, *filter.IsReliable)
+		argNum++
+	}
+
+	return where, args, argNum
+}
+
+// StreamStatistics calls fn for every statistics row matching filter, ignoring Limit and Offset
+// Rows are scanned one at a time so exports never hold the full table in memory.
+// Iteration stops at the first error from fn, which is returned unwrapped.
+func (r *weatherRepository) StreamStatistics(ctx context.Context, filter StatisticsFilter, fn func(*models.WeatherStatistics) error) error {
+	where, args, _ := buildStatisticsWhere(filter)
+	query := `
+		SELECT id, station_id, year,
+		       avg_max_temperature_celsius, avg_min_temperature_celsius, total_precipitation_cm, avg_diurnal_range_celsius,
+		       observation_count, valid_max_temp_count, valid_min_temp_count, valid_precipitation_count,
+		       is_reliable, created_at, updated_at
+		FROM ` + r.tables.Statistics + where + `
+		ORDER BY station_id, year`
+
+	rows, err := r.db.QueryContext(ctx, "stream_statistics", query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query statistics: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stats models.WeatherStatistics
+		if err := rows.StructScan(&stats); err != nil {
+			return fmt.Errorf("failed to scan statistics: %w", err)
+		}
+		if err := fn(&stats); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to stream statistics: %w", err)
+	}
+
+	return nil
+}
+
+// ListStationsMissingStatistics returns stations that have observations but no statistics rows
+// Each station is returned with its observation count, ordered by station ID
+func (r *weatherRepository) ListStationsMissingStatistics(ctx context.Context) ([]*models.StationObservationCount, error) {
+	query := `
+		SELECT o.station_id, COUNT(*) AS observation_count
+		FROM ` + r.tables.Observations + ` o
+		WHERE NOT EXISTS (
+			SELECT 1 FROM ` + r.tables.Statistics + ` s WHERE s.station_id = o.station_id
+		)
+		GROUP BY o.station_id
+		ORDER BY o.station_id
+	`
+
+	var stations []*models.StationObservationCount
+	err := r.db.SelectContext(ctx, "list_stations_missing_statistics", &stations, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stations missing statistics: %w", err)
+	}
+
+	return stations, nil
+}
+
+// ListStationStatistics retrieves all yearly statistics for a station, oldest first
+func (r *weatherRepository) ListStationStatistics(ctx context.Context, stationID string) ([]*models.WeatherStatistics, error) {
+	query := `
+		SELECT id, station_id, year,
+		       avg_max_temperature_celsius, avg_min_temperature_celsius, total_precipitation_cm, avg_diurnal_range_celsius,
+		       observation_count, valid_max_temp_count, valid_min_temp_count, valid_precipitation_count,
+		       is_reliable, created_at, updated_at
+		FROM ` + r.tables.Statistics + `
+		WHERE station_id = $1
+		ORDER BY year
+	`
+
+	var statistics []*models.WeatherStatistics
+	err := r.db.SelectContext(ctx, "list_station_statistics", &statistics, query, stationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list station statistics: %w", err)
+	}
+
+	return statistics, nil
+}
+
+// GetRanking returns the top stations for a year by a statistics metric, highest first
+// Stations with a NULL value are excluded; ties share a rank (RANK semantics)
+func (r *weatherRepository) GetRanking(ctx context.Context, metric string, year, limit int) ([]*models.StationRanking, error) {
+	column, ok := rankingMetricColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unsupported ranking metric: %s", metric)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT RANK() OVER (ORDER BY %[1]s DESC) AS rank,
+		       station_id, year,
+		       %[1]s AS value,
+		       observation_count
+		FROM `+r.tables.Statistics+`
+		WHERE year = $1 AND %[1]s IS NOT NULL
+		ORDER BY rank, station_id
+		LIMIT $2
+	`, column)
+
+	var ranking []*models.StationRanking
+	err := r.db.SelectContext(ctx, "get_ranking", &ranking, query, year, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s ranking: %w", metric, err)
+	}
+
+	for _, entry := range ranking {
+		entry.Metric = metric
+	}
+
+	return ranking, nil
+}
+
+// GetYearOverYearChange returns a station's yearly statistics metric with the
+// change from the previous year that has statistics, oldest first
+// The first year, and any year where either value is NULL, has a NULL delta
+func (r *weatherRepository) GetYearOverYearChange(ctx context.Context, stationID, metric string) ([]*models.YearOverYearChange, error) {
+	column, ok := yearOverYearMetricColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unsupported year-over-year metric: %s", metric)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT year,
+		       %[1]s AS value,
+		       LAG(year) OVER (ORDER BY year) AS previous_year,
+		       %[1]s - LAG(%[1]s) OVER (ORDER BY year) AS delta
+		FROM `+r.tables.Statistics+`
+		WHERE station_id = $1
+		ORDER BY year
+	`, column)
+
+	var changes []*models.YearOverYearChange
+	err := r.db.SelectContext(ctx, "get_year_over_year_change", &changes, query, stationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s year-over-year change: %w", metric, err)
+	}
+
+	return changes, nil
+}
+
+// PatchStatistics updates only the non-nil fields of patch for a station-year
+// Returns NotFoundError when no statistics exist for the station and year
+func (r *weatherRepository) PatchStatistics(ctx context.Context, stationID string, year int, patch *models.StatisticsPatch) (*models.WeatherStatistics, error) {
+	columns := []struct {
+		name  string
+		value interface{}
+		set   bool
+	}{
+		{"avg_max_temperature_celsius", patch.AvgMaxTemperatureCelsius, patch.AvgMaxTemperatureCelsius != nil},
+		{"avg_min_temperature_celsius", patch.AvgMinTemperatureCelsius, patch.AvgMinTemperatureCelsius != nil},
+		{"total_precipitation_cm", patch.TotalPrecipitationCm, patch.TotalPrecipitationCm != nil},
+		{"observation_count", patch.ObservationCount, patch.ObservationCount != nil},
+		{"valid_max_temp_count", patch.ValidMaxTempCount, patch.ValidMaxTempCount != nil},
+		{"valid_min_temp_count", patch.ValidMinTempCount, patch.ValidMinTempCount != nil},
+		{"valid_precipitation_count", patch.ValidPrecipitationCount, patch.ValidPrecipitationCount != nil},
+	}
+
+	query := "UPDATE " + r.tables.Statistics + " SET updated_at = NOW()"
+	args := []interface{}{}
+	argNum := 1
+
+	for _, column := range columns {
+		if !column.set {
+			continue
+		}
+		query += fmt.Sprintf(", %s = $%d", column.name, argNum)
+		args = append(args, column.value)
+		argNum++
+	}
+
+	query += fmt.Sprintf(` WHERE station_id = $%d AND year = $%d
+		RETURNING id, station_id, year,
+		          avg_max_temperature_celsius, avg_min_temperature_celsius, total_precipitation_cm, avg_diurnal_range_celsius,
+		          observation_count, valid_max_temp_count, valid_min_temp_count, valid_precipitation_count,
+		          is_reliable, created_at, updated_at`, argNum, argNum+1)
+	args = append(args, stationID, year)
+
+	var stats models.WeatherStatistics
+	err := r.db.GetContext(ctx, "patch_statistics", &stats, query, args...)
+
+	if err == sql.ErrNoRows {
+		return nil, &NotFoundError{
+			Resource: "weather_statistics",
+			ID:       fmt.Sprintf("%s:%d", stationID, year),
+		}
+	}
+
+	// Surface CHECK constraint violations (e.g. valid counts above observation_count) as validation errors
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23514" {
+		return nil, &models.ValidationError{
+			Field:   pqErr.Constraint,
+			Message: fmt.Sprintf("statistics patch violates constraint %s", pqErr.Constraint),
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch statistics: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// statisticsYear returns the half-open date range [from, to) of observations
+// counted in a station's statistics for year
+// observation_date is the station's local calendar day, so a year is simply
+// its calendar dates with no timezone conversion: 1 January opens the year and
+// 31 December closes it wherever the station is
+func statisticsYear(year int) (from, to time.Time) {
+	from = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(1, 0, 0)
+}
+
+// CalculateYearlyStatistics calculates statistics for a station and year
+// Years are the calendar years of the observation dates (see statisticsYear)
+func (r *weatherRepository) CalculateYearlyStatistics(ctx context.Context, stationID string, year int) (*models.WeatherStatistics, error) {
+	timer := time.Now()
+	defer func() {
+		duration := time.Since(timer)
+		r.metrics.StatsCalculationDuration.Observe(duration.Seconds())
+		r.logger.Debug(ctx, "[REPO_CALC_STATS] Statistics calculated", logging.Fields{
+			"station_id":  stationID,
+			"year":        year,
+			"duration_ms": duration.Milliseconds(),
+		})
+	}()
+
+	from, to := statisticsYear(year)
 
 	query := `
 		SELECT
@@ -465,23 +1930,25 @@ func (r *weatherRepository) CalculateYearlyStatistics(ctx context.Context, stati
 			COUNT(precipitation_cm) as valid_precipitation_count,
 			AVG(max_temperature_celsius) as avg_max_temperature_celsius,
 			AVG(min_temperature_celsius) as avg_min_temperature_celsius,
-			SUM(precipitation_cm) as total_precipitation_cm
-		FROM weather_observations
+			SUM(precipitation_cm) as total_precipitation_cm,
+			AVG(max_temperature_celsius - min_temperature_celsius) as avg_diurnal_range_celsius
+		FROM ` + r.tables.Observations + `
 		WHERE station_id = $1
-		  AND EXTRACT(YEAR FROM observation_date) = $2
+		  AND observation_date >= $2 AND observation_date < $3
 	`
 
 	var result struct {
-		ObservationCount        int
-		ValidMaxTempCount       int
-		ValidMinTempCount       int
-		ValidPrecipitationCount int
-		AvgMaxTemperatureCelsius  *float64
-		AvgMinTemperatureCelsius  *float64
-		TotalPrecipitationCm      *float64
+		ObservationCount         int      `db:"observation_count"`
+		ValidMaxTempCount        int      `db:"valid_max_temp_count"`
+		ValidMinTempCount        int      `db:"valid_min_temp_count"`
+		ValidPrecipitationCount  int      `db:"valid_precipitation_count"`
+		AvgMaxTemperatureCelsius *float64 `db:"avg_max_temperature_celsius"`
+		AvgMinTemperatureCelsius *float64 `db:"avg_min_temperature_celsius"`
+		TotalPrecipitationCm     *float64 `db:"total_precipitation_cm"`
+		AvgDiurnalRangeCelsius   *float64 `db:"avg_diurnal_range_celsius"`
 	}
 
-	err := r.db.GetContext(ctx, "calculate_statistics", &result, query, stationID, year)
+	err := r.db.GetContext(ctx, "calculate_statistics", &result, query, stationID, from, to)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate statistics: %w", err)
 	}
@@ -496,6 +1963,7 @@ func (r *weatherRepository) CalculateYearlyStatistics(ctx context.Context, stati
 		AvgMaxTemperatureCelsius:  result.AvgMaxTemperatureCelsius,
 		AvgMinTemperatureCelsius:  result.AvgMinTemperatureCelsius,
 		TotalPrecipitationCm:      result.TotalPrecipitationCm,
+		AvgDiurnalRangeCelsius:    result.AvgDiurnalRangeCelsius,
 		CreatedAt:               time.Now().UTC(),
 		UpdatedAt:               time.Now().UTC(),
 	}
@@ -503,11 +1971,297 @@ func (r *weatherRepository) CalculateYearlyStatistics(ctx context.Context, stati
 	return stats, nil
 }
 
+// RecordFailure persists an input line that failed parsing or conversion
+func (r *weatherRepository) RecordFailure(ctx context.Context, stationID string, lineNumber int, raw, reason string) error {
+	query := `
+		INSERT INTO ` + r.tables.FailedRecords + ` (station_id, line_number, raw_line, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, "insert_failed_record", query,
+		stationID,
+		lineNumber,
+		raw,
+		reason,
+		time.Now().UTC(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to record failure: %w", err)
+	}
+
+	return nil
+}
+
+// ListFailedRecords retrieves failed ingestion records with filtering and pagination
+func (r *weatherRepository) ListFailedRecords(ctx context.Context, filter FailedRecordFilter) ([]*models.FailedRecord, int, error) {
+	// Build query with filters
+	query := `
+		SELECT id, station_id, line_number, raw_line, reason, created_at
+		FROM ` + r.tables.FailedRecords + `
+		WHERE 1=1
+	`
+	args := []interface{}{}
+	argNum := 1
+
+	if filter.StationID != nil {
+		query += fmt.Sprintf(" AND station_id = $%d", argNum)
+		args = append(args, *filter.StationID)
+		argNum++
+	}
+
+	// Get total count
+	countQuery := "SELECT COUNT(*) FROM (" + query + ") AS count_query"
+	var totalCount int
+	err := r.db.GetContext(ctx, "count_failed_records", &totalCount, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count failed records: %w", err)
+	}
+
+	// Add ordering and pagination
+	query += " ORDER BY created_at DESC, id DESC"
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argNum, argNum+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	// Execute query
+	var records []*models.FailedRecord
+	err = r.db.SelectContext(ctx, "list_failed_records", &records, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list failed records: %w", err)
+	}
+
+	return records, totalCount, nil
+}
+
+// RecordIngestionRun persists the summary of an ingestion run and sets run.ID
+func (r *weatherRepository) RecordIngestionRun(ctx context.Context, run *models.IngestionRun) error {
+	query := `
+		INSERT INTO ` + r.tables.IngestionRuns + ` (
+			data_dir, started_at, finished_at, files_processed,
+			total_records, successful_records, failed_records, error_count, aborted
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+
+	err := r.db.GetContext(ctx, "insert_ingestion_run", run, query,
+		run.DataDir,
+		run.StartedAt,
+		run.FinishedAt,
+		run.FilesProcessed,
+		run.TotalRecords,
+		run.SuccessfulRecords,
+		run.FailedRecords,
+		run.ErrorCount,
+		run.Aborted,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to record ingestion run: %w", err)
+	}
+
+	return nil
+}
+
+// ListIngestionRuns retrieves ingestion runs, most recent first, with pagination
+func (r *weatherRepository) ListIngestionRuns(ctx context.Context, limit, offset int) ([]*models.IngestionRun, int, error) {
+	var totalCount int
+	err := r.db.GetContext(ctx, "count_ingestion_runs", &totalCount, "SELECT COUNT(*) FROM "+r.tables.IngestionRuns)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count ingestion runs: %w", err)
+	}
+
+	query := `
+		SELECT id, data_dir, started_at, finished_at, files_processed,
+		       total_records, successful_records, failed_records, error_count, aborted, created_at
+		FROM ` + r.tables.IngestionRuns + `
+		ORDER BY started_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	var runs []*models.IngestionRun
+	err = r.db.SelectContext(ctx, "list_ingestion_runs", &runs, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list ingestion runs: %w", err)
+	}
+
+	return runs, totalCount, nil
+}
+
+// RegisterIngestionFiles adds files to the shared ingestion state as pending
+// Files already known keep their state, so every instance can register the
+// directory it sees without resetting progress. With retryFailed, known files
+// marked failed go back to pending and resume after their last committed line.
+func (r *weatherRepository) RegisterIngestionFiles(ctx context.Context, fileNames []string, retryFailed bool) error {
+	onConflict := `DO NOTHING`
+	if retryFailed {
+		onConflict = `DO UPDATE SET status = 'pending', updated_at = NOW()
+		WHERE ` + r.tables.FileStatus + `.status = 'failed'`
+	}
+
+	query := `
+		INSERT INTO ` + r.tables.FileStatus + ` (file_name, status)
+		SELECT file_name, 'pending' FROM unnest($1::text[]) AS t(file_name)
+		ON CONFLICT (file_name) ` + onConflict + `
+	`
+
+	result, err := r.db.ExecContext(ctx, "register_ingestion_files", query, pq.Array(fileNames))
+	if err != nil {
+		return fmt.Errorf("failed to register ingestion files: %w", err)
+	}
+
+	registered, _ := result.RowsAffected()
+	r.logger.Debug(ctx, "[REPO_REGISTER_FILES] Registered ingestion files", logging.Fields{
+		"file_count":   len(fileNames),
+		"registered":   registered,
+		"retry_failed": retryFailed,
+	})
+
+	return nil
+}
+
+// ClaimIngestionFile marks the next pending file among fileNames in_progress
+// for owner and returns it, or nil when none is left
+// Only the files a run registered are claimed, so rows for other directories
+// or files no longer present are never picked up. Files left in_progress without an update for staleAfter are claimed again,
+// so work held by a crashed instance resumes elsewhere. SKIP LOCKED lets
+// concurrent claims pass over each other's rows instead of waiting on them.
+func (r *weatherRepository) ClaimIngestionFile(ctx context.Context, owner string, fileNames []string, staleAfter time.Duration) (*models.IngestionFileStatus, error) {
+	query := `
+		UPDATE ` + r.tables.FileStatus + `
+		SET status = 'in_progress', claimed_by = $1, updated_at = NOW()
+		WHERE file_name = (
+			SELECT file_name
+			FROM ` + r.tables.FileStatus + `
+			WHERE file_name = ANY($3)
+			  AND (status = 'pending'
+			   OR (status = 'in_progress' AND updated_at < NOW() - make_interval(secs => $2)))
+			ORDER BY file_name
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING file_name, status, last_line, claimed_by, updated_at
+	`
+
+	var file models.IngestionFileStatus
+	err := r.db.GetContext(ctx, "claim_ingestion_file", &file, query, owner, staleAfter.Seconds(), pq.Array(fileNames))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim ingestion file: %w", err)
+	}
+
+	return &file, nil
+}
+
+// UpdateIngestionFileProgress records the last committed line of a file
+// claimed by owner, which also refreshes the claim
+// Returns false when owner no longer holds the claim, e.g. after it went
+// stale and another instance took the file over
+func (r *weatherRepository) UpdateIngestionFileProgress(ctx context.Context, fileName, owner string, lastLine int) (bool, error) {
+	query := `
+		UPDATE ` + r.tables.FileStatus + `
+		SET last_line = GREATEST(last_line, $3), updated_at = NOW()
+		WHERE file_name = $1 AND claimed_by = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, "update_ingestion_file_progress", query, fileName, owner, lastLine)
+	if err != nil {
+		return false, fmt.Errorf("failed to update ingestion file progress: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update ingestion file progress: %w", err)
+	}
+
+	return updated > 0, nil
+}
+
+// SetIngestionFileStatus moves a file claimed by owner to its final status, or
+// back to pending so it can be claimed again; last_line is kept either way
+// Returns false when owner no longer holds the claim
+func (r *weatherRepository) SetIngestionFileStatus(ctx context.Context, fileName, owner, status string) (bool, error) {
+	query := `
+		UPDATE ` + r.tables.FileStatus + `
+		SET status = $3, updated_at = NOW()
+		WHERE file_name = $1 AND claimed_by = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, "set_ingestion_file_status", query, fileName, owner, status)
+	if err != nil {
+		return false, fmt.Errorf("failed to set ingestion file status: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set ingestion file status: %w", err)
+	}
+
+	return updated > 0, nil
+}
+
 // HealthCheck performs a repository health check
 func (r *weatherRepository) HealthCheck(ctx context.Context) error {
 	return r.db.HealthCheck(ctx)
 }
 
+// VerifySchema checks that every required table exists in the current schema
+// Returns a SchemaError naming the missing tables
+func (r *weatherRepository) VerifySchema(ctx context.Context) error {
+	query := `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name = ANY($1)
+	`
+
+	requiredTables := r.tables.All()
+
+	var present []string
+	err := r.db.SelectContext(ctx, "verify_schema", &present, query, pq.Array(requiredTables))
+	if err != nil {
+		return fmt.Errorf("failed to inspect schema: %w", err)
+	}
+
+	found := make(map[string]bool, len(present))
+	for _, table := range present {
+		found[table] = true
+	}
+
+	var missing []string
+	for _, table := range requiredTables {
+		if !found[table] {
+			missing = append(missing, table)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &SchemaError{MissingTables: missing}
+	}
+
+	return nil
+}
+
+// ApplyMigrations applies the embedded up migrations not yet recorded in the
+// migrations table, each in its own transaction, with the table prefix applied
+func (r *weatherRepository) ApplyMigrations(ctx context.Context) error {
+	runner := &migrations.Runner{
+		DB:      r.db.DB().DB,
+		Table:   r.tables.Migrations,
+		Rewrite: r.tables.Rewrite,
+		OnApply: func(script migrations.Script) {
+			r.logger.Info(ctx, "[REPO_MIGRATION_APPLIED] Migration applied", logging.Fields{
+				"migration": script.Name,
+			})
+		},
+		BaselineTable: r.tables.Stations,
+	}
+
+	_, err := runner.Up(ctx)
+	return err
+}
+
 // NotFoundError represents a resource not found error
 type NotFoundError struct {
 	Resource string
@@ -521,3 +2275,16 @@ func (e *NotFoundError) Error() string {
 func (e *NotFoundError) IsTransient() bool {
 	return false
 }
+
+// SchemaError reports tables missing from an unmigrated database
+type SchemaError struct {
+	MissingTables []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("database schema incomplete, missing tables: %s", strings.Join(e.MissingTables, ", "))
+}
+
+func (e *SchemaError) IsTransient() bool {
+	return false
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"weather-platform/internal/models"
+)
+
+// TestBuildObservationWhere_MissingField tests the IS NULL clause and that
+// only known metric names reach the query text
+func TestBuildObservationWhere_MissingField(t *testing.T) {
+	stationID := "USC00110072"
+
+	for field, column := range observationMetricColumns {
+		missing := field
+		where, args, argNum := buildObservationWhere(ObservationFilter{StationID: &stationID, MissingField: &missing})
+
+		if !strings.HasSuffix(where, " AND "+column+" IS NULL") {
+			t.Errorf("MissingField %q where = %q, want %s IS NULL", field, where, column)
+		}
+		if len(args) != 1 || argNum != 2 {
+			t.Errorf("MissingField %q added arguments: args = %v, argNum = %d", field, args, argNum)
+		}
+	}
+
+	injected := "precip; DROP TABLE weather_observations"
+	if where, _, _ := buildObservationWhere(ObservationFilter{MissingField: &injected}); where != " WHERE 1=1" {
+		t.Errorf("unknown MissingField where = %q, want no condition", where)
+	}
+}
+
+// TestDegreeDayTotal tests that every metric selects a different total
+func TestDegreeDayTotal(t *testing.T) {
+	degreeDays := &models.DegreeDays{HeatingDegreeDays: 1, CoolingDegreeDays: 2, GrowingDegreeDays: 3}
+
+	for metric, want := range map[string]float64{"hdd": 1, "cdd": 2, "gdd": 3} {
+		if got, err := DegreeDayTotal(degreeDays, metric); err != nil || got != want {
+			t.Errorf("DegreeDayTotal(%s) = (%v, %v), want %v", metric, got, err, want)
+		}
+		if !IsValidDegreeDayMetric(metric) {
+			t.Errorf("IsValidDegreeDayMetric(%s) = false", metric)
+		}
+	}
+
+	if _, err := DegreeDayTotal(degreeDays, "GDD"); err == nil || IsValidDegreeDayMetric("GDD") {
+		t.Error("DegreeDayTotal(GDD) expected error, metric names are case-sensitive")
+	}
+}
+
+// TestBuildStreakQuery tests the predicate each streak condition compares
+// against the bound threshold, and that unknown conditions never reach SQL
+func TestBuildStreakQuery(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      string
+		wantErr   bool
+	}{
+		{condition: "hot", want: "max_temperature_celsius > $2"},
+		{condition: "cold", want: "min_temperature_celsius < $2"},
+		{condition: "wet", want: "precipitation_cm > $2"},
+		{condition: "dry", want: "precipitation_cm <= $2"},
+		{condition: "HOT", wantErr: true},
+		{condition: "", wantErr: true},
+		{condition: "wet OR 1=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			query, err := buildStreakQuery("weather_observations", tt.condition)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildStreakQuery(%q) error = %v, wantErr %v", tt.condition, err, tt.wantErr)
+			}
+			if IsValidStreakCondition(tt.condition) == tt.wantErr {
+				t.Errorf("IsValidStreakCondition(%q) = %v, want %v", tt.condition, !tt.wantErr, !tt.wantErr)
+			}
+			if tt.wantErr {
+				if query != "" {
+					t.Errorf("buildStreakQuery(%q) returned a query with its error", tt.condition)
+				}
+				return
+			}
+
+			if !strings.Contains(query, "WHERE station_id = $1 AND "+tt.want+"\n") {
+				t.Errorf("buildStreakQuery(%q) does not filter on %q:\n%s", tt.condition, tt.want, query)
+			}
+			if !strings.Contains(query, "FROM weather_observations\n") {
+				t.Errorf("buildStreakQuery(%q) does not read the given table:\n%s", tt.condition, query)
+			}
+		})
+	}
+
+	if len(streakConditions) != 4 {
+		t.Errorf("streakConditions has %d conditions, the table above covers 4", len(streakConditions))
+	}
+}
+
+// TestBuildCorrelationQuery tests that only known metrics reach the query and
+// that rows missing either metric are excluded from the sample
+func TestBuildCorrelationQuery(t *testing.T) {
+	stationID := "USC00110072"
+	filter := ObservationFilter{StationID: &stationID}
+
+	tests := []struct {
+		name    string
+		x, y    string
+		want    string
+		wantErr bool
+	}{
+		{name: "temperatures", x: "max_temp", y: "min_temp",
+			want: "corr(max_temperature_celsius, min_temperature_celsius)"},
+		{name: "same metric", x: "precip", y: "precip",
+			want: "corr(precipitation_cm, precipitation_cm)"},
+		{name: "unknown x", x: "humidity", y: "precip", wantErr: true},
+		{name: "unknown y", x: "max_temp", y: "max_temperature_celsius", wantErr: true},
+		{name: "injected y", x: "max_temp", y: "precip) FROM pg_user --", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := buildCorrelationQuery("weather_observations", tt.x, tt.y, filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildCorrelationQuery(%q, %q) error = %v, wantErr %v", tt.x, tt.y, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !strings.Contains(query, tt.want) {
+				t.Errorf("query does not select %s:\n%s", tt.want, query)
+			}
+			columnX, columnY := observationMetricColumns[tt.x], observationMetricColumns[tt.y]
+			if !strings.Contains(query, "AND "+columnX+" IS NOT NULL AND "+columnY+" IS NOT NULL") {
+				t.Errorf("query does not exclude rows missing a metric:\n%s", query)
+			}
+			if len(args) != 1 || args[0] != stationID {
+				t.Errorf("args = %v, want the station only", args)
+			}
+		})
+	}
+}
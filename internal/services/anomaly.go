@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"weather-platform/internal/models"
+	"weather-platform/internal/repository"
+)
+
+// Data required for a degree-day anomaly
+const (
+	// AnomalyMinDays is the fewest days with both temperatures for a year to
+	// be compared or to count towards the normal; partial years would
+	// otherwise read as large negative anomalies
+	AnomalyMinDays = 300
+
+	// AnomalyMinNormalYears is the fewest complete years behind a normal
+	AnomalyMinNormalYears = 10
+)
+
+// GetDegreeDayAnomaly compares a station-year's degree-day total for metric
+// with the station's normal
+// Returns a repository.NotFoundError when the station does not exist. A
+// station or year lacking history is not an error: the result has
+// SufficientData false and a Reason.
+func (s *WeatherService) GetDegreeDayAnomaly(ctx context.Context, stationID, metric string, year int, base float64) (*models.DegreeDayAnomaly, error) {
+	if _, err := s.repo.GetStation(ctx, stationID); err != nil {
+		return nil, err
+	}
+
+	yearly, err := s.repo.GetYearlyDegreeDays(ctx, stationID, base)
+	if err != nil {
+		return nil, err
+	}
+
+	anomaly, err := compareDegreeDays(yearly, stationID, metric, year, base)
+	if err != nil {
+		return nil, err
+	}
+
+	assessAnomaly(anomaly)
+	return anomaly, nil
+}
+
+// compareDegreeDays builds the anomaly of year from a station's yearly
+// degree days: year's total for metric, and the normal, the mean total of
+// the other years with at least AnomalyMinDays days counted
+// Value is nil when year has no days counted and Normal is nil when no
+// other year qualifies; assessAnomaly judges the counts.
+func compareDegreeDays(yearly []*models.DegreeDays, stationID, metric string, year int, base float64) (*models.DegreeDayAnomaly, error) {
+	anomaly := &models.DegreeDayAnomaly{
+		StationID:   stationID,
+		Year:        year,
+		Metric:      metric,
+		BaseCelsius: base,
+	}
+
+	var normalSum float64
+	for _, degreeDays := range yearly {
+		total, err := repository.DegreeDayTotal(degreeDays, metric)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case degreeDays.Year == year:
+			anomaly.Value = &total
+			anomaly.DaysCounted = degreeDays.DaysCounted
+		case degreeDays.DaysCounted >= AnomalyMinDays:
+			normalSum += total
+			anomaly.NormalYears++
+		}
+	}
+
+	if anomaly.NormalYears > 0 {
+		normal := normalSum / float64(anomaly.NormalYears)
+		anomaly.Normal = &normal
+	}
+
+	return anomaly, nil
+}
+
+// assessAnomaly fills the differences when the year and the normal have
+// enough data, and otherwise clears them and explains why
+// The percent difference stays nil for a zero normal, e.g. growing degree
+// days at a station that is never warm enough
+func assessAnomaly(anomaly *models.DegreeDayAnomaly) {
+	anomaly.AbsoluteDifference = nil
+	anomaly.PercentDifference = nil
+
+	switch {
+	case anomaly.Value == nil || anomaly.DaysCounted < AnomalyMinDays:
+		anomaly.SufficientData = false
+		anomaly.Reason = fmt.Sprintf("insufficient data: %d has %d days with both temperatures, at least %d are needed",
+			anomaly.Year, anomaly.DaysCounted, AnomalyMinDays)
+		return
+	case anomaly.Normal == nil || anomaly.NormalYears < AnomalyMinNormalYears:
+		anomaly.SufficientData = false
+		anomaly.Reason = fmt.Sprintf("insufficient data: the normal has %d other years with at least %d days, at least %d are needed",
+			anomaly.NormalYears, AnomalyMinDays, AnomalyMinNormalYears)
+		return
+	}
+
+	anomaly.SufficientData = true
+	anomaly.Reason = ""
+
+	difference := *anomaly.Value - *anomaly.Normal
+	anomaly.AbsoluteDifference = &difference
+	if *anomaly.Normal != 0 {
+		percent := difference / *anomaly.Normal * 100
+		anomaly.PercentDifference = &percent
+	}
+}
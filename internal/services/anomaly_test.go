@@ -0,0 +1,137 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"weather-platform/internal/models"
+)
+
+func TestAssessAnomaly(t *testing.T) {
+	float := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name        string
+		anomaly     models.DegreeDayAnomaly
+		sufficient  bool
+		wantAbs     *float64
+		wantPercent *float64
+		reason      string
+	}{
+		{
+			name:        "warmer than normal",
+			anomaly:     models.DegreeDayAnomaly{Value: float(1650), Normal: float(1500), DaysCounted: 365, NormalYears: 29},
+			sufficient:  true,
+			wantAbs:     float(150),
+			wantPercent: float(10),
+		},
+		{
+			name:       "zero normal has no percent difference",
+			anomaly:    models.DegreeDayAnomaly{Value: float(12), Normal: float(0), DaysCounted: 365, NormalYears: 12},
+			sufficient: true,
+			wantAbs:    float(12),
+		},
+		{
+			name:    "partial year",
+			anomaly: models.DegreeDayAnomaly{Year: 2014, Value: float(400), Normal: float(1500), DaysCounted: 120, NormalYears: 29},
+			reason:  "2014 has 120 days",
+		},
+		{
+			name:    "year without observations",
+			anomaly: models.DegreeDayAnomaly{Year: 1970, Normal: float(1500), NormalYears: 29},
+			reason:  "1970 has 0 days",
+		},
+		{
+			name:    "short history",
+			anomaly: models.DegreeDayAnomaly{Value: float(1650), Normal: float(1500), DaysCounted: 365, NormalYears: 4},
+			reason:  "normal has 4 other years",
+		},
+		{
+			name:    "no other complete year",
+			anomaly: models.DegreeDayAnomaly{Value: float(1650), DaysCounted: 365},
+			reason:  "normal has 0 other years",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anomaly := tt.anomaly
+			assessAnomaly(&anomaly)
+
+			if anomaly.SufficientData != tt.sufficient {
+				t.Fatalf("SufficientData = %v, want %v (reason %q)", anomaly.SufficientData, tt.sufficient, anomaly.Reason)
+			}
+			if !tt.sufficient {
+				if anomaly.AbsoluteDifference != nil || anomaly.PercentDifference != nil {
+					t.Errorf("differences = %v, %v, want nil without sufficient data", anomaly.AbsoluteDifference, anomaly.PercentDifference)
+				}
+				if !strings.Contains(anomaly.Reason, tt.reason) {
+					t.Errorf("Reason = %q, want it to mention %q", anomaly.Reason, tt.reason)
+				}
+				return
+			}
+
+			if anomaly.AbsoluteDifference == nil || *anomaly.AbsoluteDifference != *tt.wantAbs {
+				t.Errorf("AbsoluteDifference = %v, want %v", anomaly.AbsoluteDifference, *tt.wantAbs)
+			}
+			if (anomaly.PercentDifference == nil) != (tt.wantPercent == nil) ||
+				(tt.wantPercent != nil && *anomaly.PercentDifference != *tt.wantPercent) {
+				t.Errorf("PercentDifference = %v, want %v", anomaly.PercentDifference, tt.wantPercent)
+			}
+		})
+	}
+}
+
+// TestCompareDegreeDays tests that each metric compares its own total, and
+// that the normal averages only the other years with enough days
+func TestCompareDegreeDays(t *testing.T) {
+	yearly := []*models.DegreeDays{
+		{Year: 2020, HeatingDegreeDays: 2000, CoolingDegreeDays: 300, GrowingDegreeDays: 1500, DaysCounted: 366},
+		{Year: 2021, HeatingDegreeDays: 2200, CoolingDegreeDays: 100, GrowingDegreeDays: 1300, DaysCounted: 365},
+		{Year: 2022, HeatingDegreeDays: 900, CoolingDegreeDays: 50, GrowingDegreeDays: 200, DaysCounted: 120}, // partial year
+		{Year: 2023, HeatingDegreeDays: 1800, CoolingDegreeDays: 400, GrowingDegreeDays: 1700, DaysCounted: 365},
+	}
+
+	tests := []struct {
+		metric     string
+		wantValue  float64
+		wantNormal float64
+	}{
+		{metric: "hdd", wantValue: 1800, wantNormal: 2100},
+		{metric: "cdd", wantValue: 400, wantNormal: 200},
+		{metric: "gdd", wantValue: 1700, wantNormal: 1400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			anomaly, err := compareDegreeDays(yearly, "USC00110072", tt.metric, 2023, 18)
+			if err != nil {
+				t.Fatalf("compareDegreeDays() error = %v", err)
+			}
+
+			if anomaly.Value == nil || *anomaly.Value != tt.wantValue {
+				t.Errorf("Value = %v, want %v", anomaly.Value, tt.wantValue)
+			}
+			if anomaly.Normal == nil || *anomaly.Normal != tt.wantNormal {
+				t.Errorf("Normal = %v, want %v", anomaly.Normal, tt.wantNormal)
+			}
+			if anomaly.DaysCounted != 365 || anomaly.NormalYears != 2 {
+				t.Errorf("DaysCounted = %d, NormalYears = %d, want 365 and 2", anomaly.DaysCounted, anomaly.NormalYears)
+			}
+		})
+	}
+
+	t.Run("year without data", func(t *testing.T) {
+		anomaly, err := compareDegreeDays(yearly, "USC00110072", "gdd", 1990, 10)
+		if err != nil {
+			t.Fatalf("compareDegreeDays() error = %v", err)
+		}
+		if anomaly.Value != nil || anomaly.DaysCounted != 0 || anomaly.NormalYears != 3 {
+			t.Errorf("anomaly = %+v, want no value and 3 normal years", anomaly)
+		}
+	})
+
+	if _, err := compareDegreeDays(yearly, "USC00110072", "fdd", 2023, 0); err == nil {
+		t.Error("compareDegreeDays() with an unknown metric expected error")
+	}
+}
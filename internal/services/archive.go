@@ -0,0 +1,153 @@
+package services
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"weather-platform/pkg/logging"
+)
+
+// IngestArchive ingests the station files packed in a gzip-compressed tar
+// archive without extracting it
+// Entries are read in archive order, one at a time, since the stream cannot be
+// split between workers. Regular entries whose base name matches the
+// configured file patterns are ingested like files in a data directory: the
+// station ID comes from the base name and the parser from the Format option.
+// A damaged archive fails the run, though rows already written are kept.
+func (s *IngestionService) IngestArchive(ctx context.Context, archivePath string, batchSize int) (*IngestionResult, error) {
+	startTime := time.Now()
+
+	s.logger.Info(ctx, "[INGEST_START] Starting archive ingestion", logging.Fields{
+		"archive":    archivePath,
+		"batch_size": batchSize,
+		"stage":      "INITIALIZATION",
+	})
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip archive %s: %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	patterns := s.options.FilePatterns
+	if len(patterns) == 0 {
+		patterns = DefaultFilePatterns
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid file pattern %q: %w", pattern, err)
+		}
+	}
+
+	result := &IngestionResult{
+		Errors: make([]string, 0),
+	}
+
+	entries := tar.NewReader(gz)
+	for {
+		header, err := entries.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive %s: %w", archivePath, err)
+		}
+
+		name, ok := archiveEntryName(header, patterns)
+		if !ok {
+			continue
+		}
+		result.TotalFiles++
+
+		entryPath := archivePath + ":" + name
+		fileResult, err := s.ingestArchiveEntry(ctx, name, entries, batchSize)
+		s.collectFileResult(ctx, result, entryPath, fileResult, err)
+		if ctx.Err() != nil {
+			break
+		}
+
+		if s.exceedsMaxErrors(result) {
+			result.Aborted = true
+			s.logger.Warn(ctx, "[INGEST_ABORTED] Error threshold exceeded, stopping ingestion", logging.Fields{
+				"error_count": result.ErrorCount(),
+				"max_errors":  s.options.MaxErrors,
+				"stage":       "ERROR_THRESHOLD",
+			})
+			break
+		}
+	}
+
+	if result.TotalFiles == 0 {
+		return nil, fmt.Errorf("no data files matching %s found in %s", strings.Join(patterns, ","), archivePath)
+	}
+
+	result.Duration = time.Since(startTime)
+	s.metrics.IngestionDuration.Observe(result.Duration.Seconds())
+
+	s.logger.Info(ctx, "[INGEST_COMPLETE] Archive ingestion completed", logging.Fields{
+		"archive":              archivePath,
+		"total_files":          result.TotalFiles,
+		"total_records":        result.TotalRecords,
+		"successful_records":   result.SuccessfulRecords,
+		"failed_records":       result.FailedRecords,
+		"out_of_order_records": result.OutOfOrderRecords,
+		"duration_seconds":     result.Duration.Seconds(),
+		"records_per_second":   float64(result.SuccessfulRecords) / result.Duration.Seconds(),
+		"error_count":          len(result.Errors),
+		"aborted":              result.Aborted,
+		"stage":                "COMPLETE",
+	})
+
+	s.recordRun(ctx, archivePath, startTime, result)
+
+	return result, nil
+}
+
+// ingestArchiveEntry ingests one archive entry read from reader
+func (s *IngestionService) ingestArchiveEntry(ctx context.Context, name string, reader io.Reader, batchSize int) (*FileIngestionResult, error) {
+	start := time.Now()
+	defer func() { s.metrics.ObserveProcessingTime("ingest_file", time.Since(start)) }()
+
+	fileName := path.Base(name)
+	stationID := strings.TrimSuffix(fileName, path.Ext(fileName))
+
+	if s.useCSV(fileName) {
+		return s.IngestCSVReader(ctx, stationID, reader, batchSize)
+	}
+	return s.IngestReader(ctx, stationID, reader, batchSize)
+}
+
+// archiveEntryName returns the cleaned name of a regular archive entry whose
+// base name matches one of patterns, and false for entries to skip
+func archiveEntryName(header *tar.Header, patterns []string) (string, bool) {
+	if header.Typeflag != tar.TypeReg {
+		return "", false
+	}
+
+	name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+	base := path.Base(name)
+	// macOS tar adds AppleDouble "._" entries alongside every file
+	if strings.HasPrefix(base, "._") {
+		return "", false
+	}
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, base); matched {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"weather-platform/internal/models"
+	"weather-platform/internal/repository"
+	"weather-platform/pkg/logging"
+)
+
+func TestArchiveEntryName(t *testing.T) {
+	patterns := []string{"*.txt", "*.csv"}
+
+	tests := []struct {
+		name     string
+		header   *tar.Header
+		want     string
+		wantSkip bool
+	}{
+		{"top-level file", &tar.Header{Name: "USC00257715.txt", Typeflag: tar.TypeReg}, "USC00257715.txt", false},
+		{"dot-slash prefix", &tar.Header{Name: "./wx_data/USC00257715.txt", Typeflag: tar.TypeReg}, "wx_data/USC00257715.txt", false},
+		{"csv file", &tar.Header{Name: "stations/USC00110072.csv", Typeflag: tar.TypeReg}, "stations/USC00110072.csv", false},
+		{"unmatched extension", &tar.Header{Name: "wx_data/README.md", Typeflag: tar.TypeReg}, "", true},
+		{"directory", &tar.Header{Name: "wx_data.txt/", Typeflag: tar.TypeDir}, "", true},
+		{"symlink", &tar.Header{Name: "latest.txt", Typeflag: tar.TypeSymlink}, "", true},
+		{"AppleDouble entry", &tar.Header{Name: "wx_data/._USC00257715.txt", Typeflag: tar.TypeReg}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := archiveEntryName(tt.header, patterns)
+			if ok == tt.wantSkip || got != tt.want {
+				t.Errorf("archiveEntryName(%q) = (%q, %v), want (%q, %v)", tt.header.Name, got, ok, tt.want, !tt.wantSkip)
+			}
+		})
+	}
+}
+
+// stubRepository records what ingestion writes, without a database
+// Methods other than those below panic through the nil embedded interface
+type stubRepository struct {
+	repository.WeatherRepository
+
+	// failStation makes every batch of that station fail to insert
+	failStation string
+
+	mu           sync.Mutex
+	stations     []string
+	observations map[string]int
+}
+
+func (r *stubRepository) CreateStation(ctx context.Context, station *models.WeatherStation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stations = append(r.stations, station.StationID)
+	return nil
+}
+
+func (r *stubRepository) CreateObservationsBatch(ctx context.Context, observations []*models.WeatherObservation, conflict repository.ConflictStrategy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.observations == nil {
+		r.observations = make(map[string]int)
+	}
+	for _, obs := range observations {
+		if obs.StationID == r.failStation {
+			return errors.New("insert failed")
+		}
+		r.observations[obs.StationID]++
+	}
+	return nil
+}
+
+func (r *stubRepository) RecordIngestionRun(ctx context.Context, run *models.IngestionRun) error {
+	return nil
+}
+
+// writeTestArchive writes entries to a gzip-compressed tar file in a
+// temporary directory and returns its path
+func writeTestArchive(t *testing.T, entries []*tar.Header, contents map[string]string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, header := range entries {
+		body := contents[header.Name]
+		header.Size = int64(len(body))
+		header.Mode = 0o644
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "wx_data.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newTestIngestionService creates an ingestion service writing to repo
+func newTestIngestionService(repo repository.WeatherRepository, opts IngestionOptions) *IngestionService {
+	logger := logging.NewStructuredLogger("services-test", "test", logging.ErrorLevel)
+	logger.SetOutput(io.Discard)
+
+	s := NewIngestionService(repo, logger, testMetrics)
+	s.SetOptions(opts)
+	return s
+}
+
+// TestIngestArchive tests entry iteration, skipped entries, station IDs taken
+// from entry base names, and parser selection by extension
+func TestIngestArchive(t *testing.T) {
+	path := writeTestArchive(t, []*tar.Header{
+		{Name: "./wx_data/", Typeflag: tar.TypeDir},
+		{Name: "./wx_data/USC00110072.txt", Typeflag: tar.TypeReg},
+		{Name: "./wx_data/._USC00110072.txt", Typeflag: tar.TypeReg},
+		{Name: "./wx_data/README.md", Typeflag: tar.TypeReg},
+		{Name: "./stations/USC00257715.csv", Typeflag: tar.TypeReg},
+	}, map[string]string{
+		"./wx_data/USC00110072.txt":   "19850101\t-22\t-128\t94\n19850102\t10\t-50\t0\n",
+		"./wx_data/._USC00110072.txt": "not weather data\n",
+		"./wx_data/README.md":         "# Weather data\n",
+		"./stations/USC00257715.csv":  "19850101,-22,-128,94\n",
+	})
+
+	repo := &stubRepository{}
+	s := newTestIngestionService(repo, IngestionOptions{})
+
+	result, err := s.IngestArchive(context.Background(), path, 10)
+	if err != nil {
+		t.Fatalf("IngestArchive() error = %v", err)
+	}
+
+	if result.TotalFiles != 2 || result.SuccessfulRecords != 3 || result.FailedRecords != 0 || len(result.Errors) != 0 {
+		t.Errorf("result files=%d successful=%d failed=%d errors=%v, want 2, 3, 0 and none",
+			result.TotalFiles, result.SuccessfulRecords, result.FailedRecords, result.Errors)
+	}
+	if want := []string{"USC00110072", "USC00257715"}; !slices.Equal(repo.stations, want) {
+		t.Errorf("stations created = %v, want %v", repo.stations, want)
+	}
+	if repo.observations["USC00110072"] != 2 || repo.observations["USC00257715"] != 1 {
+		t.Errorf("observations written = %v, want 2 for USC00110072 and 1 for USC00257715", repo.observations)
+	}
+}
+
+// TestIngestArchive_EntryErrorWithBatchTimeout tests that an entry failing
+// mid-read with a timed reader stops reading it before the next entry is
+// opened; run with -race to catch concurrent use of the archive stream
+func TestIngestArchive_EntryErrorWithBatchTimeout(t *testing.T) {
+	// The first row fails to insert while long lines after it still need
+	// reads from the archive stream
+	failing := "19850101\t10\t-50\t0\n" + strings.Repeat(strings.Repeat("x", 5000)+"\n", 20)
+
+	path := writeTestArchive(t, []*tar.Header{
+		{Name: "USC00110072.txt", Typeflag: tar.TypeReg},
+		{Name: "USC00257715.txt", Typeflag: tar.TypeReg},
+	}, map[string]string{
+		"USC00110072.txt": failing,
+		"USC00257715.txt": "19850101\t-22\t-128\t94\n19850102\t10\t-50\t0\n",
+	})
+
+	repo := &stubRepository{failStation: "USC00110072"}
+	s := newTestIngestionService(repo, IngestionOptions{BatchTimeout: time.Hour})
+
+	result, err := s.IngestArchive(context.Background(), path, 1)
+	if err != nil {
+		t.Fatalf("IngestArchive() error = %v", err)
+	}
+
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "USC00110072.txt") {
+		t.Errorf("errors = %v, want one for USC00110072.txt", result.Errors)
+	}
+	if got := repo.observations["USC00257715"]; got != 2 {
+		t.Errorf("USC00257715 observations written = %d, want 2", got)
+	}
+}
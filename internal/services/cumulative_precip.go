@@ -0,0 +1,36 @@
+package services
+
+import "weather-platform/internal/models"
+
+// cumulativePrecipitation converts a station's running precipitation totals
+// into daily amounts. Rows must be in date order.
+//
+// The first reading only establishes the baseline and is stored as missing.
+// A missing reading stays missing and leaves the baseline unchanged, so the
+// next valid reading carries everything accumulated across the gap. A total
+// lower than the previous one is a gauge reset: accumulation restarted from
+// zero, so the new total itself is the day's amount.
+type cumulativePrecipitation struct {
+	previous int
+	hasValue bool
+}
+
+// toDaily returns the daily amount for a cumulative reading and whether the
+// reading was a reset
+func (c *cumulativePrecipitation) toDaily(total int) (daily int, reset bool) {
+	if total == models.MissingValue {
+		return models.MissingValue, false
+	}
+
+	previous, hasValue := c.previous, c.hasValue
+	c.previous, c.hasValue = total, true
+
+	switch {
+	case !hasValue:
+		return models.MissingValue, false
+	case total < previous:
+		return total, true
+	default:
+		return total - previous, false
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+
+	"weather-platform/internal/models"
+)
+
+func TestCumulativePrecipitationToDaily(t *testing.T) {
+	const missing = models.MissingValue
+
+	tests := []struct {
+		name      string
+		totals    []int
+		wantDaily []int
+		wantReset []bool
+	}{
+		{
+			name:      "baseline row is missing",
+			totals:    []int{120},
+			wantDaily: []int{missing},
+			wantReset: []bool{false},
+		},
+		{
+			name:      "increments",
+			totals:    []int{100, 100, 125, 140},
+			wantDaily: []int{missing, 0, 25, 15},
+			wantReset: []bool{false, false, false, false},
+		},
+		{
+			name:      "reset to zero",
+			totals:    []int{300, 320, 0, 15},
+			wantDaily: []int{missing, 20, 0, 15},
+			wantReset: []bool{false, false, true, false},
+		},
+		{
+			name:      "reset to a partial total",
+			totals:    []int{300, 12},
+			wantDaily: []int{missing, 12},
+			wantReset: []bool{false, true},
+		},
+		{
+			name:      "gap carried across",
+			totals:    []int{50, missing, missing, 80},
+			wantDaily: []int{missing, missing, missing, 30},
+			wantReset: []bool{false, false, false, false},
+		},
+		{
+			name:      "missing before the baseline",
+			totals:    []int{missing, 40, 45},
+			wantDaily: []int{missing, missing, 5},
+			wantReset: []bool{false, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var totals cumulativePrecipitation
+			for i, total := range tt.totals {
+				daily, reset := totals.toDaily(total)
+				if daily != tt.wantDaily[i] || reset != tt.wantReset[i] {
+					t.Errorf("row %d toDaily(%d) = (%d, %v), want (%d, %v)", i, total, daily, reset, tt.wantDaily[i], tt.wantReset[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Input encodings for IngestionOptions.Encoding
+const (
+	EncodingUTF8   = "utf-8"
+	EncodingLatin1 = "latin1"
+)
+
+// ParseEncoding validates an input encoding name (empty means EncodingUTF8)
+func ParseEncoding(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "", "utf-8", "utf8":
+		return EncodingUTF8, nil
+	case "latin1", "latin-1", "iso-8859-1":
+		return EncodingLatin1, nil
+	default:
+		return "", fmt.Errorf("unknown encoding %q: expected utf-8 or latin1", value)
+	}
+}
+
+// utf8BOM is the byte order mark some editors write at the start of UTF-8 files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// decodeInput returns reader's content as UTF-8 with any leading byte order
+// mark removed, so it cannot end up in the first record's date field
+func decodeInput(reader io.Reader, encoding string) io.Reader {
+	buffered := bufio.NewReader(reader)
+	if prefix, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+
+	if encoding == EncodingLatin1 {
+		return &latin1Reader{src: buffered}
+	}
+	return buffered
+}
+
+// latin1Reader decodes ISO-8859-1 to UTF-8: every byte is the code point of the same value
+type latin1Reader struct {
+	src     io.Reader
+	raw     [4096]byte
+	decoded []byte
+	pending []byte
+	err     error
+}
+
+// Read implements io.Reader
+func (r *latin1Reader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		n, err := r.src.Read(r.raw[:])
+		r.err = err
+		r.decoded = r.decoded[:0]
+		for _, b := range r.raw[:n] {
+			r.decoded = utf8.AppendRune(r.decoded, rune(b))
+		}
+		r.pending = r.decoded
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecodeInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		encoding string
+		want     string
+	}{
+		{"utf-8 without BOM", "19850101\t-22\t-128\t94\n", EncodingUTF8, "19850101\t-22\t-128\t94\n"},
+		{"utf-8 BOM stripped", "\xEF\xBB\xBF19850101\t-22\t-128\t94\n", EncodingUTF8, "19850101\t-22\t-128\t94\n"},
+		{"BOM only", "\xEF\xBB\xBF", EncodingUTF8, ""},
+		{"latin1 decoded", "Montr\xE9al,19850101\n", EncodingLatin1, "Montréal,19850101\n"},
+		{"latin1 BOM stripped", "\xEF\xBB\xBF19850101\xB0\n", EncodingLatin1, "19850101°\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// One byte at a time exercises partial reads through the decoder
+			decoded, err := io.ReadAll(decodeInput(iotest.OneByteReader(strings.NewReader(tt.input)), tt.encoding))
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if string(decoded) != tt.want {
+				t.Errorf("decoded = %q, want %q", decoded, tt.want)
+			}
+		})
+	}
+}
+
+// TestDecodeInput_FirstLineParses tests that a BOM no longer corrupts the first record
+func TestDecodeInput_FirstLineParses(t *testing.T) {
+	input := "\xEF\xBB\xBF19850101\t-22\t-128\t94\n19850102\t10\t-50\t0\n"
+
+	var lines []string
+	err := ScanLines(decodeInput(strings.NewReader(input), EncodingUTF8), func(line int, text string) bool {
+		lines = append(lines, text)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("ScanLines() error = %v", err)
+	}
+
+	record, err := ParseLine(lines[0])
+	if err != nil {
+		t.Fatalf("ParseLine(first line) error = %v", err)
+	}
+	if record.Date != "19850101" {
+		t.Errorf("first record date = %q, want 19850101", record.Date)
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	for value, want := range map[string]string{"": EncodingUTF8, "UTF8": EncodingUTF8, "latin1": EncodingLatin1, "ISO-8859-1": EncodingLatin1} {
+		if got, err := ParseEncoding(value); err != nil || got != want {
+			t.Errorf("ParseEncoding(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+
+	if _, err := ParseEncoding("utf-16"); err == nil {
+		t.Error("ParseEncoding(utf-16) expected error")
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"weather-platform/internal/models"
+	"weather-platform/pkg/logging"
+)
+
+// DefaultClaimTimeout is how long a coordinated file may go without progress
+// before another instance may claim it, when IngestionOptions.ClaimTimeout is unset
+const DefaultClaimTimeout = 10 * time.Minute
+
+// fileClaim is a file this instance claimed from the shared ingestion state
+type fileClaim struct {
+	name        string // path relative to the data directory, the table key
+	owner       string // claimed_by of this instance
+	resumeAfter int    // last line committed by an earlier attempt
+	saved       int    // last line recorded by this attempt
+}
+
+// fileTask is a file handed to an ingestion worker
+type fileTask struct {
+	path  string
+	claim *fileClaim // nil when runs are not coordinated
+}
+
+// coordinatorID names this instance in claimed_by
+func (s *IngestionService) coordinatorID() string {
+	if s.options.CoordinatorID != "" {
+		return s.options.CoordinatorID
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// registerFiles adds the matched files to the shared ingestion state and
+// returns their names there, the only files the run may claim
+func (s *IngestionService) registerFiles(ctx context.Context, dataDir string, files []string) ([]string, error) {
+	names, err := relativeFileNames(dataDir, files)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RegisterIngestionFiles(ctx, names, s.options.RetryFailed); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// relativeFileNames returns files as slash-separated paths relative to
+// dataDir, so instances mounting the directory at different paths agree on
+// the keys of the shared ingestion state
+func relativeFileNames(dataDir string, files []string) ([]string, error) {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		name, err := filepath.Rel(dataDir, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s within %s: %w", file, dataDir, err)
+		}
+		names = append(names, filepath.ToSlash(name))
+	}
+	return names, nil
+}
+
+// dispatchClaims claims files among names, as returned by registerFiles, from
+// the shared ingestion state one at a time and sends them to tasks until none
+// are left or ctx is cancelled
+// Returns the number of files claimed
+func (s *IngestionService) dispatchClaims(ctx context.Context, dataDir string, names []string, tasks chan<- fileTask) (int, error) {
+	owner := s.coordinatorID()
+	staleAfter := s.options.ClaimTimeout
+	if staleAfter <= 0 {
+		staleAfter = DefaultClaimTimeout
+	}
+
+	claimed := 0
+	for ctx.Err() == nil {
+		status, err := s.repo.ClaimIngestionFile(ctx, owner, names, staleAfter)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				break
+			}
+			return claimed, err
+		}
+		if status == nil {
+			break
+		}
+		claimed++
+
+		if status.LastLine > 0 {
+			s.logger.Info(ctx, "[INGEST_FILE_RESUME] Resuming partially ingested file", logging.Fields{
+				"file_name": status.FileName,
+				"last_line": status.LastLine,
+				"owner":     owner,
+				"stage":     "FILE_COORDINATION",
+			})
+		}
+
+		task := fileTask{
+			path:  filepath.Join(dataDir, filepath.FromSlash(status.FileName)),
+			claim: &fileClaim{name: status.FileName, owner: owner, resumeAfter: status.LastLine, saved: status.LastLine},
+		}
+
+		select {
+		case tasks <- task:
+		case <-ctx.Done():
+			// Never handed to a worker, so return it for another claim
+			s.finishClaim(ctx, task.claim, ctx.Err())
+		}
+	}
+
+	return claimed, nil
+}
+
+// saveClaimProgress records lastLine as the claim's committed progress
+// Failures are logged but never abort ingestion: a resumed file then repeats
+// some rows, which re-import conflict handling absorbs
+func (s *IngestionService) saveClaimProgress(ctx context.Context, claim *fileClaim, lastLine int) {
+	if claim == nil || lastLine <= claim.saved {
+		return
+	}
+
+	held, err := s.repo.UpdateIngestionFileProgress(ctx, claim.name, claim.owner, lastLine)
+	if err != nil {
+		s.logger.Error(ctx, "[INGEST_PROGRESS_PERSIST_ERROR] Failed to record file progress", logging.Fields{
+			"file_name": claim.name,
+			"last_line": lastLine,
+			"stage":     "FILE_COORDINATION",
+		}, err)
+		return
+	}
+	if !held {
+		s.logClaimLost(ctx, claim)
+		return
+	}
+	claim.saved = lastLine
+}
+
+// finishClaim records the outcome of a claimed file (see claimOutcome)
+func (s *IngestionService) finishClaim(ctx context.Context, claim *fileClaim, err error) {
+	status := claimOutcome(err)
+
+	// Interrupted runs must still release their claims
+	held, err := s.repo.SetIngestionFileStatus(context.WithoutCancel(ctx), claim.name, claim.owner, status)
+	if err != nil {
+		s.logger.Error(ctx, "[INGEST_CLAIM_PERSIST_ERROR] Failed to record file status", logging.Fields{
+			"file_name": claim.name,
+			"status":    status,
+			"stage":     "FILE_COORDINATION",
+		}, err)
+		return
+	}
+	if !held {
+		s.logClaimLost(ctx, claim)
+	}
+}
+
+// logClaimLost warns that another instance took over a claim after it went
+// stale; its state is left to the new owner
+func (s *IngestionService) logClaimLost(ctx context.Context, claim *fileClaim) {
+	s.logger.Warn(ctx, "[INGEST_CLAIM_LOST] File was reclaimed by another instance, leaving its state to the new owner", logging.Fields{
+		"file_name": claim.name,
+		"owner":     claim.owner,
+		"stage":     "FILE_COORDINATION",
+	})
+}
+
+// claimOutcome maps a file's ingestion error to its final status: done on
+// success, pending when cancelled so another run resumes it, and failed
+// otherwise so a file that cannot be ingested is not claimed over and over
+func claimOutcome(err error) string {
+	switch {
+	case err == nil:
+		return models.FileStatusDone
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return models.FileStatusPending
+	default:
+		return models.FileStatusFailed
+	}
+}
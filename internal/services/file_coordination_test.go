@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"weather-platform/internal/models"
+	"weather-platform/internal/repository"
+)
+
+func TestRelativeFileNames(t *testing.T) {
+	dataDir := filepath.Join("mnt", "wx_data")
+	files := []string{
+		filepath.Join(dataDir, "USC00110072.txt"),
+		filepath.Join(dataDir, "2023", "USC00257715.csv"),
+	}
+
+	got, err := relativeFileNames(dataDir, files)
+	if err != nil {
+		t.Fatalf("relativeFileNames() error = %v", err)
+	}
+	if want := []string{"USC00110072.txt", "2023/USC00257715.csv"}; !slices.Equal(got, want) {
+		t.Errorf("relativeFileNames() = %v, want %v", got, want)
+	}
+
+	if _, err := relativeFileNames("/mnt/wx_data", []string{"relative.txt"}); err == nil {
+		t.Error("relativeFileNames() with a path outside the data directory expected error")
+	}
+}
+
+func TestClaimOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"success", nil, models.FileStatusDone},
+		{"cancelled", fmt.Errorf("failed to insert batch: %w", context.Canceled), models.FileStatusPending},
+		{"deadline", context.DeadlineExceeded, models.FileStatusPending},
+		{"failure", errors.New("failed to open file"), models.FileStatusFailed},
+	}
+
+	for _, tt := range tests {
+		if got := claimOutcome(tt.err); got != tt.want {
+			t.Errorf("claimOutcome(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCoordinatorID(t *testing.T) {
+	s := &IngestionService{options: IngestionOptions{CoordinatorID: "ingester-a"}}
+	if got := s.coordinatorID(); got != "ingester-a" {
+		t.Errorf("coordinatorID() = %q, want ingester-a", got)
+	}
+
+	s.options.CoordinatorID = ""
+	if got := s.coordinatorID(); got == "" {
+		t.Error("coordinatorID() without CoordinatorID is empty")
+	}
+}
+
+// claimRepository keeps the shared ingestion state in memory, applying the
+// same claim rules as the SQL
+type claimRepository struct {
+	repository.WeatherRepository
+
+	status map[string]string
+}
+
+func (r *claimRepository) RegisterIngestionFiles(ctx context.Context, fileNames []string, retryFailed bool) error {
+	for _, name := range fileNames {
+		status, known := r.status[name]
+		if !known || retryFailed && status == models.FileStatusFailed {
+			r.status[name] = models.FileStatusPending
+		}
+	}
+	return nil
+}
+
+func (r *claimRepository) ClaimIngestionFile(ctx context.Context, owner string, fileNames []string, staleAfter time.Duration) (*models.IngestionFileStatus, error) {
+	names := make([]string, 0, len(r.status))
+	for name := range r.status {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if r.status[name] == models.FileStatusPending && slices.Contains(fileNames, name) {
+			r.status[name] = models.FileStatusInProgress
+			return &models.IngestionFileStatus{FileName: name, Status: models.FileStatusInProgress, ClaimedBy: &owner}, nil
+		}
+	}
+	return nil, nil
+}
+
+// TestDispatchClaims_RegisteredFilesOnly tests that a run claims only the
+// files it registered, and failed files only when retrying them
+func TestDispatchClaims_RegisteredFilesOnly(t *testing.T) {
+	dataDir := filepath.Join("mnt", "wx_data")
+	files := []string{filepath.Join(dataDir, "USC00110072.txt"), filepath.Join(dataDir, "USC00257715.txt")}
+
+	tests := []struct {
+		name        string
+		retryFailed bool
+		want        []string
+	}{
+		{"failed files skipped", false, []string{"USC00110072.txt"}},
+		{"failed files retried", true, []string{"USC00110072.txt", "USC00257715.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &claimRepository{status: map[string]string{
+				"USC00257715.txt":       models.FileStatusFailed,
+				"other/USC00113335.txt": models.FileStatusPending, // another directory's run
+			}}
+			s := newTestIngestionService(repo, IngestionOptions{Coordinate: true, RetryFailed: tt.retryFailed})
+
+			names, err := s.registerFiles(context.Background(), dataDir, files)
+			if err != nil {
+				t.Fatalf("registerFiles() error = %v", err)
+			}
+
+			tasks := make(chan fileTask, len(repo.status))
+			claimed, err := s.dispatchClaims(context.Background(), dataDir, names, tasks)
+			if err != nil {
+				t.Fatalf("dispatchClaims() error = %v", err)
+			}
+			close(tasks)
+
+			var got []string
+			for task := range tasks {
+				got = append(got, task.claim.name)
+			}
+			if claimed != len(tt.want) || !slices.Equal(got, tt.want) {
+				t.Errorf("claimed %d files %v, want %v", claimed, got, tt.want)
+			}
+			if status := repo.status["other/USC00113335.txt"]; status != models.FileStatusPending {
+				t.Errorf("unregistered file status = %q, want it left pending", status)
+			}
+		})
+	}
+}
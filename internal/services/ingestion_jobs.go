@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"weather-platform/pkg/logging"
+)
+
+// Ingestion job statuses
+const (
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// maxTrackedIngestionJobs bounds the finished jobs kept for status lookups
+const maxTrackedIngestionJobs = 100
+
+// ErrIngestionJobRunning is returned when a directory ingestion job is already active
+var ErrIngestionJobRunning = errors.New("an ingestion job is already running")
+
+// IngestionJob tracks an asynchronous IngestDirectory run started through the API
+// Jobs live in memory only; a restart forgets them (the run log still records finished runs)
+type IngestionJob struct {
+	ID         string           `json:"id"`
+	DataDir    string           `json:"data_dir"`
+	BatchSize  int              `json:"batch_size"`
+	Status     string           `json:"status"`
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+	Result     *IngestionResult `json:"result,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// ResolveDataDir returns the directory requested relative to root, rejecting
+// paths that escape root through ".." or symbolic links
+// requested may be relative to root or an absolute path inside it
+func ResolveDataDir(root, requested string) (string, error) {
+	if root == "" {
+		return "", errors.New("no ingestion root configured")
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ingestion root: %w", err)
+	}
+	realRoot, err = filepath.Abs(realRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ingestion root: %w", err)
+	}
+
+	path := requested
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	// Resolve symlinks before the containment check so a link inside root
+	// cannot point the run at a directory outside it
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("data_dir %q does not exist", requested)
+	}
+	realPath, err = filepath.Abs(realPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve data_dir %q: %w", requested, err)
+	}
+
+	rel, err := filepath.Rel(realRoot, realPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("data_dir %q is outside the ingestion root", requested)
+	}
+
+	info, err := os.Stat(realPath)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("data_dir %q is not a directory", requested)
+	}
+
+	return realPath, nil
+}
+
+// StartDirectoryIngestion runs IngestDirectory in the background and returns
+// the tracking job immediately. Only one job runs at a time.
+// The run outlives the request: ctx supplies request-scoped values for logging only.
+func (s *IngestionService) StartDirectoryIngestion(ctx context.Context, dataDir string, batchSize int) (*IngestionJob, error) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.Status == JobStatusRunning {
+			return nil, ErrIngestionJobRunning
+		}
+	}
+
+	job := &IngestionJob{
+		ID:        newJobID(),
+		DataDir:   dataDir,
+		BatchSize: batchSize,
+		Status:    JobStatusRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	s.trackJob(job)
+
+	s.logger.Info(ctx, "[INGEST_JOB_START] Directory ingestion job started", logging.Fields{
+		"job_id":     job.ID,
+		"data_dir":   dataDir,
+		"batch_size": batchSize,
+	})
+
+	snapshot := *job
+	go s.runJob(context.WithoutCancel(ctx), job.ID, dataDir, batchSize)
+
+	return &snapshot, nil
+}
+
+// GetIngestionJob returns a snapshot of a tracked job
+func (s *IngestionService) GetIngestionJob(id string) (*IngestionJob, bool) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *job
+	return &snapshot, true
+}
+
+// runJob performs the ingestion and records its outcome on the job
+func (s *IngestionService) runJob(ctx context.Context, id, dataDir string, batchSize int) {
+	result, err := s.IngestDirectory(ctx, dataDir, batchSize)
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	job := s.jobs[id]
+	finished := time.Now().UTC()
+	job.FinishedAt = &finished
+	job.Result = result
+	job.Status = JobStatusCompleted
+	if err != nil {
+		job.Status = JobStatusFailed
+		job.Error = err.Error()
+		s.logger.Error(ctx, "[INGEST_JOB_ERROR] Directory ingestion job failed", logging.Fields{
+			"job_id":   id,
+			"data_dir": dataDir,
+		}, err)
+		return
+	}
+
+	s.logger.Info(ctx, "[INGEST_JOB_COMPLETE] Directory ingestion job completed", logging.Fields{
+		"job_id":             id,
+		"successful_records": result.SuccessfulRecords,
+		"failed_records":     result.FailedRecords,
+	})
+}
+
+// trackJob stores job, evicting the oldest finished jobs beyond maxTrackedIngestionJobs
+// Callers must hold jobsMu
+func (s *IngestionService) trackJob(job *IngestionJob) {
+	if s.jobs == nil {
+		s.jobs = make(map[string]*IngestionJob)
+	}
+	s.jobs[job.ID] = job
+	s.jobOrder = append(s.jobOrder, job.ID)
+
+	for len(s.jobOrder) > maxTrackedIngestionJobs {
+		oldest := s.jobOrder[0]
+		if s.jobs[oldest].Status == JobStatusRunning {
+			break
+		}
+		delete(s.jobs, oldest)
+		s.jobOrder = s.jobOrder[1:]
+	}
+}
+
+// newJobID returns 8 random bytes hex-encoded
+func newJobID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveDataDir(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(root, "2024", "march"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		requested string
+		want      string
+		wantErr   bool
+	}{
+		{"relative", "2024/march", filepath.Join("2024", "march"), false},
+		{"root itself", ".", "", false},
+		{"absolute inside root", filepath.Join(root, "2024"), "2024", false},
+		{"dot-dot escape", "../" + filepath.Base(outside), "", true},
+		{"dot-dot inside then out", "2024/../../etc", "", true},
+		{"absolute outside root", outside, "", true},
+		{"symlink escape", "escape", "", true},
+		{"missing", "2023", "", true},
+		{"file", "notes.txt", "", true},
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveDataDir(root, tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveDataDir(%q) error = %v, wantErr %v", tt.requested, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if want := filepath.Join(realRoot, tt.want); got != want {
+				t.Errorf("ResolveDataDir(%q) = %q, want %q", tt.requested, got, want)
+			}
+		})
+	}
+
+	if _, err := ResolveDataDir("", "2024"); err == nil {
+		t.Error("ResolveDataDir with no root expected error")
+	}
+}
+
+// TestMatchFilesSkipsSymlinks tests that a file link inside a data directory
+// cannot expose a file outside it
+func TestMatchFilesSkipsSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("not weather data\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "USC00110072.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(root, "USC00257715.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "dir.txt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := matchFiles(root, []string{"*.txt"})
+	if err != nil {
+		t.Fatalf("matchFiles() error = %v", err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != "USC00110072.txt" {
+		t.Errorf("matchFiles() = %v, want only USC00110072.txt", files)
+	}
+}
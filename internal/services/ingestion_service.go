@@ -3,11 +3,15 @@ package services
 import (
 	"bufio"
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"weather-platform/internal/models"
@@ -21,28 +25,186 @@ type IngestionService struct {
 	repo    repository.WeatherRepository
 	logger  *logging.StructuredLogger
 	metrics *metrics.Collector
+	options IngestionOptions
+
+	// batchSlots is a semaphore bounding concurrent batch writes
+	batchSlots chan struct{}
+
+	// jobs tracks directory ingestions started through the API, oldest first in jobOrder
+	jobsMu   sync.Mutex
+	jobs     map[string]*IngestionJob
+	jobOrder []string
+}
+
+// IngestionOptions controls optional ingestion behavior
+type IngestionOptions struct {
+	// PersistFailures stores parse/conversion failures in the failed_records table
+	PersistFailures bool
+
+	// BatchTimeout flushes a non-empty partial batch after this interval (0 disables)
+	BatchTimeout time.Duration
+
+	// Workers is the number of files ingested concurrently (values below 1 mean 1)
+	Workers int
+
+	// MaxInFlightBatches bounds the batches being written to the database at
+	// once across all workers and split file ranges (0 defaults to half of
+	// Workers, at least 1). Writers past the bound wait for a slot, which
+	// stops their readers too. Peak buffered records are bounded by roughly
+	// Workers * batchSize regardless of directory size.
+	MaxInFlightBatches int
+
+	// CSVDelimiter separates fields in .csv files (0 defaults to ',')
+	CSVDelimiter rune
+
+	// CSVSkipHeader discards the first row of each .csv file
+	CSVSkipHeader bool
+
+	// Conflict controls re-imports of existing station/date rows (empty means update)
+	Conflict repository.ConflictStrategy
+
+	// FilePatterns are glob patterns selecting files in the data directory
+	// (empty defaults to DefaultFilePatterns)
+	FilePatterns []string
+
+	// Format selects the parser for every file; FormatAuto picks by extension
+	Format string
+
+	// CheckOrdering warns about and counts rows dated earlier than the row before
+	// them, a sign of concatenated files. Such rows are still ingested.
+	CheckOrdering bool
+
+	// FromLine and ToLine restrict each file to an inclusive window of 1-based
+	// line numbers (0 leaves that side open). Rows outside the window are not
+	// counted, and reading stops once ToLine is passed.
+	FromLine int
+	ToLine   int
+
+	// CumulativePrecip treats the precipitation column as a running total and
+	// stores the difference from the previous row (see cumulativePrecipitation)
+	CumulativePrecip bool
+
+	// Conversion sets the unit scales for raw values (zero values use the
+	// tenths-of-a-degree and tenths-of-a-millimeter defaults)
+	Conversion models.ConversionOptions
+
+	// MaxErrors aborts a directory run once file errors plus failed records
+	// exceed it (0 means unlimited). Checked as each file completes.
+	MaxErrors int
+
+	// Encoding is the character encoding of input (empty means EncodingUTF8)
+	// A leading UTF-8 byte order mark is always removed
+	Encoding string
+
+	// StationAliases maps alternate station IDs from file names or -station-id
+	// to the canonical ID records are stored under (nil disables)
+	StationAliases StationAliases
+
+	// IntraFileParallelism splits each tab-delimited file of at least
+	// IntraFileMinBytes into this many newline-aligned byte ranges parsed
+	// concurrently (values below 2 disable). Ignored with CumulativePrecip or
+	// CheckOrdering, which need rows in file order.
+	IntraFileParallelism int
+
+	// IntraFileMinBytes is the smallest file split by IntraFileParallelism
+	// (0 defaults to DefaultIntraFileMinBytes)
+	IntraFileMinBytes int64
+
+	// Coordinate claims files through the shared ingestion_file_status table
+	// instead of ingesting every matched file, so several ingesters can split
+	// one directory. Progress is saved after each batch and a reclaimed file
+	// resumes after its last committed line. Coordinated files are always read
+	// sequentially, ignoring IntraFileParallelism.
+	Coordinate bool
+
+	// CoordinatorID names this instance in claimed_by (empty uses host name and PID)
+	CoordinatorID string
+
+	// ClaimTimeout is how long a claimed file may go without progress before
+	// another instance may claim it (0 defaults to DefaultClaimTimeout)
+	ClaimTimeout time.Duration
+
+	// RetryFailed returns the run's files marked failed to pending when they
+	// are registered, so a coordinated run claims them again
+	RetryFailed bool
+}
+
+// Input formats for IngestionOptions.Format
+const (
+	FormatAuto = "auto" // .csv files use the CSV parser, everything else tab-delimited
+	FormatTab  = "tab"
+	FormatCSV  = "csv"
+)
+
+// DefaultFilePatterns are the files ingested when no patterns are configured
+var DefaultFilePatterns = []string{"*.txt", "*.csv"}
+
+// ParseFormat validates an input format name (empty means FormatAuto)
+func ParseFormat(value string) (string, error) {
+	switch value {
+	case "", FormatAuto:
+		return FormatAuto, nil
+	case FormatTab, FormatCSV:
+		return value, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: expected auto, tab, or csv", value)
+	}
 }
 
 // IngestionResult contains ingestion statistics
 type IngestionResult struct {
-	TotalFiles       int
-	TotalRecords     int
-	SuccessfulRecords int
-	FailedRecords    int
-	StationsCreated  int
-	Duration         time.Duration
-	Errors           []string
+	TotalFiles       int           `json:"total_files"`
+	TotalRecords     int           `json:"total_records"`
+	SuccessfulRecords int          `json:"successful_records"`
+	FailedRecords    int           `json:"failed_records"`
+	OutOfOrderRecords int          `json:"out_of_order_records"`
+	StationsCreated  int           `json:"stations_created"`
+	Duration         time.Duration `json:"duration_ns"`
+	Errors           []string      `json:"errors"`
+
+	// Aborted is set when the run stopped early after exceeding MaxErrors
+	Aborted bool `json:"aborted"`
+}
+
+// ErrorCount returns the aggregate error count compared against MaxErrors
+func (r *IngestionResult) ErrorCount() int {
+	return len(r.Errors) + r.FailedRecords
 }
 
 // NewIngestionService creates a new ingestion service
 func NewIngestionService(repo repository.WeatherRepository, logger *logging.StructuredLogger, metricsCollector *metrics.Collector) *IngestionService {
 	return &IngestionService{
-		repo:    repo,
-		logger:  logger,
-		metrics: metricsCollector,
+		repo:       repo,
+		logger:     logger,
+		metrics:    metricsCollector,
+		batchSlots: make(chan struct{}, 1),
 	}
 }
 
+// SetOptions sets optional ingestion behavior
+func (s *IngestionService) SetOptions(opts IngestionOptions) {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.MaxInFlightBatches < 1 {
+		// Half the workers, so the database is not written by every worker at once
+		opts.MaxInFlightBatches = max(1, opts.Workers/2)
+	}
+
+	s.options = opts
+	s.batchSlots = make(chan struct{}, opts.MaxInFlightBatches)
+}
+
+// ListIngestionRuns retrieves recorded ingestion runs, most recent first
+func (s *IngestionService) ListIngestionRuns(ctx context.Context, limit, offset int) ([]*models.IngestionRun, int, error) {
+	return s.repo.ListIngestionRuns(ctx, limit, offset)
+}
+
+// ListFailedRecords retrieves persisted ingestion failures with filtering
+func (s *IngestionService) ListFailedRecords(ctx context.Context, filter repository.FailedRecordFilter) ([]*models.FailedRecord, int, error) {
+	return s.repo.ListFailedRecords(ctx, filter)
+}
+
 // IngestDirectory ingests all weather data files from a directory
 func (s *IngestionService) IngestDirectory(ctx context.Context, dataDir string, batchSize int) (*IngestionResult, error) {
 	startTime := time.Now()
@@ -57,14 +219,19 @@ func (s *IngestionService) IngestDirectory(ctx context.Context, dataDir string,
 		Errors: make([]string, 0),
 	}
 
-	// Read directory
-	files, err := filepath.Glob(filepath.Join(dataDir, "*.txt"))
+	// Read directory; the parser for each file is chosen by the Format option
+	patterns := s.options.FilePatterns
+	if len(patterns) == 0 {
+		patterns = DefaultFilePatterns
+	}
+
+	files, err := matchFiles(dataDir, patterns)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
+		return nil, err
 	}
 
 	if len(files) == 0 {
-		return nil, fmt.Errorf("no data files found in %s", dataDir)
+		return nil, fmt.Errorf("no data files matching %s found in %s", strings.Join(patterns, ","), dataDir)
 	}
 
 	result.TotalFiles = len(files)
@@ -74,63 +241,406 @@ func (s *IngestionService) IngestDirectory(ctx context.Context, dataDir string,
 		"stage":      "FILE_DISCOVERY",
 	})
 
-	// Process each file
-	for _, filePath := range files {
-		fileResult, err := s.ingestFile(ctx, filePath, batchSize)
-		if err != nil {
-			errMsg := fmt.Sprintf("failed to ingest %s: %v", filePath, err)
-			result.Errors = append(result.Errors, errMsg)
-			s.logger.Error(ctx, "[INGEST_FILE_ERROR] File ingestion failed", logging.Fields{
-				"file_path": filePath,
-				"stage":     "FILE_PROCESSING",
-			}, err)
-			s.metrics.RecordIngestionError("file_error")
-			continue
+	var registered []string
+	if s.options.Coordinate {
+		if registered, err = s.registerFiles(ctx, dataDir, files); err != nil {
+			return nil, err
 		}
+	}
 
-		result.TotalRecords += fileResult.TotalRecords
-		result.SuccessfulRecords += fileResult.SuccessfulRecords
-		result.FailedRecords += fileResult.FailedRecords
+	// Process files with a bounded worker pool; the work channel never holds
+	// more than one pending path per worker regardless of directory size
+	workers := s.options.Workers
+	if workers < 1 {
+		workers = 1
+	}
 
-		s.logger.Info(ctx, "[INGEST_FILE_SUCCESS] File ingested successfully", logging.Fields{
-			"file_path":         filePath,
-			"total_records":     fileResult.TotalRecords,
-			"successful_records": fileResult.SuccessfulRecords,
-			"failed_records":    fileResult.FailedRecords,
-			"stage":             "FILE_COMPLETE",
-		})
+	// Exceeding MaxErrors cancels runCtx, which stops dispatch and the files
+	// still in flight without cancelling the caller's context
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+
+	tasks := make(chan fileTask, workers)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range tasks {
+				filePath := task.path
+				fileResult, err := s.ingestFile(runCtx, filePath, batchSize, task.claim)
+				if task.claim != nil {
+					s.finishClaim(ctx, task.claim, err)
+				}
+
+				mu.Lock()
+				if result.Aborted && errors.Is(err, context.Canceled) {
+					// Cut short by the abort rather than failed on its own
+					mu.Unlock()
+					continue
+				}
+				s.collectFileResult(ctx, result, filePath, fileResult, err)
+				if s.exceedsMaxErrors(result) && !result.Aborted {
+					result.Aborted = true
+					cancelRun()
+					s.logger.Warn(ctx, "[INGEST_ABORTED] Error threshold exceeded, stopping ingestion", logging.Fields{
+						"error_count": result.ErrorCount(),
+						"max_errors":  s.options.MaxErrors,
+						"stage":       "ERROR_THRESHOLD",
+					})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Coordinated runs take whichever files no other instance has claimed,
+	// so the run covers only the files claimed here
+	if s.options.Coordinate {
+		claimed, err := s.dispatchClaims(runCtx, dataDir, registered, tasks)
+		close(tasks)
+		wg.Wait()
+
+		result.TotalFiles = claimed
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to claim files: %v", err))
+			s.logger.Error(ctx, "[INGEST_CLAIM_ERROR] Failed to claim files, stopping ingestion", logging.Fields{
+				"claimed_files": claimed,
+				"stage":         "FILE_COORDINATION",
+			}, err)
+		}
+	} else {
+	dispatch:
+		for _, filePath := range files {
+			select {
+			case tasks <- fileTask{path: filePath}:
+			case <-runCtx.Done():
+				break dispatch
+			}
+		}
+		close(tasks)
+		wg.Wait()
 	}
 
 	result.Duration = time.Since(startTime)
 	s.metrics.IngestionDuration.Observe(result.Duration.Seconds())
 
 	s.logger.Info(ctx, "[INGEST_COMPLETE] Data ingestion completed", logging.Fields{
-		"total_files":        result.TotalFiles,
-		"total_records":      result.TotalRecords,
-		"successful_records": result.SuccessfulRecords,
-		"failed_records":     result.FailedRecords,
-		"duration_seconds":   result.Duration.Seconds(),
-		"records_per_second": float64(result.SuccessfulRecords) / result.Duration.Seconds(),
-		"error_count":        len(result.Errors),
-		"stage":              "COMPLETE",
+		"total_files":          result.TotalFiles,
+		"total_records":        result.TotalRecords,
+		"successful_records":   result.SuccessfulRecords,
+		"failed_records":       result.FailedRecords,
+		"out_of_order_records": result.OutOfOrderRecords,
+		"duration_seconds":     result.Duration.Seconds(),
+		"records_per_second":   float64(result.SuccessfulRecords) / result.Duration.Seconds(),
+		"error_count":          len(result.Errors),
+		"aborted":              result.Aborted,
+		"stage":                "COMPLETE",
 	})
 
+	s.recordRun(ctx, dataDir, startTime, result)
+
 	return result, nil
 }
 
+// recordRun appends the run summary to the ingestion run log
+// Uses a non-cancellable context so interrupted runs are still audited;
+// persistence errors are logged but never fail the run
+func (s *IngestionService) recordRun(ctx context.Context, dataDir string, startTime time.Time, result *IngestionResult) {
+	run := &models.IngestionRun{
+		DataDir:           dataDir,
+		StartedAt:         startTime.UTC(),
+		FinishedAt:        startTime.Add(result.Duration).UTC(),
+		FilesProcessed:    result.TotalFiles,
+		TotalRecords:      result.TotalRecords,
+		SuccessfulRecords: result.SuccessfulRecords,
+		FailedRecords:     result.FailedRecords,
+		ErrorCount:        len(result.Errors),
+		Aborted:           result.Aborted,
+	}
+
+	if err := s.repo.RecordIngestionRun(context.WithoutCancel(ctx), run); err != nil {
+		s.logger.Error(ctx, "[INGEST_RUN_PERSIST_ERROR] Failed to record ingestion run", logging.Fields{
+			"data_dir": dataDir,
+			"stage":    "RUN_LOG",
+		}, err)
+		return
+	}
+
+	s.logger.Info(ctx, "[INGEST_RUN_RECORDED] Ingestion run recorded", logging.Fields{
+		"run_id":   run.ID,
+		"data_dir": dataDir,
+		"stage":    "RUN_LOG",
+	})
+}
+
+// exceedsMaxErrors reports whether the run has passed the MaxErrors threshold
+func (s *IngestionService) exceedsMaxErrors(result *IngestionResult) bool {
+	return s.options.MaxErrors > 0 && result.ErrorCount() > s.options.MaxErrors
+}
+
+// collectFileResult merges a single file's outcome into the run result
+// Callers must serialize access to result
+func (s *IngestionService) collectFileResult(ctx context.Context, result *IngestionResult, filePath string, fileResult *FileIngestionResult, err error) {
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to ingest %s: %v", filePath, err)
+		result.Errors = append(result.Errors, errMsg)
+		s.logger.Error(ctx, "[INGEST_FILE_ERROR] File ingestion failed", logging.Fields{
+			"file_path": filePath,
+			"stage":     "FILE_PROCESSING",
+		}, err)
+		s.metrics.RecordIngestionError("file_error")
+		return
+	}
+
+	result.TotalRecords += fileResult.TotalRecords
+	result.SuccessfulRecords += fileResult.SuccessfulRecords
+	result.FailedRecords += fileResult.FailedRecords
+	result.OutOfOrderRecords += fileResult.OutOfOrderRecords
+
+	s.logger.Info(ctx, "[INGEST_FILE_SUCCESS] File ingested successfully", logging.Fields{
+		"file_path":            filePath,
+		"total_records":        fileResult.TotalRecords,
+		"successful_records":   fileResult.SuccessfulRecords,
+		"failed_records":       fileResult.FailedRecords,
+		"out_of_order_records": fileResult.OutOfOrderRecords,
+		"stage":              "FILE_COMPLETE",
+	})
+}
+
+// writeBatch inserts a batch once a write slot is available
+// Blocking on the semaphore applies backpressure to readers when the DB falls behind
+func (s *IngestionService) writeBatch(ctx context.Context, batch []*models.WeatherObservation) error {
+	select {
+	case s.batchSlots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-s.batchSlots }()
+
+	// Timed after acquiring the slot so backpressure waits are not counted as DB time
+	start := time.Now()
+	defer func() { s.metrics.ObserveProcessingTime("batch_insert", time.Since(start)) }()
+
+	return s.repo.CreateObservationsBatch(ctx, batch, s.options.Conflict)
+}
+
 // FileIngestionResult contains per-file ingestion statistics
 type FileIngestionResult struct {
 	TotalRecords      int
 	SuccessfulRecords int
 	FailedRecords     int
+	OutOfOrderRecords int                   // only counted with IngestionOptions.CheckOrdering
+	Validation        *FileValidationReport // failed records grouped by category
 }
 
 // ingestFile ingests a single weather data file
-func (s *IngestionService) ingestFile(ctx context.Context, filePath string, batchSize int) (*FileIngestionResult, error) {
+// claim is the file's shared state when runs are coordinated (nil otherwise)
+func (s *IngestionService) ingestFile(ctx context.Context, filePath string, batchSize int, claim *fileClaim) (*FileIngestionResult, error) {
+	start := time.Now()
+	defer func() { s.metrics.ObserveProcessingTime("ingest_file", time.Since(start)) }()
+
 	// Extract station ID from filename
 	fileName := filepath.Base(filePath)
 	stationID := strings.TrimSuffix(fileName, filepath.Ext(fileName))
 
+	// Open file
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	// Claim progress must be a prefix of the file, so coordinated files are read in order
+	if claim == nil && s.options.IntraFileParallelism > 1 {
+		info, err := file.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat file: %w", err)
+		}
+		if s.useIntraFileParallelism(fileName, info.Size()) {
+			return s.ingestFileRanges(ctx, stationID, file, info.Size(), batchSize)
+		}
+	}
+
+	produce := s.tabProducer(file)
+	if s.useCSV(fileName) {
+		produce = s.csvProducer(file)
+	}
+	return s.ingestRecords(ctx, stationID, batchSize, claim, produce)
+}
+
+// useCSV reports whether a file is parsed as CSV under the configured format
+func (s *IngestionService) useCSV(fileName string) bool {
+	switch s.options.Format {
+	case FormatCSV:
+		return true
+	case FormatTab:
+		return false
+	default:
+		return strings.EqualFold(filepath.Ext(fileName), ".csv")
+	}
+}
+
+// matchFiles expands glob patterns within dataDir, skipping directories,
+// symbolic links and files matched by more than one pattern
+func matchFiles(dataDir string, patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(dataDir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid file pattern %q: %w", pattern, err)
+		}
+
+		for _, match := range matches {
+			if seen[match] {
+				continue
+			}
+			// Lstat so a symbolic link cannot pull in a file from outside
+			// dataDir, such as one planted under the API ingestion root
+			if info, err := os.Lstat(match); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			seen[match] = true
+			files = append(files, match)
+		}
+	}
+
+	return files, nil
+}
+
+// inputRecord is a single row read from an ingestion source
+type inputRecord struct {
+	line   int
+	raw    string
+	fields []string
+	err    error // row-level read error, counted as a parse failure
+}
+
+// recordProducer reads rows from a source and passes each to emit
+// It stops early when emit returns false and returns any fatal read error
+type recordProducer func(emit func(inputRecord) bool) error
+
+// IngestReader ingests tab-delimited weather records for a station from any reader
+// Input is decoded from IngestionOptions.Encoding and a leading BOM is dropped
+// Batches are flushed when full and, if BatchTimeout is set, when the timeout
+// elapses with a non-empty partial batch (bounding latency for slow streams)
+func (s *IngestionService) IngestReader(ctx context.Context, stationID string, reader io.Reader, batchSize int) (*FileIngestionResult, error) {
+	return s.ingestRecords(ctx, stationID, batchSize, nil, s.tabProducer(reader))
+}
+
+// tabProducer reads tab-delimited rows from reader
+func (s *IngestionService) tabProducer(reader io.Reader) recordProducer {
+	reader = decodeInput(reader, s.options.Encoding)
+
+	return func(emit func(inputRecord) bool) error {
+		return ScanLines(reader, func(line int, text string) bool {
+			return emit(inputRecord{line: line, raw: text, fields: splitLine(text)})
+		})
+	}
+}
+
+// ScanLines calls fn with each line of reader, numbered from 1, without
+// loading the whole input into memory
+// It stops early when fn returns false and returns any read error
+func ScanLines(reader io.Reader, fn func(line int, text string) bool) error {
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		if !fn(line, scanner.Text()) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// IngestCSVReader ingests delimiter-separated weather records for a station
+// Rows use the same column order as tab-delimited files; rows with the wrong
+// column count or malformed quoting are counted as failures, not fatal errors
+func (s *IngestionService) IngestCSVReader(ctx context.Context, stationID string, reader io.Reader, batchSize int) (*FileIngestionResult, error) {
+	return s.ingestRecords(ctx, stationID, batchSize, nil, s.csvProducer(reader))
+}
+
+// csvProducer reads delimiter-separated rows from reader
+func (s *IngestionService) csvProducer(reader io.Reader) recordProducer {
+	delimiter := s.options.CSVDelimiter
+	if delimiter == 0 {
+		delimiter = ','
+	}
+
+	csvReader := csv.NewReader(decodeInput(reader, s.options.Encoding))
+	csvReader.Comma = delimiter
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	return func(emit func(inputRecord) bool) error {
+		skipHeader := s.options.CSVSkipHeader
+		for {
+			fields, err := csvReader.Read()
+			if err == io.EOF {
+				return nil
+			}
+
+			var parseErr *csv.ParseError
+			if err != nil && !errors.As(err, &parseErr) {
+				return err
+			}
+
+			if skipHeader {
+				skipHeader = false
+				continue
+			}
+
+			var line int
+			if parseErr != nil {
+				line = parseErr.Line
+			} else {
+				line, _ = csvReader.FieldPos(0)
+			}
+
+			record := inputRecord{
+				line:   line,
+				raw:    strings.Join(fields, string(delimiter)),
+				fields: fields,
+				err:    err,
+			}
+			if !emit(record) {
+				return nil
+			}
+		}
+	}
+}
+
+// ingestRecords converts and batches rows from produce into observations
+// claim is the shared state of a coordinated file (nil when not coordinated)
+func (s *IngestionService) ingestRecords(ctx context.Context, stationID string, batchSize int, claim *fileClaim, produce recordProducer) (*FileIngestionResult, error) {
+	stationID, err := s.prepareStation(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.processRecords(ctx, stationID, batchSize, claim, produce)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logValidationReport(ctx, stationID, result)
+	return result, nil
+}
+
+// prepareStation resolves stationID to its canonical ID and creates the
+// station if it does not exist yet
+func (s *IngestionService) prepareStation(ctx context.Context, stationID string) (string, error) {
+	if canonical := s.options.StationAliases.Resolve(stationID); canonical != stationID {
+		s.logger.Debug(ctx, "[INGEST_STATION_ALIAS] Station ID resolved to canonical ID", logging.Fields{
+			"alias":      stationID,
+			"station_id": canonical,
+		})
+		stationID = canonical
+	}
+
 	// Create station if not exists
 	station := &models.WeatherStation{
 		StationID: stationID,
@@ -139,88 +649,309 @@ func (s *IngestionService) ingestFile(ctx context.Context, filePath string, batc
 		UpdatedAt: time.Now().UTC(),
 	}
 
-	err := s.repo.CreateStation(ctx, station)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create station: %w", err)
+	if err := s.repo.CreateStation(ctx, station); err != nil {
+		return "", fmt.Errorf("failed to create station: %w", err)
 	}
 
-	// Open file
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
+	return stationID, nil
+}
 
-	result := &FileIngestionResult{}
+// processRecords converts and batches rows from produce for a station that
+// already exists
+// With a claim, rows up to claim.resumeAfter are skipped and the last line
+// handled is saved as the claim's progress after every flush
+func (s *IngestionService) processRecords(ctx context.Context, stationID string, batchSize int, claim *fileClaim, produce recordProducer) (*FileIngestionResult, error) {
+	result := &FileIngestionResult{Validation: NewFileValidationReport()}
 	batch := make([]*models.WeatherObservation, 0, batchSize)
+	var previousDate time.Time
+	var precipTotals cumulativePrecipitation
+
+	// Parsing and conversion time is summed and observed once per input, as
+	// individual rows take far less than the histogram's smallest bucket
+	var parseTime time.Duration
+	defer func() { s.metrics.ObserveProcessingTime("parse", parseTime) }()
+
+	// Records enter the queue depth gauge when batched and leave once written;
+	// anything still batched on return (errors, cancellation) is released here
+	defer func() {
+		s.metrics.IngestionQueueDepth.Sub(float64(len(batch)))
+	}()
+
+	// lastLine is the last row handled; once flushed, every row up to it is
+	// either written or recorded as failed
+	var lastLine int
+
+	flush := func() error {
+		if len(batch) > 0 {
+			if err := s.writeBatch(ctx, batch); err != nil {
+				return err
+			}
+			result.SuccessfulRecords += len(batch)
+			s.metrics.IngestionQueueDepth.Sub(float64(len(batch)))
+			batch = batch[:0]
+		}
+		s.saveClaimProgress(ctx, claim, lastLine)
+		return nil
+	}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		result.TotalRecords++
+	// handle converts one row and adds it to the batch, flushing when full
+	// Returns false once the row is past ToLine and reading should stop
+	handle := func(input inputRecord) (bool, error) {
+		if s.options.FromLine > 0 && input.line < s.options.FromLine {
+			return true, nil
+		}
+		if s.options.ToLine > 0 && input.line > s.options.ToLine {
+			return false, nil
+		}
 
-		line := scanner.Text()
-		record, err := s.parseLine(line)
+		if claim != nil && input.line <= claim.resumeAfter {
+			// Already ingested, but a running total still sets the
+			// baseline the first resumed row is measured from
+			if s.options.CumulativePrecip && input.err == nil {
+				if record, err := ParseFields(input.fields); err == nil {
+					precipTotals.toDaily(record.PrecipitationTenths)
+				}
+			}
+			return true, nil
+		}
+
+		result.TotalRecords++
+		lastLine = input.line
+		parseStart := time.Now()
+
+		record, err := ParseFields(input.fields)
+		if input.err != nil {
+			err = &models.ValidationError{
+				Field:   "row",
+				Value:   input.raw,
+				Message: fmt.Sprintf("invalid row: %v", input.err),
+			}
+		}
 		if err != nil {
+			parseTime += time.Since(parseStart)
 			result.FailedRecords++
 			s.metrics.RecordIngestionError("parse_error")
-			continue
+			result.Validation.Add(input.line, input.raw, err)
+			s.recordFailure(ctx, stationID, input.line, input.raw, err)
+			return true, nil
 		}
 
-		observation, err := record.ToObservation(stationID)
+		if s.options.CumulativePrecip {
+			total := record.PrecipitationTenths
+			daily, reset := precipTotals.toDaily(total)
+			if reset {
+				s.logger.Info(ctx, "[INGEST_PRECIP_RESET] Cumulative precipitation reset detected", logging.Fields{
+					"station_id":  stationID,
+					"line_number": input.line,
+					"total":       total,
+					"stage":       "CUMULATIVE_PRECIP",
+				})
+			}
+			record.PrecipitationTenths = daily
+		}
+
+		observation, err := record.ToObservationWithOptions(stationID, s.options.Conversion)
+		parseTime += time.Since(parseStart)
 		if err != nil {
 			result.FailedRecords++
 			s.metrics.RecordIngestionError("conversion_error")
-			continue
+			result.Validation.Add(input.line, input.raw, err)
+			s.recordFailure(ctx, stationID, input.line, input.raw, err)
+			return true, nil
+		}
+
+		if s.options.CheckOrdering {
+			if !previousDate.IsZero() && observation.ObservationDate.Before(previousDate) {
+				result.OutOfOrderRecords++
+				s.logger.Warn(ctx, "[INGEST_OUT_OF_ORDER] Record dated before the preceding record", logging.Fields{
+					"station_id":    stationID,
+					"line_number":   input.line,
+					"date":          observation.ObservationDate.Format("2006-01-02"),
+					"previous_date": previousDate.Format("2006-01-02"),
+					"stage":         "ORDERING_CHECK",
+				})
+			}
+			previousDate = observation.ObservationDate
 		}
 
 		batch = append(batch, observation)
+		s.metrics.IngestionQueueDepth.Inc()
 
 		// Process batch when full
 		if len(batch) >= batchSize {
-			if err := s.repo.CreateObservationsBatch(ctx, batch); err != nil {
-				return nil, fmt.Errorf("failed to insert batch: %w", err)
+			if err := flush(); err != nil {
+				return false, fmt.Errorf("failed to insert batch: %w", err)
 			}
-			result.SuccessfulRecords += len(batch)
-			batch = batch[:0]
 		}
+
+		return true, nil
 	}
 
-	// Process remaining records
-	if len(batch) > 0 {
-		if err := s.repo.CreateObservationsBatch(ctx, batch); err != nil {
-			return nil, fmt.Errorf("failed to insert final batch: %w", err)
+	var readErr error
+	if s.options.BatchTimeout > 0 {
+		// Read rows in a separate goroutine so a blocking read cannot delay timed flushes
+		records := make(chan inputRecord)
+		readDone := make(chan error, 1)
+		done := make(chan struct{})
+		stopReading := sync.OnceFunc(func() { close(done) })
+
+		// Every return waits for the reader to exit, so callers may use the
+		// underlying reader again, as IngestArchive does for its next entry.
+		// On an early return this waits out a read already in progress.
+		defer func() {
+			stopReading()
+			for range records {
+			}
+		}()
+
+		go func() {
+			defer close(records)
+			readDone <- produce(func(record inputRecord) bool {
+				select {
+				case records <- record:
+					return true
+				case <-done:
+					return false
+				}
+			})
+		}()
+
+		ticker := time.NewTicker(s.options.BatchTimeout)
+		defer ticker.Stop()
+
+	readLoop:
+		for {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+
+			case <-ticker.C:
+				if err := flush(); err != nil {
+					return nil, fmt.Errorf("failed to insert timed batch: %w", err)
+				}
+
+			case input, ok := <-records:
+				if !ok {
+					break readLoop
+				}
+
+				more, err := handle(input)
+				if err != nil {
+					return nil, err
+				}
+				if !more {
+					// Past the window: stop the reader, which then closes records
+					stopReading()
+				}
+			}
 		}
-		result.SuccessfulRecords += len(batch)
+		readErr = <-readDone
+	} else {
+		// Without timed flushes nothing has to run while a read blocks, so
+		// rows are handled inline as they are read
+		var handleErr error
+		readErr = produce(func(input inputRecord) bool {
+			if handleErr = ctx.Err(); handleErr != nil {
+				return false
+			}
+			var more bool
+			more, handleErr = handle(input)
+			return more && handleErr == nil
+		})
+		if handleErr != nil {
+			return nil, handleErr
+		}
+	}
+
+	// Process remaining records
+	if err := flush(); err != nil {
+		return nil, fmt.Errorf("failed to insert final batch: %w", err)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+	if readErr != nil {
+		return nil, fmt.Errorf("error reading input: %w", readErr)
 	}
 
 	return result, nil
 }
 
-// parseLine parses a single line from weather data file
-// Format: YYYYMMDD\tMAX_TEMP\tMIN_TEMP\tPRECIP
-func (s *IngestionService) parseLine(line string) (*models.RawWeatherRecord, error) {
-	parts := strings.Split(line, "\t")
+// logValidationReport logs a file's failed records grouped by category
+func (s *IngestionService) logValidationReport(ctx context.Context, stationID string, result *FileIngestionResult) {
+	if result.FailedRecords > 0 {
+		s.logger.Warn(ctx, "[INGEST_VALIDATION_REPORT] Records failed validation", logging.Fields{
+			"station_id":     stationID,
+			"total_records":  result.TotalRecords,
+			"failed_records": result.FailedRecords,
+			"categories":     result.Validation.Categories,
+			"stage":          "VALIDATION_REPORT",
+		})
+	}
+}
+
+// recordFailure persists a failed line to the dead-letter table when enabled
+// Persistence errors are logged but never abort ingestion
+func (s *IngestionService) recordFailure(ctx context.Context, stationID string, lineNumber int, line string, cause error) {
+	if !s.options.PersistFailures {
+		return
+	}
+
+	if err := s.repo.RecordFailure(ctx, stationID, lineNumber, line, cause.Error()); err != nil {
+		s.logger.Error(ctx, "[INGEST_FAILURE_PERSIST_ERROR] Failed to persist failed record", logging.Fields{
+			"station_id":  stationID,
+			"line_number": lineNumber,
+			"stage":       "FAILURE_PERSISTENCE",
+		}, err)
+		s.metrics.RecordIngestionError("failure_persist_error")
+	}
+}
+
+// ParseLine parses a single tab-delimited weather data row
+// It applies exactly the parsing used by IngestReader
+func ParseLine(line string) (*models.RawWeatherRecord, error) {
+	return ParseFields(splitLine(line))
+}
+
+// splitLine splits a tab-delimited row into its columns
+func splitLine(line string) []string {
+	return strings.Split(line, "\t")
+}
+
+// ParseFields parses the columns of a single weather data row
+// Format: YYYYMMDD, MAX_TEMP, MIN_TEMP, PRECIP
+func ParseFields(parts []string) (*models.RawWeatherRecord, error) {
 	if len(parts) != 4 {
-		return nil, fmt.Errorf("invalid line format: expected 4 fields, got %d", len(parts))
+		return nil, &models.ValidationError{
+			Field:   "fields",
+			Value:   strconv.Itoa(len(parts)),
+			Message: fmt.Sprintf("invalid line format: expected 4 fields, got %d", len(parts)),
+		}
 	}
 
 	maxTemp, err := strconv.Atoi(strings.TrimSpace(parts[1]))
 	if err != nil {
-		return nil, fmt.Errorf("invalid max temperature: %w", err)
+		return nil, &models.ValidationError{
+			Field:   "max_temp",
+			Value:   parts[1],
+			Message: fmt.Sprintf("invalid max temperature: %v", err),
+		}
 	}
 
 	minTemp, err := strconv.Atoi(strings.TrimSpace(parts[2]))
 	if err != nil {
-		return nil, fmt.Errorf("invalid min temperature: %w", err)
+		return nil, &models.ValidationError{
+			Field:   "min_temp",
+			Value:   parts[2],
+			Message: fmt.Sprintf("invalid min temperature: %v", err),
+		}
 	}
 
 	precip, err := strconv.Atoi(strings.TrimSpace(parts[3]))
 	if err != nil {
-		return nil, fmt.Errorf("invalid precipitation: %w", err)
+		return nil, &models.ValidationError{
+			Field:   "precip",
+			Value:   parts[3],
+			Message: fmt.Sprintf("invalid precipitation: %v", err),
+		}
 	}
 
 	return &models.RawWeatherRecord{
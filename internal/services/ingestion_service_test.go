@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"weather-platform/internal/models"
+	"weather-platform/internal/repository"
+	"weather-platform/pkg/metrics"
+)
+
+// testMetrics is shared because collectors register globally and can only be created once
+var testMetrics = metrics.NewCollector("services_test")
+
+func TestCSVProducer(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		delimiter  rune
+		skipHeader bool
+		want       []string // "line:field|field|..." per row, "line:error" for row errors
+	}{
+		{
+			name:  "default comma",
+			input: "19850101,-22,-128,94\n19850102, 10,-50,0\n",
+			want:  []string{"1:19850101|-22|-128|94", "2:19850102|10|-50|0"},
+		},
+		{
+			name:      "semicolon delimiter",
+			input:     "19850101;-22;-128;94\n",
+			delimiter: ';',
+			want:      []string{"1:19850101|-22|-128|94"},
+		},
+		{
+			name:      "tab delimiter",
+			input:     "19850101\t-22\t-128\t94\n",
+			delimiter: '\t',
+			want:      []string{"1:19850101|-22|-128|94"},
+		},
+		{
+			name:       "header skipped",
+			input:      "date,max,min,precip\n19850101,-22,-128,94\n",
+			skipHeader: true,
+			want:       []string{"2:19850101|-22|-128|94"},
+		},
+		{
+			name:  "header kept without skip",
+			input: "date,max,min,precip\n19850101,-22,-128,94\n",
+			want:  []string{"1:date|max|min|precip", "2:19850101|-22|-128|94"},
+		},
+		{
+			name:       "malformed header skipped",
+			input:      "da\"te,max,min,precip\n19850101,-22,-128,94\n",
+			skipHeader: true,
+			want:       []string{"2:19850101|-22|-128|94"},
+		},
+		{
+			name:  "malformed quotes reported per row",
+			input: "19850101,-22,-128,94\n1985\"0102,10,-50,0\n\"19850103\"x,10,-50,0\n19850104,10,-50,0\n",
+			want:  []string{"1:19850101|-22|-128|94", "2:error", "3:error", "4:19850104|10|-50|0"},
+		},
+		{
+			name:  "quoted delimiter kept in field",
+			input: "19850101,\"-2,2\",-128,94\n",
+			want:  []string{"1:19850101|-2,2|-128|94"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &IngestionService{options: IngestionOptions{CSVDelimiter: tt.delimiter, CSVSkipHeader: tt.skipHeader}}
+
+			var got []string
+			err := s.csvProducer(strings.NewReader(tt.input))(func(record inputRecord) bool {
+				if record.err != nil {
+					got = append(got, fmt.Sprintf("%d:error", record.line))
+				} else {
+					got = append(got, fmt.Sprintf("%d:%s", record.line, strings.Join(record.fields, "|")))
+				}
+				return true
+			})
+			if err != nil {
+				t.Fatalf("csvProducer() error = %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestProcessRecords_MalformedCSVRowsFail tests that rows with malformed
+// quoting are counted as failed records instead of stopping the file
+// Every row fails, so no batch is written and no repository is needed
+func TestProcessRecords_MalformedCSVRowsFail(t *testing.T) {
+	s := &IngestionService{metrics: testMetrics, options: IngestionOptions{CSVSkipHeader: true}}
+
+	input := "date,max,min,precip\n1985\"0101,-22,-128,94\n\"19850102\"x,10,-50,0\n19850103,abc,-50,0\n"
+	result, err := s.processRecords(context.Background(), "USC00110072", 10, nil, s.csvProducer(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("processRecords() error = %v", err)
+	}
+
+	if result.TotalRecords != 3 || result.FailedRecords != 3 || result.SuccessfulRecords != 0 {
+		t.Errorf("records total=%d failed=%d successful=%d, want 3, 3 and 0",
+			result.TotalRecords, result.FailedRecords, result.SuccessfulRecords)
+	}
+
+	invalid := result.Validation.Categories[ValidationInvalidRow]
+	if invalid == nil || invalid.Count != 2 {
+		t.Fatalf("invalid_row category = %+v, want 2 failures", invalid)
+	}
+	if lines := []int{invalid.Examples[0].Line, invalid.Examples[1].Line}; !slices.Equal(lines, []int{2, 3}) {
+		t.Errorf("invalid_row example lines = %v, want [2 3]", lines)
+	}
+	if numeric := result.Validation.Categories[ValidationNonNumericTemp]; numeric == nil || numeric.Count != 1 {
+		t.Errorf("non_numeric_temp category = %+v, want 1 failure", numeric)
+	}
+}
+
+// blockingRepository holds every batch write until release is closed,
+// tracking how many writes are in progress at once
+type blockingRepository struct {
+	repository.WeatherRepository
+
+	release chan struct{}
+	active  atomic.Int32
+	peak    atomic.Int32
+}
+
+func (r *blockingRepository) CreateObservationsBatch(ctx context.Context, observations []*models.WeatherObservation, conflict repository.ConflictStrategy) error {
+	active := r.active.Add(1)
+	defer r.active.Add(-1)
+	for peak := r.peak.Load(); active > peak && !r.peak.CompareAndSwap(peak, active); peak = r.peak.Load() {
+	}
+
+	<-r.release
+	return nil
+}
+
+func TestSetOptions_MaxInFlightBatchesDefault(t *testing.T) {
+	tests := []struct {
+		workers     int
+		maxInFlight int
+		want        int
+	}{
+		{workers: 0, want: 1},
+		{workers: 1, want: 1},
+		{workers: 4, want: 2},
+		{workers: 9, want: 4},
+		{workers: 4, maxInFlight: 3, want: 3},
+		{workers: 2, maxInFlight: 8, want: 8},
+	}
+
+	for _, tt := range tests {
+		s := &IngestionService{}
+		s.SetOptions(IngestionOptions{Workers: tt.workers, MaxInFlightBatches: tt.maxInFlight})
+		if got := cap(s.batchSlots); got != tt.want {
+			t.Errorf("SetOptions(workers=%d, max=%d) batch slots = %d, want %d", tt.workers, tt.maxInFlight, got, tt.want)
+		}
+	}
+}
+
+// TestWriteBatch_MaxInFlightBlocks tests that writers past the in-flight
+// limit wait for a slot, with one writer per default worker
+func TestWriteBatch_MaxInFlightBlocks(t *testing.T) {
+	const workers = 4
+	repo := &blockingRepository{release: make(chan struct{})}
+	s := &IngestionService{repo: repo, metrics: testMetrics}
+	s.SetOptions(IngestionOptions{Workers: workers})
+	limit := int32(cap(s.batchSlots))
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.writeBatch(context.Background(), nil); err != nil {
+				t.Errorf("writeBatch() error = %v", err)
+			}
+		}()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for repo.active.Load() < limit && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	// Give writers past the limit time to get in if the limit is not enforced
+	time.Sleep(50 * time.Millisecond)
+	if active := repo.active.Load(); active != limit {
+		t.Errorf("writes in progress = %d, want the limit of %d", active, limit)
+	}
+
+	// A writer waiting for a slot gives up when its context ends
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.writeBatch(ctx, nil); err != context.Canceled {
+		t.Errorf("writeBatch() with every slot taken error = %v, want context.Canceled", err)
+	}
+
+	close(repo.release)
+	wg.Wait()
+	if peak := repo.peak.Load(); peak != limit {
+		t.Errorf("peak writes in progress = %d, want %d", peak, limit)
+	}
+}
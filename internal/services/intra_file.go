@@ -0,0 +1,207 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"weather-platform/pkg/logging"
+)
+
+// DefaultIntraFileMinBytes is the smallest file split into byte ranges when
+// IngestionOptions.IntraFileMinBytes is unset
+const DefaultIntraFileMinBytes = 64 << 20
+
+// byteRange is a newline-aligned slice of a file
+// firstLine is the 1-based line number of the first line in the range
+type byteRange struct {
+	start     int64
+	end       int64
+	firstLine int
+}
+
+// useIntraFileParallelism reports whether a file of size bytes is split into
+// byte ranges parsed concurrently
+// Only tab-delimited input qualifies, as CSV quoting can span newlines, and
+// options that depend on reading rows in file order keep the sequential path
+func (s *IngestionService) useIntraFileParallelism(fileName string, size int64) bool {
+	if s.options.IntraFileParallelism < 2 || s.useCSV(fileName) {
+		return false
+	}
+	if s.options.CumulativePrecip || s.options.CheckOrdering {
+		return false
+	}
+
+	minBytes := s.options.IntraFileMinBytes
+	if minBytes <= 0 {
+		minBytes = DefaultIntraFileMinBytes
+	}
+	return size >= minBytes
+}
+
+// ingestFileRanges ingests a tab-delimited file by parsing newline-aligned
+// byte ranges concurrently
+// Every range feeds the shared batch write slots, and results are merged so
+// line numbers, counts and the validation report match a sequential read
+func (s *IngestionService) ingestFileRanges(ctx context.Context, stationID string, file *os.File, size int64, batchSize int) (*FileIngestionResult, error) {
+	ranges, err := splitRanges(file, size, s.options.IntraFileParallelism)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split file: %w", err)
+	}
+
+	stationID, err = s.prepareStation(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug(ctx, "[INGEST_FILE_RANGES] Parsing file in concurrent byte ranges", logging.Fields{
+		"station_id": stationID,
+		"file_size":  size,
+		"ranges":     len(ranges),
+	})
+
+	// A failing range cancels the others so the file fails as a whole
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*FileIngestionResult, len(ranges))
+	errs := make([]error, len(ranges))
+	var wg sync.WaitGroup
+
+	for i, rng := range ranges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			reader := io.Reader(io.NewSectionReader(file, rng.start, rng.end-rng.start))
+			if rng.start == 0 {
+				reader = decodeInput(reader, s.options.Encoding)
+			} else if s.options.Encoding == EncodingLatin1 {
+				reader = &latin1Reader{src: reader}
+			}
+
+			results[i], errs[i] = s.processRecords(ctx, stationID, batchSize, nil, func(emit func(inputRecord) bool) error {
+				return ScanLines(reader, func(line int, text string) bool {
+					return emit(inputRecord{line: rng.firstLine + line - 1, raw: text, fields: splitLine(text)})
+				})
+			})
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Report the error that caused the cancellation rather than a range it cancelled
+	var firstErr error
+	for _, err := range errs {
+		if err != nil && (firstErr == nil || firstErr == context.Canceled) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	result := &FileIngestionResult{Validation: NewFileValidationReport()}
+	for _, part := range results {
+		result.TotalRecords += part.TotalRecords
+		result.SuccessfulRecords += part.SuccessfulRecords
+		result.FailedRecords += part.FailedRecords
+		result.Validation.Merge(part.Validation)
+	}
+
+	s.logValidationReport(ctx, stationID, result)
+	return result, nil
+}
+
+// splitRanges divides size bytes of r into at most n ranges, moving each
+// boundary forward to just after a newline so no line spans two ranges
+// Line counts are gathered concurrently to number each range's first line
+func splitRanges(r io.ReaderAt, size int64, n int) ([]byteRange, error) {
+	var ranges []byteRange
+	var start int64
+
+	for i := 1; i < n && start < size; i++ {
+		offset := size * int64(i) / int64(n)
+		if offset < start {
+			continue
+		}
+
+		end, err := nextLineStart(r, offset, size)
+		if err != nil {
+			return nil, err
+		}
+		if end >= size {
+			break
+		}
+		if end > start {
+			ranges = append(ranges, byteRange{start: start, end: end})
+			start = end
+		}
+	}
+	if start < size || len(ranges) == 0 {
+		ranges = append(ranges, byteRange{start: start, end: size})
+	}
+
+	counts := make([]int, len(ranges))
+	errs := make([]error, len(ranges))
+	var wg sync.WaitGroup
+	for i, rng := range ranges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counts[i], errs[i] = countNewlines(io.NewSectionReader(r, rng.start, rng.end-rng.start))
+		}()
+	}
+	wg.Wait()
+
+	line := 1
+	for i := range ranges {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		ranges[i].firstLine = line
+		line += counts[i]
+	}
+
+	return ranges, nil
+}
+
+// nextLineStart returns the offset just past the first newline at or after
+// offset, or size when the rest of the input has none
+func nextLineStart(r io.ReaderAt, offset, size int64) (int64, error) {
+	reader := bufio.NewReader(io.NewSectionReader(r, offset, size-offset))
+	skipped, err := reader.ReadSlice('\n')
+	for err == bufio.ErrBufferFull {
+		offset += int64(len(skipped))
+		skipped, err = reader.ReadSlice('\n')
+	}
+	if err == io.EOF {
+		return size, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return offset + int64(len(skipped)), nil
+}
+
+// countNewlines counts the newline bytes in reader
+func countNewlines(reader io.Reader) (int, error) {
+	buf := make([]byte, 64*1024)
+	count := 0
+	for {
+		n, err := reader.Read(buf)
+		count += bytes.Count(buf[:n], []byte{'\n'})
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+}
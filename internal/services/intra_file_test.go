@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestSplitRanges checks ranges cover the input without splitting lines and
+// number lines as a sequential scan would
+func TestSplitRanges(t *testing.T) {
+	var sb strings.Builder
+	for i := 1; i <= 50; i++ {
+		fmt.Fprintf(&sb, "2020%04d\t%d\t%d\t0\n", i, i*10, i*5)
+	}
+	sb.WriteString("20201231\t1\t1\t1") // final line without a newline
+
+	inputs := map[string]string{
+		"lines":        sb.String(),
+		"single line":  "20200101\t100\t50\t0",
+		"long line":    strings.Repeat("x", 10000) + "\nshort\n",
+		"blank lines":  "\n\n\n\n",
+		"trailing eol": "a\nb\nc\n",
+	}
+
+	for name, input := range inputs {
+		var want []string
+		ScanLines(strings.NewReader(input), func(line int, text string) bool {
+			want = append(want, fmt.Sprintf("%d:%s", line, text))
+			return true
+		})
+
+		for n := 1; n <= 8; n++ {
+			ranges, err := splitRanges(strings.NewReader(input), int64(len(input)), n)
+			if err != nil {
+				t.Fatalf("%s: splitRanges(n=%d) error = %v", name, n, err)
+			}
+			if len(ranges) > n {
+				t.Errorf("%s: splitRanges(n=%d) returned %d ranges", name, n, len(ranges))
+			}
+
+			var got []string
+			var next int64
+			for _, rng := range ranges {
+				if rng.start != next {
+					t.Fatalf("%s: n=%d range starts at %d, want %d", name, n, rng.start, next)
+				}
+				if rng.start > 0 && input[rng.start-1] != '\n' {
+					t.Errorf("%s: n=%d range starts mid-line at %d", name, n, rng.start)
+				}
+				next = rng.end
+
+				section := io.NewSectionReader(strings.NewReader(input), rng.start, rng.end-rng.start)
+				ScanLines(section, func(line int, text string) bool {
+					got = append(got, fmt.Sprintf("%d:%s", rng.firstLine+line-1, text))
+					return true
+				})
+			}
+			if next != int64(len(input)) {
+				t.Errorf("%s: n=%d ranges end at %d, want %d", name, n, next, len(input))
+			}
+
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("%s: n=%d lines from ranges differ from a sequential scan", name, n)
+			}
+		}
+	}
+}
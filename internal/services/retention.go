@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"weather-platform/pkg/logging"
+)
+
+// RetentionCutoff returns the first day kept when retaining the given number
+// of full years before now's year
+// The cutoff is always January 1st so a purge removes whole years, whose
+// statistics are then never recalculated from a partial year
+func RetentionCutoff(now time.Time, years int) time.Time {
+	return time.Date(now.UTC().Year()-years, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PurgeObservationsBefore deletes observations of whole years before cutoff,
+// keeping their yearly statistics
+// A cutoff within a year is moved back to that year's January 1st
+func (s *WeatherService) PurgeObservationsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.repo.PurgeObservationsBefore(ctx, RetentionCutoff(cutoff, 0))
+}
+
+// RunRetention purges observations older than the retention period
+// immediately and then every interval, until ctx is cancelled
+// Purge failures are logged and retried on the next tick
+func (s *WeatherService) RunRetention(ctx context.Context, years int, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cutoff := RetentionCutoff(time.Now(), years)
+		start := time.Now()
+
+		removed, err := s.PurgeObservationsBefore(ctx, cutoff)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error(ctx, "[RETENTION_PURGE_ERROR] Observation purge failed", logging.Fields{
+				"cutoff":       cutoff.Format("2006-01-02"),
+				"rows_removed": removed,
+			}, err)
+		} else if err == nil {
+			s.logger.Info(ctx, "[RETENTION_PURGE] Observation purge completed", logging.Fields{
+				"cutoff":           cutoff.Format("2006-01-02"),
+				"retention_years":  years,
+				"rows_removed":     removed,
+				"duration_seconds": time.Since(start).Seconds(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionCutoff(t *testing.T) {
+	tests := []struct {
+		name  string
+		now   time.Time
+		years int
+		want  time.Time
+	}{
+		{"mid year", time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC), 10, time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"new year's day", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 1, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"zero years keeps the current year", time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC), 0, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"non-UTC time uses the UTC year", time.Date(2025, 1, 1, 0, 30, 0, 0, time.FixedZone("CET", 3600)), 0, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RetentionCutoff(tt.now, tt.years); !got.Equal(tt.want) {
+				t.Errorf("RetentionCutoff(%v, %d) = %v, want %v", tt.now, tt.years, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// StationAliases maps alternate station IDs (e.g. "GHCND:USC00110072") to the
+// canonical ID their records are stored under, so files from different sources
+// merge into one station
+type StationAliases map[string]string
+
+// Resolve returns the canonical station ID for id, or id itself when it has no alias
+// A nil StationAliases resolves every ID to itself
+func (a StationAliases) Resolve(id string) string {
+	if canonical, ok := a[id]; ok {
+		return canonical
+	}
+	return id
+}
+
+// LoadStationAliases reads an alias file (see ParseStationAliases)
+func LoadStationAliases(path string) (StationAliases, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open station aliases: %w", err)
+	}
+	defer file.Close()
+
+	return ParseStationAliases(file)
+}
+
+// ParseStationAliases reads "alias,canonical" rows
+// Blank lines, lines starting with # and an "alias,canonical" header are skipped.
+// Aliases are resolved in a single step, so a canonical ID may not itself be an
+// alias, and an alias may not map to two different IDs.
+func ParseStationAliases(reader io.Reader) (StationAliases, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comment = '#'
+	csvReader.FieldsPerRecord = 2
+	csvReader.TrimLeadingSpace = true
+
+	aliases := StationAliases{}
+	for {
+		fields, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid station aliases: %w", err)
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		alias, canonical := strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+
+		if alias == "alias" && canonical == "canonical" {
+			continue
+		}
+		if alias == "" || canonical == "" {
+			return nil, fmt.Errorf("invalid station aliases: line %d: alias and canonical ID are required", line)
+		}
+		if alias == canonical {
+			return nil, fmt.Errorf("invalid station aliases: line %d: %q is an alias of itself", line, alias)
+		}
+		if existing, ok := aliases[alias]; ok && existing != canonical {
+			return nil, fmt.Errorf("invalid station aliases: line %d: %q is already an alias of %q", line, alias, existing)
+		}
+
+		aliases[alias] = canonical
+	}
+
+	for alias, canonical := range aliases {
+		if _, ok := aliases[canonical]; ok {
+			return nil, fmt.Errorf("invalid station aliases: %q maps to %q, which is itself an alias", alias, canonical)
+		}
+	}
+
+	return aliases, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStationAliases(t *testing.T) {
+	input := `alias,canonical
+# GHCN-Daily prefixes
+GHCND:USC00110072,USC00110072
+
+ GHCND:USC00257715 , USC00257715
+GHCND:USC00110072,USC00110072
+`
+
+	aliases, err := ParseStationAliases(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseStationAliases() error = %v", err)
+	}
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"GHCND:USC00110072", "USC00110072"},
+		{"GHCND:USC00257715", "USC00257715"},
+		{"USC00110072", "USC00110072"},
+		{"USC00999999", "USC00999999"},
+	}
+
+	for _, tt := range tests {
+		if got := aliases.Resolve(tt.id); got != tt.want {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+
+	var none StationAliases
+	if got := none.Resolve("GHCND:USC00110072"); got != "GHCND:USC00110072" {
+		t.Errorf("nil StationAliases Resolve() = %q, want the ID unchanged", got)
+	}
+}
+
+func TestParseStationAliases_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing canonical", "GHCND:USC00110072,\n"},
+		{"wrong column count", "GHCND:USC00110072,USC00110072,extra\n"},
+		{"alias of itself", "USC00110072,USC00110072\n"},
+		{"conflicting targets", "A,USC00110072\nA,USC00257715\n"},
+		{"chained alias", "A,B\nB,USC00110072\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseStationAliases(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("ParseStationAliases(%q) succeeded, want error", tt.input)
+			}
+		})
+	}
+}
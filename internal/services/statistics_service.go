@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"weather-platform/internal/models"
@@ -16,6 +17,18 @@ type StatisticsService struct {
 	repo    repository.WeatherRepository
 	logger  *logging.StructuredLogger
 	metrics *metrics.Collector
+	options StatisticsOptions
+}
+
+// StatisticsOptions configures optional statistics behavior
+type StatisticsOptions struct {
+	// MinObservations is the observation count at which a station-year is
+	// marked reliable; sparser years are still stored but flagged (0 marks all reliable)
+	MinObservations int
+
+	// Concurrency is the number of stations calculated at once (values below 1 mean 1)
+	// Each worker holds at most one database connection at a time
+	Concurrency int
 }
 
 // NewStatisticsService creates a new statistics service
@@ -27,7 +40,15 @@ func NewStatisticsService(repo repository.WeatherRepository, logger *logging.Str
 	}
 }
 
+// SetOptions sets optional statistics behavior
+func (s *StatisticsService) SetOptions(opts StatisticsOptions) {
+	s.options = opts
+}
+
 // CalculateAllStatistics calculates statistics for all stations and years
+// Stations are spread over Concurrency workers. Failures for a station or
+// year are logged and counted without stopping the run; cancelling ctx stops
+// handing out stations and returns an error once in-flight work has stopped.
 func (s *StatisticsService) CalculateAllStatistics(ctx context.Context) error {
 	startTime := time.Now()
 
@@ -41,50 +62,204 @@ func (s *StatisticsService) CalculateAllStatistics(ctx context.Context) error {
 		return fmt.Errorf("failed to list stations: %w", err)
 	}
 
-	totalStats := 0
-	for _, station := range stations {
-		// Calculate for years 1985-2014
-		for year := 1985; year <= 2014; year++ {
-			stats, err := s.repo.CalculateYearlyStatistics(ctx, station.StationID, year)
-			if err != nil {
-				s.logger.Error(ctx, "[STATS_CALC_ERROR] Failed to calculate statistics", logging.Fields{
-					"station_id": station.StationID,
-					"year":       year,
-				}, err)
-				continue
-			}
+	workers := s.options.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(stations) {
+		workers = len(stations)
+	}
+
+	var (
+		mu                sync.Mutex
+		totalStats        int
+		failedStats       int
+		stationsCompleted int
+	)
 
-			// Only save if there are observations
-			if stats.ObservationCount > 0 {
-				if err := s.repo.UpsertStatistics(ctx, stats); err != nil {
-					s.logger.Error(ctx, "[STATS_SAVE_ERROR] Failed to save statistics", logging.Fields{
-						"station_id": station.StationID,
-						"year":       year,
-					}, err)
-					continue
+	stationIDs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for stationID := range stationIDs {
+				saved, failed, completed := s.calculateStation(ctx, stationID)
+
+				mu.Lock()
+				totalStats += saved
+				failedStats += failed
+				if completed {
+					stationsCompleted++
 				}
-				totalStats++
+				mu.Unlock()
 			}
+		}()
+	}
+
+dispatch:
+	for _, station := range stations {
+		select {
+		case stationIDs <- station.StationID:
+		case <-ctx.Done():
+			break dispatch
 		}
+	}
+	close(stationIDs)
+	wg.Wait()
 
-		s.logger.Info(ctx, "[STATS_STATION_COMPLETE] Station statistics calculated", logging.Fields{
-			"station_id": station.StationID,
+	if ctx.Err() != nil {
+		s.logger.Warn(ctx, "[STATS_CALC_CANCELLED] Statistics calculation cancelled before completion", logging.Fields{
+			"total_stations":     len(stations),
+			"stations_completed": stationsCompleted,
+			"total_statistics":   totalStats,
+			"failed_statistics":  failedStats,
+			"duration_seconds":   time.Since(startTime).Seconds(),
+			"stage":              "CANCELLED",
 		})
+		return fmt.Errorf("statistics calculation cancelled: %w", ctx.Err())
 	}
 
 	duration := time.Since(startTime)
 
 	s.logger.Info(ctx, "[STATS_CALC_COMPLETE] Statistics calculation completed", logging.Fields{
-		"total_stations":  len(stations),
-		"total_statistics": totalStats,
-		"duration_seconds": duration.Seconds(),
-		"stage":           "COMPLETE",
+		"total_stations":    len(stations),
+		"total_statistics":  totalStats,
+		"failed_statistics": failedStats,
+		"workers":           workers,
+		"min_observations":  s.options.MinObservations,
+		"duration_seconds":  duration.Seconds(),
+		"stage":             "COMPLETE",
 	})
 
 	return nil
 }
 
+// calculateStation calculates statistics for every year of one station and
+// saves them in a single batch
+// It returns the rows saved, the failures logged, and whether it finished
+// without being cancelled
+func (s *StatisticsService) calculateStation(ctx context.Context, stationID string) (saved, failed int, completed bool) {
+	// Calculate only for years that actually have observations
+	years, err := s.repo.ListAvailableYears(ctx, &stationID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, 0, false
+		}
+		s.logger.Error(ctx, "[STATS_YEARS_ERROR] Failed to list available years", logging.Fields{
+			"station_id": stationID,
+		}, err)
+		return 0, 1, true
+	}
+
+	batch := make([]*models.WeatherStatistics, 0, len(years))
+	for _, year := range years {
+		if ctx.Err() != nil {
+			return 0, failed, false
+		}
+
+		stats, err := s.repo.CalculateYearlyStatistics(ctx, stationID, year)
+		if err != nil {
+			s.logger.Error(ctx, "[STATS_CALC_ERROR] Failed to calculate statistics", logging.Fields{
+				"station_id": stationID,
+				"year":       year,
+			}, err)
+			failed++
+			continue
+		}
+
+		// Only save if there are observations
+		if stats.ObservationCount > 0 {
+			stats.IsReliable = stats.ObservationCount >= s.options.MinObservations
+			batch = append(batch, stats)
+		}
+	}
+
+	if err := s.repo.UpsertStatisticsBatch(ctx, batch); err != nil {
+		if ctx.Err() != nil {
+			return 0, failed, false
+		}
+		s.logger.Error(ctx, "[STATS_SAVE_ERROR] Failed to save statistics", logging.Fields{
+			"station_id": stationID,
+			"years":      len(batch),
+		}, err)
+		return 0, failed + len(batch), true
+	}
+
+	s.logger.Info(ctx, "[STATS_STATION_COMPLETE] Station statistics calculated", logging.Fields{
+		"station_id": stationID,
+		"saved":      len(batch),
+	})
+	return len(batch), failed, true
+}
+
 // GetStatistics retrieves statistics with filtering
 func (s *StatisticsService) GetStatistics(ctx context.Context, filter repository.StatisticsFilter) ([]*models.WeatherStatistics, int, error) {
 	return s.repo.GetStatistics(ctx, filter)
 }
+
+// StreamStatistics calls fn for every statistics row matching filter
+func (s *StatisticsService) StreamStatistics(ctx context.Context, filter repository.StatisticsFilter, fn func(*models.WeatherStatistics) error) error {
+	return s.repo.StreamStatistics(ctx, filter, fn)
+}
+
+// ListStationsMissingStatistics finds stations with observations but no calculated statistics
+func (s *StatisticsService) ListStationsMissingStatistics(ctx context.Context) ([]*models.StationObservationCount, error) {
+	return s.repo.ListStationsMissingStatistics(ctx)
+}
+
+// GetRanking retrieves the top stations for a year by a statistics metric
+func (s *StatisticsService) GetRanking(ctx context.Context, metric string, year, limit int) ([]*models.StationRanking, error) {
+	return s.repo.GetRanking(ctx, metric, year, limit)
+}
+
+// GetYearOverYearChange retrieves a station's yearly metric values and their change from the prior year
+func (s *StatisticsService) GetYearOverYearChange(ctx context.Context, stationID, metric string) ([]*models.YearOverYearChange, error) {
+	return s.repo.GetYearOverYearChange(ctx, stationID, metric)
+}
+
+// GetStationQuality computes per-year data-quality ratios for a station
+// Returns a repository.NotFoundError when the station does not exist
+func (s *StatisticsService) GetStationQuality(ctx context.Context, stationID string) ([]*models.YearlyDataQuality, error) {
+	if _, err := s.repo.GetStation(ctx, stationID); err != nil {
+		return nil, err
+	}
+
+	statistics, err := s.repo.ListStationStatistics(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	quality := make([]*models.YearlyDataQuality, 0, len(statistics))
+	for _, stats := range statistics {
+		quality = append(quality, stats.DataQuality())
+	}
+
+	return quality, nil
+}
+
+// PatchStatistics applies a manual correction to a station-year's statistics
+// Returns a models.ValidationError for invalid patches and a
+// repository.NotFoundError when the station-year does not exist
+func (s *StatisticsService) PatchStatistics(ctx context.Context, stationID string, year int, patch *models.StatisticsPatch) (*models.WeatherStatistics, error) {
+	if patch.IsEmpty() {
+		return nil, &models.ValidationError{Message: "patch must set at least one field"}
+	}
+
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.PatchStatistics(ctx, stationID, year, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "[STATS_PATCHED] Statistics manually corrected", logging.Fields{
+		"station_id": stationID,
+		"year":       year,
+		"patch":      patch,
+	})
+
+	return stats, nil
+}
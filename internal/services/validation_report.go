@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+
+	"weather-platform/internal/models"
+)
+
+// Validation error categories reported per file
+const (
+	ValidationBadDate          = "bad_date"
+	ValidationNonNumericTemp   = "non_numeric_temp"
+	ValidationNonNumericPrecip = "non_numeric_precip"
+	ValidationNegativePrecip   = "negative_precip"
+	ValidationWrongFieldCount  = "wrong_field_count"
+	ValidationInvalidRow       = "invalid_row"
+	ValidationOther            = "other"
+)
+
+// maxValidationExamples bounds the examples kept per category so a file of
+// bad rows cannot grow the report without limit
+const maxValidationExamples = 3
+
+// maxExampleLength truncates example lines kept in the report
+const maxExampleLength = 200
+
+// ValidationExample is one failed line kept as an illustration of its category
+type ValidationExample struct {
+	Line  int    `json:"line"`
+	Raw   string `json:"raw"`
+	Error string `json:"error"`
+}
+
+// ValidationCategory counts the failures of one category
+type ValidationCategory struct {
+	Count    int                 `json:"count"`
+	Examples []ValidationExample `json:"examples"`
+}
+
+// FileValidationReport summarizes the records of a file that failed parsing
+// or conversion, grouped by category with a few examples each
+type FileValidationReport struct {
+	Categories map[string]*ValidationCategory `json:"categories"`
+}
+
+// NewFileValidationReport creates an empty report
+func NewFileValidationReport() *FileValidationReport {
+	return &FileValidationReport{Categories: make(map[string]*ValidationCategory)}
+}
+
+// Add records a failed line under the category of err
+func (r *FileValidationReport) Add(line int, raw string, err error) {
+	category := validationCategory(err)
+
+	entry, ok := r.Categories[category]
+	if !ok {
+		entry = &ValidationCategory{}
+		r.Categories[category] = entry
+	}
+
+	entry.Count++
+	if len(entry.Examples) < maxValidationExamples {
+		if len(raw) > maxExampleLength {
+			raw = raw[:maxExampleLength]
+		}
+		entry.Examples = append(entry.Examples, ValidationExample{Line: line, Raw: raw, Error: err.Error()})
+	}
+}
+
+// Merge adds the counts and examples of other, keeping examples in order up
+// to the per-category limit
+func (r *FileValidationReport) Merge(other *FileValidationReport) {
+	for category, src := range other.Categories {
+		entry, ok := r.Categories[category]
+		if !ok {
+			entry = &ValidationCategory{}
+			r.Categories[category] = entry
+		}
+
+		entry.Count += src.Count
+		for _, example := range src.Examples {
+			if len(entry.Examples) >= maxValidationExamples {
+				break
+			}
+			entry.Examples = append(entry.Examples, example)
+		}
+	}
+}
+
+// Count returns the number of failures recorded under category
+func (r *FileValidationReport) Count(category string) int {
+	if entry, ok := r.Categories[category]; ok {
+		return entry.Count
+	}
+	return 0
+}
+
+// validationCategory maps a parse or conversion error to its report category
+func validationCategory(err error) string {
+	var validationErr *models.ValidationError
+	if !errors.As(err, &validationErr) {
+		return ValidationOther
+	}
+
+	switch validationErr.Field {
+	case "date":
+		return ValidationBadDate
+	case "max_temp", "min_temp":
+		return ValidationNonNumericTemp
+	case "precip":
+		return ValidationNonNumericPrecip
+	case "precipitation_cm":
+		return ValidationNegativePrecip
+	case "fields":
+		return ValidationWrongFieldCount
+	case "row":
+		return ValidationInvalidRow
+	default:
+		return ValidationOther
+	}
+}
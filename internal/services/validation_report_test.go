@@ -0,0 +1,101 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"weather-platform/internal/models"
+)
+
+func TestFileValidationReport(t *testing.T) {
+	lines := []string{
+		"20200101\t100\t50",
+		"20200102\tabc\t50\t0",
+		"20200103\t100\t-\t0",
+		"20200104\t100\t50\tx",
+		"2020-01-05\t100\t50\t0",
+		"20201301\t100\t50\t0",
+		"20200106\t100\t50\t0\textra",
+		"20200107\t1.5\t50\t0",
+		"20200108\t1x\t50\t0",
+		"20200109\t100\t50\t0",
+		"20200110\t100\t50\t-50",
+		"20200111\t100\t50\t-9999",
+	}
+
+	report := NewFileValidationReport()
+	for i, line := range lines {
+		record, err := ParseLine(line)
+		if err == nil {
+			_, err = record.ToObservation("USC00110072")
+		}
+		if err != nil {
+			report.Add(i+1, line, err)
+		}
+	}
+
+	tests := []struct {
+		category string
+		want     int
+	}{
+		{ValidationWrongFieldCount, 2},
+		{ValidationNonNumericTemp, 4},
+		{ValidationNonNumericPrecip, 1},
+		{ValidationNegativePrecip, 1},
+		{ValidationBadDate, 2},
+		{ValidationInvalidRow, 0},
+		{ValidationOther, 0},
+	}
+
+	for _, tt := range tests {
+		if got := report.Count(tt.category); got != tt.want {
+			t.Errorf("Count(%q) = %d, want %d", tt.category, got, tt.want)
+		}
+	}
+
+	temp := report.Categories[ValidationNonNumericTemp]
+	if len(temp.Examples) != maxValidationExamples {
+		t.Fatalf("non_numeric_temp examples = %d, want %d", len(temp.Examples), maxValidationExamples)
+	}
+	if first := temp.Examples[0]; first.Line != 2 || first.Raw != lines[1] || first.Error == "" {
+		t.Errorf("first non_numeric_temp example = %+v, want line 2 with its raw text and error", first)
+	}
+}
+
+func TestValidationCategoryUnclassified(t *testing.T) {
+	report := NewFileValidationReport()
+	report.Add(1, "line", fmt.Errorf("unexpected"))
+
+	if got := report.Count(ValidationOther); got != 1 {
+		t.Errorf("Count(other) = %d, want 1", got)
+	}
+}
+
+func TestFileValidationReportMerge(t *testing.T) {
+	badDate := &models.ValidationError{Field: "date", Value: "2020-01-01", Message: "invalid date format"}
+	first := NewFileValidationReport()
+	second := NewFileValidationReport()
+	for i := 1; i <= 2; i++ {
+		first.Add(i, "bad", badDate)
+	}
+	for i := 10; i <= 12; i++ {
+		second.Add(i, "bad", badDate)
+	}
+	second.Add(13, "short", fmt.Errorf("unclassified"))
+
+	merged := NewFileValidationReport()
+	merged.Merge(first)
+	merged.Merge(second)
+
+	if got := merged.Count(ValidationBadDate); got != 5 {
+		t.Errorf("Count(bad_date) = %d, want 5", got)
+	}
+	if got := merged.Count(ValidationOther); got != 1 {
+		t.Errorf("Count(other) = %d, want 1", got)
+	}
+
+	examples := merged.Categories[ValidationBadDate].Examples
+	if len(examples) != maxValidationExamples || examples[0].Line != 1 || examples[2].Line != 10 {
+		t.Errorf("merged bad_date examples = %+v, want lines 1, 2, 10", examples)
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"weather-platform/internal/models"
 	"weather-platform/internal/repository"
@@ -30,7 +31,148 @@ func (s *WeatherService) GetObservations(ctx context.Context, filter repository.
 	return s.repo.GetObservations(ctx, filter)
 }
 
-// GetStations retrieves all weather stations
-func (s *WeatherService) GetStations(ctx context.Context, limit, offset int) ([]*models.WeatherStation, error) {
-	return s.repo.ListStations(ctx, limit, offset)
+// GetDiurnalRange retrieves daily max minus min temperature with filtering and pagination
+func (s *WeatherService) GetDiurnalRange(ctx context.Context, filter repository.ObservationFilter) ([]*models.DiurnalRange, int, error) {
+	return s.repo.GetDiurnalRange(ctx, filter)
+}
+
+// CountObservations counts observations matching the filter
+func (s *WeatherService) CountObservations(ctx context.Context, filter repository.ObservationFilter) (int, error) {
+	return s.repo.CountObservations(ctx, filter)
+}
+
+// GetStations retrieves a page of weather stations and the total station count
+func (s *WeatherService) GetStations(ctx context.Context, limit, offset int) ([]*models.WeatherStation, int, error) {
+	stations, err := s.repo.ListStations(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountStations(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return stations, total, nil
+}
+
+// SearchStations finds stations whose ID contains query, best matches first
+func (s *WeatherService) SearchStations(ctx context.Context, query string, limit int) ([]*models.WeatherStation, error) {
+	return s.repo.SearchStations(ctx, query, limit)
+}
+
+// GetLatestObservations retrieves the most recent observation per station
+func (s *WeatherService) GetLatestObservations(ctx context.Context, stationIDs []string) ([]*models.WeatherObservation, error) {
+	return s.repo.GetLatestObservations(ctx, stationIDs)
+}
+
+// BackfillMissingDates inserts all-null observations for absent dates in a station's range
+func (s *WeatherService) BackfillMissingDates(ctx context.Context, stationID string, from, to time.Time) (int64, error) {
+	return s.repo.BackfillMissingDates(ctx, stationID, from, to)
+}
+
+// CompactDuplicates removes duplicate station/date observations
+func (s *WeatherService) CompactDuplicates(ctx context.Context) (int64, error) {
+	return s.repo.CompactDuplicates(ctx)
+}
+
+// ListAvailableYears retrieves the distinct years with observations
+func (s *WeatherService) ListAvailableYears(ctx context.Context, stationID *string) ([]int, error) {
+	return s.repo.ListAvailableYears(ctx, stationID)
+}
+
+// CompareStations retrieves aligned daily metric values for two stations
+func (s *WeatherService) CompareStations(ctx context.Context, stationA, stationB, metric string, from, to time.Time) ([]*models.DailyComparison, error) {
+	return s.repo.CompareStations(ctx, stationA, stationB, metric, from, to)
+}
+
+// GetExtremes retrieves the hottest, coldest, and wettest days
+func (s *WeatherService) GetExtremes(ctx context.Context, year *int, stationID *string) (*models.WeatherExtremes, error) {
+	return s.repo.GetExtremes(ctx, year, stationID)
+}
+
+// GetMissingDates retrieves dates without observations for a station
+func (s *WeatherService) GetMissingDates(ctx context.Context, stationID string, from, to time.Time) ([]time.Time, error) {
+	return s.repo.GetMissingDates(ctx, stationID, from, to)
+}
+
+// GetMovingAverage retrieves a trailing moving average of a metric for a station
+func (s *WeatherService) GetMovingAverage(ctx context.Context, stationID, metric string, window int, from, to time.Time) ([]*models.MovingAveragePoint, error) {
+	return s.repo.GetMovingAverage(ctx, stationID, metric, window, from, to)
+}
+
+// GetHistogram retrieves the distribution of a metric across equal-width bins
+func (s *WeatherService) GetHistogram(ctx context.Context, metric string, bins int, filter repository.ObservationFilter) ([]*models.HistogramBin, error) {
+	return s.repo.GetHistogram(ctx, metric, bins, filter)
+}
+
+// CalculateFrostFreeSeason retrieves the frost-free season for a station-year
+func (s *WeatherService) CalculateFrostFreeSeason(ctx context.Context, stationID string, year int) (*models.FrostFreeSeason, error) {
+	return s.repo.CalculateFrostFreeSeason(ctx, stationID, year)
+}
+
+// GetMonthlyObservationCounts retrieves a station-year's observation count per month
+// Returns a repository.NotFoundError when the station does not exist
+func (s *WeatherService) GetMonthlyObservationCounts(ctx context.Context, stationID string, year int) ([]*models.MonthlyObservationCount, error) {
+	if _, err := s.repo.GetStation(ctx, stationID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMonthlyObservationCounts(ctx, stationID, year)
+}
+
+// GetClimateNormals retrieves a station's per-day-of-year averages across all years
+// Returns a repository.NotFoundError when the station does not exist
+func (s *WeatherService) GetClimateNormals(ctx context.Context, stationID string) ([]*models.ClimateNormal, error) {
+	if _, err := s.repo.GetStation(ctx, stationID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetClimateNormals(ctx, stationID)
+}
+
+// GetCorrelation retrieves the Pearson correlation between two metrics over the filtered observations
+func (s *WeatherService) GetCorrelation(ctx context.Context, metricX, metricY string, filter repository.ObservationFilter) (*models.Correlation, error) {
+	return s.repo.GetCorrelation(ctx, metricX, metricY, filter)
+}
+
+// GetLongestStreak retrieves a station's longest run of consecutive days meeting condition
+// Returns a repository.NotFoundError when the station does not exist
+func (s *WeatherService) GetLongestStreak(ctx context.Context, stationID, condition string, threshold float64) (*models.Streak, error) {
+	if _, err := s.repo.GetStation(ctx, stationID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetLongestStreak(ctx, stationID, condition, threshold)
+}
+
+// GetObservationHistory retrieves a station-date observation with its previous values
+// Returns a repository.NotFoundError when the observation does not exist
+func (s *WeatherService) GetObservationHistory(ctx context.Context, stationID string, date time.Time) (*models.WeatherObservation, []*models.ObservationHistory, error) {
+	current, err := s.repo.GetObservationByStationDate(ctx, stationID, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history, err := s.repo.GetObservationHistory(ctx, stationID, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return current, history, nil
+}
+
+// GetGlobalStats retrieves dataset-wide station and observation totals
+func (s *WeatherService) GetGlobalStats(ctx context.Context, exact bool) (*models.GlobalStats, error) {
+	return s.repo.GetGlobalStats(ctx, exact)
+}
+
+// GetWeatherEvents retrieves the stations meeting an event's thresholds on one date
+func (s *WeatherService) GetWeatherEvents(ctx context.Context, filter repository.EventFilter) ([]*models.WeatherEvent, error) {
+	return s.repo.GetWeatherEvents(ctx, filter)
+}
+
+// CalculateDegreeDays retrieves heating, cooling and growing degree days for a station-year
+func (s *WeatherService) CalculateDegreeDays(ctx context.Context, stationID string, year int, base float64) (*models.DegreeDays, error) {
+	return s.repo.CalculateDegreeDays(ctx, stationID, year, base)
 }
@@ -0,0 +1,5 @@
+-- Rollback migration 002 - Drop dead-letter table
+
+DROP INDEX IF EXISTS idx_failed_records_station_created;
+
+DROP TABLE IF EXISTS failed_records CASCADE;
@@ -0,0 +1,19 @@
+-- Migration: 002 - Dead-letter table for records that failed ingestion
+
+CREATE TABLE IF NOT EXISTS failed_records (
+    id BIGSERIAL PRIMARY KEY,
+    station_id VARCHAR(50) NOT NULL REFERENCES weather_stations(station_id) ON DELETE CASCADE,
+    line_number INTEGER NOT NULL,
+    raw_line TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_line_number CHECK (line_number > 0)
+);
+
+-- Index for reviewing failures per station, most recent first
+CREATE INDEX IF NOT EXISTS idx_failed_records_station_created ON failed_records(station_id, created_at DESC);
+
+COMMENT ON TABLE failed_records IS 'Raw input lines that failed parsing or conversion during ingestion';
+COMMENT ON COLUMN failed_records.raw_line IS 'Original line content as read from the source file';
+COMMENT ON COLUMN failed_records.reason IS 'Parse or conversion error message';
@@ -0,0 +1,5 @@
+-- Rollback migration 003 - Drop ingestion run log
+
+DROP INDEX IF EXISTS idx_ingestion_runs_started;
+
+DROP TABLE IF EXISTS ingestion_runs CASCADE;
@@ -0,0 +1,29 @@
+-- Migration: 003 - Audit log of ingestion runs
+
+CREATE TABLE IF NOT EXISTS ingestion_runs (
+    id BIGSERIAL PRIMARY KEY,
+    data_dir TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    files_processed INTEGER NOT NULL DEFAULT 0,
+    total_records INTEGER NOT NULL DEFAULT 0,
+    successful_records INTEGER NOT NULL DEFAULT 0,
+    failed_records INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_run_window CHECK (finished_at >= started_at),
+    CONSTRAINT valid_run_counts CHECK (
+        files_processed >= 0 AND
+        total_records >= 0 AND
+        successful_records >= 0 AND
+        failed_records >= 0 AND
+        error_count >= 0
+    )
+);
+
+-- Index for listing recent runs
+CREATE INDEX IF NOT EXISTS idx_ingestion_runs_started ON ingestion_runs(started_at DESC);
+
+COMMENT ON TABLE ingestion_runs IS 'One row per ingestion run for audit and reproducibility';
+COMMENT ON COLUMN ingestion_runs.data_dir IS 'Directory the run ingested files from';
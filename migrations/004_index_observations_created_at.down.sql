@@ -0,0 +1,3 @@
+-- Rollback migration 004 - Drop ingestion time index
+
+DROP INDEX IF EXISTS idx_weather_obs_created_at;
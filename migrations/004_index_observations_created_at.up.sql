@@ -0,0 +1,3 @@
+-- Migration: 004 - Index observations by ingestion time for created_after queries
+
+CREATE INDEX IF NOT EXISTS idx_weather_obs_created_at ON weather_observations(created_at);
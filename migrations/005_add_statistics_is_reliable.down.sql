@@ -0,0 +1,5 @@
+-- Rollback migration 005 - Drop statistics reliability flag
+
+DROP INDEX IF EXISTS idx_weather_stats_year_reliable;
+
+ALTER TABLE weather_statistics DROP COLUMN IF EXISTS is_reliable;
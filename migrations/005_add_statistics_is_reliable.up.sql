@@ -0,0 +1,8 @@
+-- Migration: 005 - Flag statistics computed from too few observations
+
+ALTER TABLE weather_statistics ADD COLUMN IF NOT EXISTS is_reliable BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Index for filtering reliable statistics by year
+CREATE INDEX IF NOT EXISTS idx_weather_stats_year_reliable ON weather_statistics(year, is_reliable);
+
+COMMENT ON COLUMN weather_statistics.is_reliable IS 'Observation count met STATS_MIN_OBSERVATIONS when calculated';
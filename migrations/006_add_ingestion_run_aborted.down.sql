@@ -0,0 +1,3 @@
+-- Rollback migration 006 - Drop ingestion run aborted flag
+
+ALTER TABLE ingestion_runs DROP COLUMN IF EXISTS aborted;
@@ -0,0 +1,5 @@
+-- Migration: 006 - Flag ingestion runs stopped by the error threshold
+
+ALTER TABLE ingestion_runs ADD COLUMN IF NOT EXISTS aborted BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN ingestion_runs.aborted IS 'Run stopped early after exceeding the -max-errors threshold';
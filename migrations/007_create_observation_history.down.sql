@@ -0,0 +1,9 @@
+-- Rollback migration 007 - Drop observation history
+
+DROP TRIGGER IF EXISTS record_weather_observation_history ON weather_observations;
+
+DROP FUNCTION IF EXISTS trg_record_observation_history();
+
+DROP INDEX IF EXISTS idx_weather_obs_history_station;
+
+DROP TABLE IF EXISTS weather_observation_history CASCADE;
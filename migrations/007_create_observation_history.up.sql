@@ -0,0 +1,46 @@
+-- Migration: 007 - Audit history of corrected observation values
+
+CREATE TABLE IF NOT EXISTS weather_observation_history (
+    id BIGSERIAL PRIMARY KEY,
+    observation_id BIGINT NOT NULL,
+    station_id VARCHAR(50) NOT NULL REFERENCES weather_stations(station_id) ON DELETE CASCADE,
+    observation_date DATE NOT NULL,
+    max_temperature_celsius DECIMAL(5,2),
+    min_temperature_celsius DECIMAL(5,2),
+    precipitation_cm DECIMAL(8,4),
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for listing the corrections of one observation, most recent first
+CREATE INDEX IF NOT EXISTS idx_weather_obs_history_station ON weather_observation_history(station_id, observation_date, changed_at DESC);
+
+-- Copy the previous values whenever an update (including an upsert) changes them
+CREATE OR REPLACE FUNCTION trg_record_observation_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO weather_observation_history (
+        observation_id, station_id, observation_date,
+        max_temperature_celsius, min_temperature_celsius, precipitation_cm
+    )
+    VALUES (
+        OLD.id, OLD.station_id, OLD.observation_date,
+        OLD.max_temperature_celsius, OLD.min_temperature_celsius, OLD.precipitation_cm
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS record_weather_observation_history ON weather_observations;
+
+CREATE TRIGGER record_weather_observation_history
+    AFTER UPDATE ON weather_observations
+    FOR EACH ROW
+    WHEN (
+        OLD.max_temperature_celsius IS DISTINCT FROM NEW.max_temperature_celsius OR
+        OLD.min_temperature_celsius IS DISTINCT FROM NEW.min_temperature_celsius OR
+        OLD.precipitation_cm IS DISTINCT FROM NEW.precipitation_cm
+    )
+    EXECUTE FUNCTION trg_record_observation_history();
+
+COMMENT ON TABLE weather_observation_history IS 'Previous observation values, one row per update that changed them';
+COMMENT ON COLUMN weather_observation_history.changed_at IS 'When the values in this row were replaced';
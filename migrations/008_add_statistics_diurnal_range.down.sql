@@ -0,0 +1,3 @@
+-- Rollback migration 008 - Drop yearly average diurnal range
+
+ALTER TABLE weather_statistics DROP COLUMN IF EXISTS avg_diurnal_range_celsius;
@@ -0,0 +1,5 @@
+-- Migration: 008 - Yearly average diurnal temperature range
+
+ALTER TABLE weather_statistics ADD COLUMN IF NOT EXISTS avg_diurnal_range_celsius DECIMAL(5,2);
+
+COMMENT ON COLUMN weather_statistics.avg_diurnal_range_celsius IS 'Average of max minus min temperature over days with both values, NULL when there are none';
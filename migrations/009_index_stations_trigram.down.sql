@@ -0,0 +1,4 @@
+-- Rollback migration 009 - Drop station ID trigram index
+-- The pg_trgm extension is left installed as other objects may depend on it
+
+DROP INDEX IF EXISTS idx_weather_stations_id_trgm;
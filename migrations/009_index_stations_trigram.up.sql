@@ -0,0 +1,6 @@
+-- Migration: 009 - Trigram index on station IDs for substring search
+
+-- pg_trgm ships with PostgreSQL; creating it needs the CREATE privilege on the database
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_weather_stations_id_trgm ON weather_stations USING GIN (station_id gin_trgm_ops);
@@ -0,0 +1,3 @@
+-- Rollback migration 010 - Drop station timezone
+
+ALTER TABLE weather_stations DROP COLUMN IF EXISTS timezone;
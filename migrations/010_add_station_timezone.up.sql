@@ -0,0 +1,5 @@
+-- Migration: 010 - Station timezone for local-time statistics
+
+ALTER TABLE weather_stations ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+COMMENT ON COLUMN weather_stations.timezone IS 'IANA time zone name used when statistics are grouped by local year';
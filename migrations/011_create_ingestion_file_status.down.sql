@@ -0,0 +1,5 @@
+-- Rollback migration 011 - Drop per-file ingestion state
+
+DROP INDEX IF EXISTS idx_ingestion_file_status_status;
+
+DROP TABLE IF EXISTS ingestion_file_status CASCADE;
@@ -0,0 +1,20 @@
+-- Migration: 011 - Per-file ingestion state shared by ingester instances
+
+CREATE TABLE IF NOT EXISTS ingestion_file_status (
+    file_name TEXT PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    last_line INTEGER NOT NULL DEFAULT 0,
+    claimed_by TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_file_status CHECK (status IN ('pending', 'in_progress', 'done', 'failed')),
+    CONSTRAINT valid_last_line CHECK (last_line >= 0)
+);
+
+-- Index for claiming the next pending or stale file
+CREATE INDEX IF NOT EXISTS idx_ingestion_file_status_status ON ingestion_file_status(status, updated_at);
+
+COMMENT ON TABLE ingestion_file_status IS 'Ingestion state of each data file so several ingesters can share a directory and resume';
+COMMENT ON COLUMN ingestion_file_status.file_name IS 'File path relative to the data directory';
+COMMENT ON COLUMN ingestion_file_status.status IS 'pending, in_progress, done, or failed (not claimed again until reset to pending)';
+COMMENT ON COLUMN ingestion_file_status.last_line IS 'Last line whose records are committed; a resumed file continues after it';
@@ -0,0 +1,3 @@
+-- Rollback migration 012 - Restore station timezone
+
+ALTER TABLE weather_stations ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
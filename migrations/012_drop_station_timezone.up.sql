@@ -0,0 +1,5 @@
+-- Migration: 012 - Drop station timezone
+-- Observation dates are already the station's local calendar days, so yearly
+-- statistics never needed a timezone and nothing reads the column
+
+ALTER TABLE weather_stations DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,48 @@
+// Package migrations embeds the SQL schema migrations so binaries can apply
+// them without the migrations directory on disk
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Script is a single migration file
+type Script struct {
+	Name string
+	SQL  string
+}
+
+// Load returns the migration scripts for direction ("up" or "down") in the
+// order they must run: ascending for up, descending for down
+func Load(direction string) ([]Script, error) {
+	if direction != "up" && direction != "down" {
+		return nil, fmt.Errorf("unknown migration direction %q: expected up or down", direction)
+	}
+
+	names, err := fs.Glob(files, "*."+direction+".sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	sort.Strings(names)
+	if direction == "down" {
+		sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	}
+
+	scripts := make([]Script, 0, len(names))
+	for _, name := range names {
+		content, err := files.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+		scripts = append(scripts, Script{Name: name, SQL: string(content)})
+	}
+
+	return scripts, nil
+}
@@ -0,0 +1,99 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoad tests that every migration is embedded and ordered by direction
+func TestLoad(t *testing.T) {
+	up, err := Load("up")
+	if err != nil {
+		t.Fatalf("Load(up) error = %v", err)
+	}
+	down, err := Load("down")
+	if err != nil {
+		t.Fatalf("Load(down) error = %v", err)
+	}
+
+	if len(up) == 0 || len(up) != len(down) {
+		t.Fatalf("got %d up and %d down migrations, want the same non-zero count", len(up), len(down))
+	}
+
+	for i := range up {
+		upVersion := strings.TrimSuffix(up[i].Name, ".up.sql")
+		downVersion := strings.TrimSuffix(down[len(down)-1-i].Name, ".down.sql")
+		if upVersion != downVersion {
+			t.Errorf("up migration %d is %s but the matching down migration is %s", i, up[i].Name, down[len(down)-1-i].Name)
+		}
+		if up[i].SQL == "" {
+			t.Errorf("migration %s is empty", up[i].Name)
+		}
+	}
+
+	if up[0].Name != "001_create_schema.up.sql" {
+		t.Errorf("first up migration = %s, want 001_create_schema.up.sql", up[0].Name)
+	}
+
+	if _, err := Load("sideways"); err == nil {
+		t.Error("Load(sideways) expected error")
+	}
+}
+
+// TestScriptVersion tests that up and down scripts of a migration share a version
+func TestScriptVersion(t *testing.T) {
+	up, err := Load("up")
+	if err != nil {
+		t.Fatalf("Load(up) error = %v", err)
+	}
+
+	seen := make(map[string]string)
+	for _, script := range up {
+		version := script.Version()
+		if len(version) != 3 {
+			t.Errorf("%s version = %q, want a three-digit prefix", script.Name, version)
+		}
+		if other, ok := seen[version]; ok {
+			t.Errorf("%s and %s share version %s", script.Name, other, version)
+		}
+		seen[version] = script.Name
+	}
+
+	if got := (Script{Name: "011_create_ingestion_file_status.down.sql"}).Version(); got != "011" {
+		t.Errorf("Version() = %q, want 011", got)
+	}
+}
+
+// TestUpScriptsRerunnable tests that up scripts after the first only create
+// objects conditionally, so databases created before versions were recorded
+// can be brought under the runner: the first script is recorded as the
+// baseline (see Runner.BaselineTable) and every later one is applied again
+func TestUpScriptsRerunnable(t *testing.T) {
+	up, err := Load("up")
+	if err != nil {
+		t.Fatalf("Load(up) error = %v", err)
+	}
+
+	for _, script := range up[1:] {
+		lines := strings.Split(script.SQL, "\n")
+		for i, line := range lines {
+			statement := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(statement, "CREATE TABLE"), strings.HasPrefix(statement, "CREATE INDEX"),
+				strings.HasPrefix(statement, "CREATE EXTENSION"):
+				if !strings.Contains(statement, "IF NOT EXISTS") {
+					t.Errorf("%s line %d creates without IF NOT EXISTS: %s", script.Name, i+1, line)
+				}
+			case strings.HasPrefix(statement, "ALTER TABLE") && strings.Contains(statement, "ADD COLUMN"):
+				if !strings.Contains(statement, "ADD COLUMN IF NOT EXISTS") {
+					t.Errorf("%s line %d adds a column without IF NOT EXISTS: %s", script.Name, i+1, line)
+				}
+			case strings.HasPrefix(statement, "CREATE TRIGGER"):
+				name := strings.Fields(statement)[2]
+				if !strings.Contains(strings.ToUpper(script.SQL), "DROP TRIGGER IF EXISTS "+name+" ") {
+					t.Errorf("%s creates trigger %s without dropping it first", script.Name, name)
+				}
+			}
+		}
+	}
+}
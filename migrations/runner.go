@@ -0,0 +1,195 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// DefaultVersionTable is the table recording applied migration versions
+const DefaultVersionTable = "schema_migrations"
+
+// Version returns the numeric prefix identifying the migration, e.g. "001"
+// for 001_create_schema.up.sql; up and down scripts share it
+func (s Script) Version() string {
+	version, _, _ := strings.Cut(s.Name, "_")
+	return version
+}
+
+// Runner applies migrations against a database, recording every applied
+// version in a version table so a rerun only applies the missing ones
+// Each script runs in its own transaction together with its version row, so a
+// failing migration leaves the earlier ones applied and itself not recorded.
+// Concurrent runners serialize on an advisory lock taken in each transaction.
+type Runner struct {
+	DB *sql.DB
+
+	// Table is the version table name (empty uses DefaultVersionTable)
+	Table string
+
+	// Rewrite adapts each script before it runs, such as applying a table
+	// prefix (nil runs scripts unchanged)
+	Rewrite func(string) string
+
+	// OnApply is called after each script is committed (nil disables)
+	OnApply func(Script)
+
+	// BaselineTable names a table the first migration creates, after any
+	// Rewrite (empty disables). When Up finds no version recorded but this
+	// table present, the database predates version tracking: the first
+	// migration is recorded without running it, and the later ones, which
+	// only create what is missing, are applied.
+	BaselineTable string
+}
+
+func (r *Runner) table() string {
+	if r.Table != "" {
+		return r.Table
+	}
+	return DefaultVersionTable
+}
+
+// Up applies the up scripts whose versions are not yet recorded, in order
+// Returns the scripts applied by this call
+func (r *Runner) Up(ctx context.Context) ([]Script, error) {
+	scripts, err := Load("up")
+	if err != nil {
+		return nil, err
+	}
+	return r.run(ctx, scripts, false)
+}
+
+// Down reverts every recorded version, newest first, removing its record
+// Versions that were never recorded are left alone
+// Returns the scripts applied by this call
+func (r *Runner) Down(ctx context.Context) ([]Script, error) {
+	scripts, err := Load("down")
+	if err != nil {
+		return nil, err
+	}
+	return r.run(ctx, scripts, true)
+}
+
+func (r *Runner) run(ctx context.Context, scripts []Script, down bool) ([]Script, error) {
+	if err := r.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	if !down && r.BaselineTable != "" && len(scripts) > 0 {
+		if err := r.adoptBaseline(ctx, scripts[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	var applied []Script
+	for _, script := range scripts {
+		ran, err := r.apply(ctx, script, down)
+		if err != nil {
+			return applied, err
+		}
+		if !ran {
+			continue
+		}
+
+		applied = append(applied, script)
+		if r.OnApply != nil {
+			r.OnApply(script)
+		}
+	}
+
+	return applied, nil
+}
+
+// ensureTable creates the version table if it does not exist
+func (r *Runner) ensureTable(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			version VARCHAR(16) PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`, r.table())
+
+	if _, err := r.DB.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", r.table(), err)
+	}
+	return nil
+}
+
+// adoptBaseline records script, the first migration, as applied when no
+// version is recorded yet and BaselineTable already exists
+func (r *Runner) adoptBaseline(ctx context.Context, script Script) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, r.table()); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", r.table(), err)
+	}
+
+	var untracked bool
+	query := fmt.Sprintf(`SELECT NOT EXISTS (SELECT 1 FROM %s) AND to_regclass($1) IS NOT NULL`, r.table())
+	if err := tx.QueryRowContext(ctx, query, r.BaselineTable).Scan(&untracked); err != nil {
+		return fmt.Errorf("failed to read %s: %w", r.table(), err)
+	}
+	if !untracked {
+		return nil
+	}
+
+	query = fmt.Sprintf(`INSERT INTO %s (version, name) VALUES ($1, $2)`, r.table())
+	if _, err := tx.ExecContext(ctx, query, script.Version(), script.Name); err != nil {
+		return fmt.Errorf("failed to record baseline migration %s: %w", script.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+// apply runs script in a transaction if its version still needs it: up
+// scripts run when the version is missing, down scripts when it is recorded
+// Reports whether the script ran
+func (r *Runner) apply(ctx context.Context, script Script, down bool) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Checked under the lock so a concurrent runner cannot apply the same version
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, r.table()); err != nil {
+		return false, fmt.Errorf("failed to lock %s: %w", r.table(), err)
+	}
+
+	var recorded bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE version = $1)`, r.table())
+	if err := tx.QueryRowContext(ctx, query, script.Version()).Scan(&recorded); err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", r.table(), err)
+	}
+	if recorded != down {
+		return false, nil
+	}
+
+	statement := script.SQL
+	if r.Rewrite != nil {
+		statement = r.Rewrite(statement)
+	}
+	if _, err := tx.ExecContext(ctx, statement); err != nil {
+		return false, fmt.Errorf("failed to apply migration %s: %w", script.Name, err)
+	}
+
+	if down {
+		query = fmt.Sprintf(`DELETE FROM %s WHERE version = $1`, r.table())
+		_, err = tx.ExecContext(ctx, query, script.Version())
+	} else {
+		query = fmt.Sprintf(`INSERT INTO %s (version, name) VALUES ($1, $2)`, r.table())
+		_, err = tx.ExecContext(ctx, query, script.Version(), script.Name)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record migration %s: %w", script.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit migration %s: %w", script.Name, err)
+	}
+	return true, nil
+}
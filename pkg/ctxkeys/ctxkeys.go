@@ -0,0 +1,36 @@
+// Package ctxkeys defines typed context keys for request-scoped values
+// Producers (middleware) and consumers (the logger) share these helpers so a
+// key can never be mistyped on one side or collide with another package's key
+package ctxkeys
+
+import "context"
+
+// key is unexported so only this package can create context keys
+type key int
+
+const (
+	requestIDKey key = iota
+	tenantIDKey
+)
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFrom returns the request ID stored in ctx, if any
+func RequestIDFrom(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey).(string)
+	return requestID, ok
+}
+
+// WithTenantID returns a copy of ctx carrying the tenant ID
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey, tenantID)
+}
+
+// TenantIDFrom returns the tenant ID stored in ctx, if any
+func TenantIDFrom(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantIDKey).(string)
+	return tenantID, ok
+}
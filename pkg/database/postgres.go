@@ -3,12 +3,20 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 
+	"weather-platform/pkg/ctxkeys"
 	"weather-platform/pkg/logging"
 	"weather-platform/pkg/metrics"
 )
@@ -25,40 +33,180 @@ type Config struct {
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+
+	// MinConns connections are opened in parallel at startup (0 disables warmup)
+	MinConns int
+
+	// SlowQueryThreshold logs queries slower than this at Warn and counts them
+	// in db_slow_queries_total (0 disables)
+	SlowQueryThreshold time.Duration
+
+	// FailoverHosts are tried in order after Host when it cannot be reached,
+	// at startup and when the pool monitor loses the active host
+	// Entries are "host" or "host:port" (Port is used when omitted)
+	FailoverHosts []string
+
+	// QueryComments prepends "/* request_id=... */" to query text when the
+	// context carries a request ID, so pg_stat_activity can be tied to API requests
+	QueryComments bool
+
+	// PoolMonitorInterval is how often pool metrics are published, utilization
+	// checked and, with FailoverHosts, the active host pinged (0 disables the monitor)
+	PoolMonitorInterval time.Duration
+
+	// PoolWarnUtilization logs [DB_POOL_WARNING] when in-use connections exceed
+	// this fraction of MaxOpenConns (0 disables)
+	PoolWarnUtilization float64
+
+	// MaxConcurrentTx caps transactions holding a slot from AcquireTxSlot at
+	// once, independent of how many goroutines want one (0 means unlimited)
+	MaxConcurrentTx int
+
+	// Options are extra connection parameters appended to the DSN, e.g.
+	// connect_timeout or options=-c statement_timeout=30000
+	// application_name defaults to the logger's service name
+	Options map[string]string
+}
+
+// dsn builds the key/value connection string for host and port
+// Extra options follow the base parameters in key order
+func (cfg *Config) dsn(host, port, applicationName string) string {
+	params := []string{
+		"host=" + quoteDSNValue(host),
+		"port=" + quoteDSNValue(port),
+		"user=" + quoteDSNValue(cfg.User),
+		"password=" + quoteDSNValue(cfg.Password),
+		"dbname=" + quoteDSNValue(cfg.Database),
+		"sslmode=" + quoteDSNValue(cfg.SSLMode),
+	}
+
+	options := make(map[string]string, len(cfg.Options)+1)
+	if applicationName != "" {
+		options["application_name"] = applicationName
+	}
+	for key, value := range cfg.Options {
+		options[key] = value
+	}
+
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		params = append(params, key+"="+quoteDSNValue(options[key]))
+	}
+
+	return strings.Join(params, " ")
+}
+
+// quoteDSNValue single-quotes a connection string value when it is empty or
+// contains spaces, quotes or backslashes, escaping quotes and backslashes
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n'\\") {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// addresses returns the candidate host:port addresses, primary first
+func (cfg *Config) addresses() []string {
+	port := strconv.Itoa(cfg.Port)
+	addrs := []string{net.JoinHostPort(cfg.Host, port)}
+	for _, host := range cfg.FailoverHosts {
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, port)
+		}
+		addrs = append(addrs, host)
+	}
+	return addrs
 }
 
 // PostgresDB wraps sqlx.DB with monitoring and metrics
+// The underlying pool is replaced when failing over to another host
 type PostgresDB struct {
-	db      *sqlx.DB
 	logger  *logging.StructuredLogger
 	metrics *metrics.Collector
 	config  *Config
+
+	// addrs are the candidate hosts in priority order
+	addrs []string
+
+	mu     sync.RWMutex
+	db     *sqlx.DB
+	active string
+
+	// stop is closed by Close to end the pool monitor, which then marks
+	// monitorDone so Close can wait for it
+	stop        chan struct{}
+	monitorDone sync.WaitGroup
+	closeOnce   sync.Once
+
+	// txSlots is a semaphore bounding concurrent write transactions (nil when unlimited)
+	txSlots chan struct{}
 }
 
 // NewPostgresDB creates a new PostgreSQL database connection
+// Candidate hosts are tried in order and the first that answers a ping is used
 func NewPostgresDB(cfg *Config, logger *logging.StructuredLogger, metricsCollector *metrics.Collector) (*PostgresDB, error) {
-	// Build connection string
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host,
-		cfg.Port,
-		cfg.User,
-		cfg.Password,
-		cfg.Database,
-		cfg.SSLMode,
-	)
+	pgDB := &PostgresDB{
+		logger:  logger,
+		metrics: metricsCollector,
+		config:  cfg,
+		addrs:   cfg.addresses(),
+		stop:    make(chan struct{}),
+	}
+	if cfg.MaxConcurrentTx > 0 {
+		pgDB.txSlots = make(chan struct{}, cfg.MaxConcurrentTx)
+	}
+
+	db, active, err := pgDB.connectFirst(pgDB.addrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	pgDB.db = db
+	pgDB.active = active
+	metricsCollector.SetDBActiveHost(active, pgDB.addrs)
+
+	logger.Info(context.Background(), "[DB_INIT] PostgreSQL connection established", logging.Fields{
+		"host":              active,
+		"failover_hosts":    len(pgDB.addrs) - 1,
+		"database":          cfg.Database,
+		"max_open_conns":    cfg.MaxOpenConns,
+		"max_idle_conns":    cfg.MaxIdleConns,
+		"max_concurrent_tx": cfg.MaxConcurrentTx,
+		"conn_max_lifetime": cfg.ConnMaxLifetime.String(),
+	})
+
+	// Warm the pool so the first burst of traffic does not pay connection setup
+	if cfg.MinConns > 0 {
+		pgDB.warmup(cfg.MinConns)
+	}
+
+	// Start monitoring connection pool
+	pgDB.startPoolMonitor()
+
+	return pgDB, nil
+}
+
+// connect opens a pool to addr and verifies it with a ping
+func (p *PostgresDB) connect(addr string) (*sqlx.DB, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
 
 	// Open database connection
-	db, err := sqlx.Open("postgres", dsn)
+	db, err := sqlx.Open("postgres", p.config.dsn(host, port, p.logger.Service()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
 	// Configure connection pool
-	db.SetMaxOpenConns(cfg.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
-	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	db.SetMaxOpenConns(p.config.MaxOpenConns)
+	db.SetMaxIdleConns(p.config.MaxIdleConns)
+	db.SetConnMaxLifetime(p.config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(p.config.ConnMaxIdleTime)
 
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -66,50 +214,170 @@ func NewPostgresDB(cfg *Config, logger *logging.StructuredLogger, metricsCollect
 
 	if err := db.PingContext(ctx); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, err
 	}
 
-	logger.Info(context.Background(), "[DB_INIT] PostgreSQL connection established", logging.Fields{
-		"host":              cfg.Host,
-		"port":              cfg.Port,
-		"database":          cfg.Database,
-		"max_open_conns":    cfg.MaxOpenConns,
-		"max_idle_conns":    cfg.MaxIdleConns,
-		"conn_max_lifetime": cfg.ConnMaxLifetime.String(),
+	return db, nil
+}
+
+// connectFirst returns a pool for the first reachable address in addrs
+func (p *PostgresDB) connectFirst(addrs []string) (*sqlx.DB, string, error) {
+	var errs []error
+	for _, addr := range addrs {
+		db, err := p.connect(addr)
+		if err == nil {
+			return db, addr, nil
+		}
+
+		p.logger.Warn(context.Background(), "[DB_CONNECT_FAILED] Database host unreachable", logging.Fields{
+			"host":  addr,
+			"error": err.Error(),
+		})
+		errs = append(errs, fmt.Errorf("%s: %w", addr, err))
+	}
+	return nil, "", errors.Join(errs...)
+}
+
+// failover replaces the pool with one to the next reachable candidate host
+// Candidates after the active host are tried first, wrapping around so a
+// recovered primary is picked up again
+func (p *PostgresDB) failover(cause error) {
+	ctx := context.Background()
+	previous := p.ActiveHost()
+
+	p.logger.Error(ctx, "[DB_CONNECTION_LOST] Active database host unreachable, attempting failover", logging.Fields{
+		"host": previous,
+	}, cause)
+
+	next := 0
+	for i, addr := range p.addrs {
+		if addr == previous {
+			next = i + 1
+			break
+		}
+	}
+	order := append(append([]string{}, p.addrs[next:]...), p.addrs[:next]...)
+
+	db, active, err := p.connectFirst(order)
+	if err != nil {
+		p.metrics.RecordDBError("failover_error")
+		p.logger.Error(ctx, "[DB_FAILOVER_FAILED] No database host reachable", logging.Fields{
+			"hosts": p.addrs,
+		}, err)
+		return
+	}
+
+	p.mu.Lock()
+	old := p.db
+	p.db = db
+	p.active = active
+	p.mu.Unlock()
+
+	// Close waits for running queries, so do not block monitoring on it
+	go old.Close()
+
+	p.metrics.SetDBActiveHost(active, p.addrs)
+	if active != previous {
+		p.metrics.DBFailoversTotal.Inc()
+	}
+	p.logger.Warn(ctx, "[DB_FAILOVER] Switched database host", logging.Fields{
+		"from": previous,
+		"to":   active,
 	})
+}
 
-	pgDB := &PostgresDB{
-		db:      db,
-		logger:  logger,
-		metrics: metricsCollector,
-		config:  cfg,
+// warmup opens up to n connections concurrently and returns them to the idle pool
+// Warmup failures are logged but never fatal; the pool fills lazily instead
+func (p *PostgresDB) warmup(n int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Connections beyond MaxIdleConns would be closed as soon as they are released
+	if p.config.MaxIdleConns > 0 && n > p.config.MaxIdleConns {
+		p.logger.Warn(ctx, "[DB_WARMUP] MinConns exceeds MaxIdleConns, clamping", logging.Fields{
+			"min_conns":      n,
+			"max_idle_conns": p.config.MaxIdleConns,
+		})
+		n = p.config.MaxIdleConns
 	}
 
-	// Start monitoring connection pool
-	go pgDB.monitorConnectionPool()
+	timer := time.Now()
+	conns := make([]*sql.Conn, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conn, err := p.DB().Conn(ctx)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			conns[i] = conn
+			errs[i] = conn.PingContext(ctx)
+		}(i)
+	}
+	wg.Wait()
 
-	return pgDB, nil
+	// Release only after all are open so each goroutine got a distinct connection
+	warmed := 0
+	for i, conn := range conns {
+		if conn == nil {
+			continue
+		}
+		if errs[i] == nil {
+			warmed++
+		}
+		conn.Close()
+	}
+
+	fields := logging.Fields{
+		"requested":   n,
+		"warmed":      warmed,
+		"duration_ms": time.Since(timer).Milliseconds(),
+	}
+	if warmed < n {
+		p.logger.Warn(ctx, "[DB_WARMUP] Connection pool partially warmed", fields)
+		return
+	}
+	p.logger.Info(ctx, "[DB_WARMUP] Connection pool warmed", fields)
 }
 
-// Close closes the database connection
+// Close stops the pool monitor and closes the database connection
+// It returns once the monitor goroutine has exited, so open/close cycles do not leak it
 func (p *PostgresDB) Close() error {
+	p.closeOnce.Do(func() { close(p.stop) })
+	p.monitorDone.Wait()
+
 	p.logger.Info(context.Background(), "[DB_CLOSE] Closing database connection", logging.Fields{
 		"database": p.config.Database,
 	})
-	return p.db.Close()
+	return p.DB().Close()
 }
 
-// DB returns the underlying sqlx.DB instance
+// DB returns the underlying sqlx.DB instance for the active host
 func (p *PostgresDB) DB() *sqlx.DB {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 	return p.db
 }
 
+// ActiveHost returns the host:port currently serving queries
+func (p *PostgresDB) ActiveHost() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.active
+}
+
 // QueryContext executes a query with context and metrics
 func (p *PostgresDB) QueryContext(ctx context.Context, queryType, query string, args ...interface{}) (*sqlx.Rows, error) {
 	timer := time.Now()
 	defer func() {
 		duration := time.Since(timer)
 		p.metrics.DBQueryDuration.WithLabelValues(queryType).Observe(duration.Seconds())
+		p.observeSlowQuery(ctx, queryType, duration)
 
 		p.logger.Debug(ctx, "[DB_QUERY] Query executed", logging.Fields{
 			"query_type":       queryType,
@@ -118,7 +386,7 @@ func (p *PostgresDB) QueryContext(ctx context.Context, queryType, query string,
 		})
 	}()
 
-	rows, err := p.db.QueryxContext(ctx, query, args...)
+	rows, err := p.DB().QueryxContext(ctx, p.commentQuery(ctx, query), args...)
 	if err != nil {
 		p.metrics.RecordDBError("query_error")
 		p.logger.Error(ctx, "[DB_QUERY_ERROR] Query failed", logging.Fields{
@@ -137,6 +405,7 @@ func (p *PostgresDB) ExecContext(ctx context.Context, queryType, query string, a
 	defer func() {
 		duration := time.Since(timer)
 		p.metrics.DBQueryDuration.WithLabelValues(queryType).Observe(duration.Seconds())
+		p.observeSlowQuery(ctx, queryType, duration)
 
 		p.logger.Debug(ctx, "[DB_EXEC] Command executed", logging.Fields{
 			"query_type":  queryType,
@@ -144,7 +413,7 @@ func (p *PostgresDB) ExecContext(ctx context.Context, queryType, query string, a
 		})
 	}()
 
-	result, err := p.db.ExecContext(ctx, query, args...)
+	result, err := p.DB().ExecContext(ctx, p.commentQuery(ctx, query), args...)
 	if err != nil {
 		p.metrics.RecordDBError("exec_error")
 		p.logger.Error(ctx, "[DB_EXEC_ERROR] Command failed", logging.Fields{
@@ -162,9 +431,10 @@ func (p *PostgresDB) GetContext(ctx context.Context, queryType string, dest inte
 	defer func() {
 		duration := time.Since(timer)
 		p.metrics.DBQueryDuration.WithLabelValues(queryType).Observe(duration.Seconds())
+		p.observeSlowQuery(ctx, queryType, duration)
 	}()
 
-	err := p.db.GetContext(ctx, dest, query, args...)
+	err := p.DB().GetContext(ctx, dest, p.commentQuery(ctx, query), args...)
 	if err != nil && err != sql.ErrNoRows {
 		p.metrics.RecordDBError("get_error")
 		p.logger.Error(ctx, "[DB_GET_ERROR] Get query failed", logging.Fields{
@@ -181,9 +451,10 @@ func (p *PostgresDB) SelectContext(ctx context.Context, queryType string, dest i
 	defer func() {
 		duration := time.Since(timer)
 		p.metrics.DBQueryDuration.WithLabelValues(queryType).Observe(duration.Seconds())
+		p.observeSlowQuery(ctx, queryType, duration)
 	}()
 
-	err := p.db.SelectContext(ctx, dest, query, args...)
+	err := p.DB().SelectContext(ctx, dest, p.commentQuery(ctx, query), args...)
 	if err != nil {
 		p.metrics.RecordDBError("select_error")
 		p.logger.Error(ctx, "[DB_SELECT_ERROR] Select query failed", logging.Fields{
@@ -195,9 +466,43 @@ func (p *PostgresDB) SelectContext(ctx context.Context, queryType string, dest i
 	return nil
 }
 
+// commentQuery prepends the request ID in ctx to query as a SQL comment when
+// QueryComments is enabled (the sqlcommenter pattern)
+func (p *PostgresDB) commentQuery(ctx context.Context, query string) string {
+	if !p.config.QueryComments {
+		return query
+	}
+	return withRequestIDComment(ctx, query)
+}
+
+// withRequestIDComment prepends "/* request_id=<id> */ " to query
+// The ID is query-escaped so it cannot close the comment or inject SQL
+func withRequestIDComment(ctx context.Context, query string) string {
+	requestID, ok := ctxkeys.RequestIDFrom(ctx)
+	if !ok || requestID == "" {
+		return query
+	}
+	return "/* request_id=" + url.QueryEscape(requestID) + " */ " + query
+}
+
+// observeSlowQuery warns about and counts queries exceeding the slow query threshold
+func (p *PostgresDB) observeSlowQuery(ctx context.Context, queryType string, duration time.Duration) {
+	threshold := p.config.SlowQueryThreshold
+	if threshold <= 0 || duration < threshold {
+		return
+	}
+
+	p.metrics.DBSlowQueriesTotal.WithLabelValues(queryType).Inc()
+	p.logger.Warn(ctx, "[DB_SLOW_QUERY] Query exceeded slow query threshold", logging.Fields{
+		"query_type":   queryType,
+		"duration_ms":  duration.Milliseconds(),
+		"threshold_ms": threshold.Milliseconds(),
+	})
+}
+
 // BeginTx begins a new transaction
 func (p *PostgresDB) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
-	tx, err := p.db.BeginTxx(ctx, &sql.TxOptions{
+	tx, err := p.DB().BeginTxx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelSerializable,
 	})
 	if err != nil {
@@ -209,13 +514,56 @@ func (p *PostgresDB) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
 	return tx, nil
 }
 
+// AcquireTxSlot blocks until fewer than MaxConcurrentTx transactions hold a
+// slot or ctx is done. Call the returned release once the transaction has
+// committed or rolled back. Without a limit it returns immediately.
+func (p *PostgresDB) AcquireTxSlot(ctx context.Context) (release func(), err error) {
+	if p.txSlots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case p.txSlots <- struct{}{}:
+		return func() { <-p.txSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startPoolMonitor runs monitorConnectionPool in the background until Close
+// Does nothing when PoolMonitorInterval is 0
+func (p *PostgresDB) startPoolMonitor() {
+	if p.config.PoolMonitorInterval <= 0 {
+		return
+	}
+
+	p.monitorDone.Add(1)
+	go p.monitorConnectionPool(p.config.PoolMonitorInterval)
+}
+
 // monitorConnectionPool periodically updates connection pool metrics
-func (p *PostgresDB) monitorConnectionPool() {
-	ticker := time.NewTicker(10 * time.Second)
+// and fails over when the active host stops answering pings
+// It returns when stop is closed
+func (p *PostgresDB) monitorConnectionPool(interval time.Duration) {
+	defer p.monitorDone.Done()
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		stats := p.db.Stats()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if len(p.addrs) > 1 {
+			if err := p.HealthCheck(context.Background()); err != nil {
+				p.failover(err)
+			}
+		}
+
+		stats := p.DB().Stats()
 
 		p.metrics.UpdateDBConnectionPool(
 			stats.InUse,
@@ -225,13 +573,14 @@ func (p *PostgresDB) monitorConnectionPool() {
 
 		// Log warning if connection pool is near capacity
 		utilization := float64(stats.InUse) / float64(p.config.MaxOpenConns)
-		if utilization > 0.8 {
+		if threshold := p.config.PoolWarnUtilization; threshold > 0 && utilization > threshold {
 			p.logger.Warn(context.Background(), "[DB_POOL_WARNING] Connection pool utilization high", logging.Fields{
-				"in_use":       stats.InUse,
-				"idle":         stats.Idle,
-				"total":        stats.OpenConnections,
-				"max_open":     p.config.MaxOpenConns,
-				"utilization":  fmt.Sprintf("%.2f%%", utilization*100),
+				"in_use":      stats.InUse,
+				"idle":        stats.Idle,
+				"total":       stats.OpenConnections,
+				"max_open":    p.config.MaxOpenConns,
+				"utilization": fmt.Sprintf("%.2f%%", utilization*100),
+				"threshold":   fmt.Sprintf("%.2f%%", threshold*100),
 			})
 		}
 	}
@@ -242,7 +591,7 @@ func (p *PostgresDB) HealthCheck(ctx context.Context) error {
 	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	if err := p.db.PingContext(pingCtx); err != nil {
+	if err := p.DB().PingContext(pingCtx); err != nil {
 		return fmt.Errorf("database health check failed: %w", err)
 	}
 
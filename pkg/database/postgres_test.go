@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"weather-platform/pkg/ctxkeys"
+	"weather-platform/pkg/logging"
+	"weather-platform/pkg/metrics"
+)
+
+func TestWithRequestIDComment(t *testing.T) {
+	const query = "SELECT 1"
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no request ID", context.Background(), query},
+		{"empty request ID", ctxkeys.WithRequestID(context.Background(), ""), query},
+		{"request ID", ctxkeys.WithRequestID(context.Background(), "abc-123"), "/* request_id=abc-123 */ SELECT 1"},
+		{"comment terminator escaped", ctxkeys.WithRequestID(context.Background(), "x*/; DROP TABLE t; --"), "/* request_id=x%2A%2F%3B+DROP+TABLE+t%3B+-- */ SELECT 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withRequestIDComment(tt.ctx, query); got != tt.want {
+				t.Errorf("withRequestIDComment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommentQueryDisabled(t *testing.T) {
+	p := &PostgresDB{config: &Config{}}
+	ctx := ctxkeys.WithRequestID(context.Background(), "abc-123")
+
+	if got := p.commentQuery(ctx, "SELECT 1"); got != "SELECT 1" {
+		t.Errorf("commentQuery() with QueryComments off = %q, want query unchanged", got)
+	}
+}
+
+func TestConfigDSN(t *testing.T) {
+	cfg := &Config{
+		User:     "weather",
+		Password: `it's a \secret`,
+		Database: "weather_db",
+		SSLMode:  "disable",
+		Options: map[string]string{
+			"connect_timeout": "5",
+			"options":         "-c statement_timeout=30000",
+		},
+	}
+
+	want := `host=db port=5432 user=weather password='it\'s a \\secret' dbname=weather_db sslmode=disable ` +
+		`application_name=weather-api connect_timeout=5 options='-c statement_timeout=30000'`
+	if got := cfg.dsn("db", "5432", "weather-api"); got != want {
+		t.Errorf("dsn() = %q, want %q", got, want)
+	}
+
+	cfg.Options["application_name"] = "weather-api-blue"
+	cfg.Password = ""
+	want = `host=db port=5432 user=weather password='' dbname=weather_db sslmode=disable ` +
+		`application_name=weather-api-blue connect_timeout=5 options='-c statement_timeout=30000'`
+	if got := cfg.dsn("db", "5432", "weather-api"); got != want {
+		t.Errorf("dsn() with application_name option = %q, want %q", got, want)
+	}
+}
+
+// testMetrics is shared because collectors register globally and can only be created once
+var testMetrics = metrics.NewCollector("database_test")
+
+// TestCloseStopsPoolMonitor opens and closes pools with a fast monitor and
+// checks the goroutine count returns to its baseline
+// sql.Open connects lazily, so no database is needed
+func TestCloseStopsPoolMonitor(t *testing.T) {
+	logger := logging.NewStructuredLogger("database-test", "test", logging.ErrorLevel)
+	logger.SetOutput(io.Discard)
+
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 20; i++ {
+		db, err := sqlx.Open("postgres", "host=127.0.0.1 port=1 sslmode=disable")
+		if err != nil {
+			t.Fatalf("sqlx.Open() error = %v", err)
+		}
+
+		p := &PostgresDB{
+			logger:  logger,
+			metrics: testMetrics,
+			config:  &Config{MaxOpenConns: 1, PoolMonitorInterval: time.Millisecond},
+			addrs:   []string{"127.0.0.1:1"},
+			db:      db,
+			stop:    make(chan struct{}),
+		}
+		p.startPoolMonitor()
+
+		// Let the monitor tick at least once before stopping it
+		time.Sleep(2 * time.Millisecond)
+
+		if err := p.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if err := p.Close(); err != nil {
+			t.Fatalf("second Close() error = %v", err)
+		}
+	}
+
+	// database/sql's own goroutines exit asynchronously after Close
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines after 20 open/close cycles = %d, want at most %d", after, before)
+	}
+}
+
+// TestAcquireTxSlot tests that slots are bounded, released, and abandoned on cancellation
+func TestAcquireTxSlot(t *testing.T) {
+	unlimited := &PostgresDB{}
+	for i := 0; i < 3; i++ {
+		if _, err := unlimited.AcquireTxSlot(context.Background()); err != nil {
+			t.Fatalf("AcquireTxSlot() without a limit error = %v", err)
+		}
+	}
+
+	p := &PostgresDB{txSlots: make(chan struct{}, 2)}
+	first, err := p.AcquireTxSlot(context.Background())
+	if err != nil {
+		t.Fatalf("AcquireTxSlot() error = %v", err)
+	}
+	if _, err := p.AcquireTxSlot(context.Background()); err != nil {
+		t.Fatalf("AcquireTxSlot() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.AcquireTxSlot(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("AcquireTxSlot() beyond the limit error = %v, want context.DeadlineExceeded", err)
+	}
+
+	first()
+	if _, err := p.AcquireTxSlot(context.Background()); err != nil {
+		t.Fatalf("AcquireTxSlot() after release error = %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// asyncWriter queues encoded entries for a single writer goroutine
+type asyncWriter struct {
+	entries chan asyncEntry
+	done    chan struct{}
+	dropped atomic.Uint64
+
+	// mu guards closed so no entry is sent on the closed channel
+	mu     sync.RWMutex
+	closed bool
+}
+
+// asyncEntry is an encoded log line, or a flush marker when flushed is set
+type asyncEntry struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+// EnableAsync moves output for the root logger and its named sub-loggers to a
+// background writer with a buffer of bufferSize entries, so callers no longer
+// wait on the output's I/O
+// When the buffer is full Debug entries are dropped and counted; every other
+// level blocks until there is room. Call Close before exit to drain the buffer.
+// Does nothing for a bufferSize below 1 or when async output is already enabled.
+func (l *StructuredLogger) EnableAsync(bufferSize int) {
+	root := l.rootLogger()
+	if bufferSize < 1 || root.async.Load() != nil {
+		return
+	}
+
+	a := &asyncWriter{
+		entries: make(chan asyncEntry, bufferSize),
+		done:    make(chan struct{}),
+	}
+	if !root.async.CompareAndSwap(nil, a) {
+		return
+	}
+
+	go func() {
+		defer close(a.done)
+		for entry := range a.entries {
+			if entry.flushed != nil {
+				close(entry.flushed)
+				continue
+			}
+			root.write(entry.data)
+		}
+	}()
+}
+
+// enqueue hands data to the async writer
+// Returns false when async output is disabled or closed, so the caller writes directly
+func (l *StructuredLogger) enqueue(level LogLevel, data []byte) bool {
+	a := l.rootLogger().async.Load()
+	if a == nil {
+		return false
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return false
+	}
+
+	if level == DebugLevel {
+		select {
+		case a.entries <- asyncEntry{data: data}:
+		default:
+			a.dropped.Add(1)
+		}
+		return true
+	}
+
+	a.entries <- asyncEntry{data: data}
+	return true
+}
+
+// Flush blocks until every entry logged before the call has been written
+// It returns immediately when async output is disabled
+func (l *StructuredLogger) Flush() {
+	a := l.rootLogger().async.Load()
+	if a == nil {
+		return
+	}
+
+	flushed := make(chan struct{})
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return
+	}
+	a.entries <- asyncEntry{flushed: flushed}
+	a.mu.RUnlock()
+
+	<-flushed
+}
+
+// Close drains the async buffer and stops the writer goroutine
+// Later entries are written synchronously. Safe to call more than once.
+func (l *StructuredLogger) Close() {
+	root := l.rootLogger()
+	a := root.async.Load()
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	alreadyClosed := a.closed
+	if !alreadyClosed {
+		a.closed = true
+		close(a.entries)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+
+	if dropped := a.dropped.Load(); dropped > 0 && !alreadyClosed {
+		root.Warn(context.Background(), "[LOGGER_DROPPED] Debug log entries dropped while the async buffer was full", Fields{
+			"dropped": dropped,
+		})
+	}
+}
+
+// Dropped returns the number of Debug entries dropped by the async buffer
+func (l *StructuredLogger) Dropped() uint64 {
+	a := l.rootLogger().async.Load()
+	if a == nil {
+		return 0
+	}
+	return a.dropped.Load()
+}
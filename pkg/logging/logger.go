@@ -7,8 +7,12 @@ import (
 	"io"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"weather-platform/pkg/ctxkeys"
 )
 
 // LogLevel represents the severity level of a log message
@@ -43,7 +47,26 @@ func (l LogLevel) String() string {
 // Fields represents structured log fields
 type Fields map[string]interface{}
 
+// ParseLogLevel converts a level name (debug, info, warn, error, fatal) to a LogLevel
+func ParseLogLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("unknown log level: %q", name)
+	}
+}
+
 // StructuredLogger provides structured JSON logging with context
+// Named sub-loggers share output and level state with their root logger
 type StructuredLogger struct {
 	level      LogLevel
 	output     io.Writer
@@ -51,6 +74,14 @@ type StructuredLogger struct {
 	service    string
 	version    string
 	hostname   string
+
+	// Component sub-logger support
+	component       string
+	componentLevels map[string]LogLevel
+	root            *StructuredLogger
+
+	// async is set by EnableAsync on the root logger
+	async atomic.Pointer[asyncWriter]
 }
 
 // LogEntry represents a single structured log entry
@@ -60,6 +91,7 @@ type LogEntry struct {
 	Service     string                 `json:"service"`
 	Version     string                 `json:"version"`
 	Hostname    string                 `json:"hostname"`
+	Component   string                 `json:"component,omitempty"`
 	Message     string                 `json:"message"`
 	Fields      map[string]interface{} `json:"fields,omitempty"`
 	RequestID   string                 `json:"request_id,omitempty"`
@@ -76,28 +108,102 @@ func NewStructuredLogger(service, version string, level LogLevel) *StructuredLog
 	hostname, _ := os.Hostname()
 
 	return &StructuredLogger{
-		level:    level,
-		output:   os.Stdout,
-		service:  service,
-		version:  version,
-		hostname: hostname,
+		level:           level,
+		output:          os.Stdout,
+		service:         service,
+		version:         version,
+		hostname:        hostname,
+		componentLevels: make(map[string]LogLevel),
+	}
+}
+
+// Named returns a sub-logger that tags entries with a component field
+// Its level defaults to the root level until overridden via SetComponentLevel
+func (l *StructuredLogger) Named(component string) *StructuredLogger {
+	return &StructuredLogger{
+		service:   l.service,
+		version:   l.version,
+		hostname:  l.hostname,
+		component: component,
+		root:      l.rootLogger(),
 	}
 }
 
+// rootLogger returns the logger that owns output and level state
+func (l *StructuredLogger) rootLogger() *StructuredLogger {
+	if l.root != nil {
+		return l.root
+	}
+	return l
+}
+
+// Service returns the service name written on every entry
+func (l *StructuredLogger) Service() string {
+	return l.service
+}
+
 // SetOutput sets the output destination for logs
 func (l *StructuredLogger) SetOutput(w io.Writer) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.output = w
+	root := l.rootLogger()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	root.output = w
 }
 
 // SetLevel sets the minimum log level
+// On a named sub-logger this sets the level for that component only
 func (l *StructuredLogger) SetLevel(level LogLevel) {
+	if l.component != "" {
+		l.rootLogger().SetComponentLevel(l.component, level)
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.level = level
 }
 
+// SetComponentLevel sets the minimum log level for a named component
+func (l *StructuredLogger) SetComponentLevel(component string, level LogLevel) {
+	root := l.rootLogger()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	root.componentLevels[component] = level
+}
+
+// ApplyComponentLevels sets component levels from a component -> level name map
+// Returns an error naming every level that could not be parsed
+func (l *StructuredLogger) ApplyComponentLevels(levels map[string]string) error {
+	var invalid []string
+	for component, name := range levels {
+		level, err := ParseLogLevel(name)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s=%s", component, name))
+			continue
+		}
+		l.SetComponentLevel(component, level)
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid component log levels: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// effectiveLevel returns the minimum level for this logger's component
+func (l *StructuredLogger) effectiveLevel() LogLevel {
+	root := l.rootLogger()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	if l.component != "" {
+		if level, ok := root.componentLevels[l.component]; ok {
+			return level
+		}
+	}
+	return root.level
+}
+
 // Debug logs a debug message with structured fields
 func (l *StructuredLogger) Debug(ctx context.Context, message string, fields Fields) {
 	l.log(ctx, DebugLevel, message, fields, nil)
@@ -119,15 +225,17 @@ func (l *StructuredLogger) Error(ctx context.Context, message string, fields Fie
 }
 
 // Fatal logs a fatal message and exits the program
+// Buffered async entries are written before exiting
 func (l *StructuredLogger) Fatal(ctx context.Context, message string, fields Fields, err error) {
 	l.log(ctx, FatalLevel, message, fields, err)
+	l.Close()
 	os.Exit(1)
 }
 
 // log is the internal logging implementation
 func (l *StructuredLogger) log(ctx context.Context, level LogLevel, message string, fields Fields, err error) {
 	// Check log level
-	if level < l.level {
+	if level < l.effectiveLevel() {
 		return
 	}
 
@@ -138,16 +246,17 @@ func (l *StructuredLogger) log(ctx context.Context, level LogLevel, message stri
 		Service:   l.service,
 		Version:   l.version,
 		Hostname:  l.hostname,
+		Component: l.component,
 		Message:   message,
 		Fields:    fields,
 	}
 
 	// Extract context values
 	if ctx != nil {
-		if requestID, ok := ctx.Value("request_id").(string); ok {
+		if requestID, ok := ctxkeys.RequestIDFrom(ctx); ok {
 			entry.RequestID = requestID
 		}
-		if tenantID, ok := ctx.Value("tenant_id").(string); ok {
+		if tenantID, ok := ctxkeys.TenantIDFrom(ctx); ok {
 			entry.TenantID = tenantID
 		}
 	}
@@ -186,7 +295,15 @@ func (l *StructuredLogger) log(ctx context.Context, level LogLevel, message stri
 		return
 	}
 
-	// Write log entry
+	// Write log entry, through the async writer when enabled
+	if l.enqueue(level, data) {
+		return
+	}
+	l.rootLogger().write(data)
+}
+
+// write outputs one encoded entry followed by a newline
+func (l *StructuredLogger) write(data []byte) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"weather-platform/pkg/ctxkeys"
+)
+
+// TestNamedLogger_ComponentLevels tests per-component level overrides
+func TestNamedLogger_ComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStructuredLogger("test", "1.0.0", InfoLevel)
+	logger.SetOutput(&buf)
+
+	if err := logger.ApplyComponentLevels(map[string]string{
+		"database":  "warn",
+		"ingestion": "debug",
+	}); err != nil {
+		t.Fatalf("ApplyComponentLevels() error = %v", err)
+	}
+
+	ctx := context.Background()
+	logger.Named("database").Info(ctx, "db info", Fields{})
+	logger.Named("ingestion").Debug(ctx, "ingest debug", Fields{})
+	logger.Named("api").Debug(ctx, "api debug", Fields{})
+	logger.Named("api").Info(ctx, "api info", Fields{})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want 2: %q", len(lines), buf.String())
+	}
+
+	want := []struct{ component, message string }{
+		{"ingestion", "ingest debug"},
+		{"api", "api info"},
+	}
+	for i, line := range lines {
+		var entry LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to unmarshal log line: %v", err)
+		}
+		if entry.Component != want[i].component || entry.Message != want[i].message {
+			t.Errorf("line %d = (%s, %s), want (%s, %s)", i, entry.Component, entry.Message, want[i].component, want[i].message)
+		}
+	}
+}
+
+// TestApplyComponentLevels_Invalid tests that invalid levels are reported
+func TestApplyComponentLevels_Invalid(t *testing.T) {
+	logger := NewStructuredLogger("test", "1.0.0", InfoLevel)
+
+	err := logger.ApplyComponentLevels(map[string]string{"database": "loud"})
+	if err == nil {
+		t.Fatal("ApplyComponentLevels() expected error for invalid level")
+	}
+	if !strings.Contains(err.Error(), "database=loud") {
+		t.Errorf("error = %v, want it to name database=loud", err)
+	}
+}
+
+// TestLogContextValues tests that request and tenant IDs come from typed context keys
+func TestLogContextValues(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStructuredLogger("test", "1.0.0", InfoLevel)
+	logger.SetOutput(&buf)
+
+	ctx := ctxkeys.WithTenantID(ctxkeys.WithRequestID(context.Background(), "req-1"), "tenant-1")
+	logger.Info(ctx, "with ids", Fields{})
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to unmarshal log line: %v", err)
+	}
+	if entry.RequestID != "req-1" || entry.TenantID != "tenant-1" {
+		t.Errorf("entry ids = (%q, %q), want (req-1, tenant-1)", entry.RequestID, entry.TenantID)
+	}
+}
+
+// blockingWriter holds every Write until release is closed
+type blockingWriter struct {
+	release chan struct{}
+	buf     bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.buf.Write(p)
+}
+
+// TestAsyncLogger tests buffered output, dropping of Debug entries and Close draining
+func TestAsyncLogger(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	logger := NewStructuredLogger("test", "1.0.0", DebugLevel)
+	logger.SetOutput(out)
+	logger.EnableAsync(1)
+
+	ctx := context.Background()
+	// The writer holds at most one entry and the buffer one more, so with
+	// output blocked at least one of these is dropped rather than waited on
+	for i := 0; i < 3; i++ {
+		logger.Debug(ctx, "debug", Fields{})
+	}
+	if logger.Dropped() == 0 {
+		t.Fatal("Dropped() = 0, want Debug entries dropped while the buffer is full")
+	}
+
+	close(out.release)
+	logger.Named("api").Error(ctx, "kept", Fields{}, nil)
+	logger.Flush()
+	if !strings.Contains(out.buf.String(), `"message":"kept"`) {
+		t.Fatalf("Flush() returned before the Error entry was written: %q", out.buf.String())
+	}
+
+	logger.Close()
+	logger.Close()
+	if !strings.Contains(out.buf.String(), "[LOGGER_DROPPED]") {
+		t.Errorf("Close() did not report dropped entries: %q", out.buf.String())
+	}
+
+	// After Close entries are written synchronously
+	logger.Info(ctx, "after close", Fields{})
+	if !strings.Contains(out.buf.String(), `"message":"after close"`) {
+		t.Errorf("entry logged after Close() was not written: %q", out.buf.String())
+	}
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"net"
+	"net/http"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,17 +15,23 @@ type Collector struct {
 	APIRequestsTotal    *prometheus.CounterVec
 	APIRequestDuration  *prometheus.HistogramVec
 	APIErrorsTotal      *prometheus.CounterVec
+	APIRequestsInFlight *prometheus.GaugeVec
 
 	// Ingestion Metrics
 	IngestionRecordsTotal    prometheus.Counter
 	IngestionDuration        prometheus.Histogram
 	IngestionErrorsTotal     *prometheus.CounterVec
 	IngestionBatchSize       prometheus.Histogram
+	IngestionQueueDepth      prometheus.Gauge
+	IngestionRecordsByState  *prometheus.CounterVec
 
 	// Database Metrics
 	DBQueryDuration     *prometheus.HistogramVec
 	DBConnectionPool    *prometheus.GaugeVec
 	DBErrorsTotal       *prometheus.CounterVec
+	DBSlowQueriesTotal  *prometheus.CounterVec
+	DBActiveHost        *prometheus.GaugeVec
+	DBFailoversTotal    prometheus.Counter
 
 	// Statistics Metrics
 	StatsCacheHitRatio  prometheus.Gauge
@@ -32,6 +40,7 @@ type Collector struct {
 	// System Metrics
 	ProcessingTimeMS    *prometheus.HistogramVec
 	ActiveConnections   prometheus.Gauge
+	ConnectionsAcceptedTotal prometheus.Counter
 }
 
 // NewCollector creates a new metrics collector
@@ -100,6 +109,23 @@ func NewCollector(namespace string) *Collector {
 			},
 		),
 
+		IngestionQueueDepth: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "ingestion_queue_depth",
+				Help:      "Number of parsed records waiting to be written to the database",
+			},
+		),
+
+		IngestionRecordsByState: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "ingestion_records_by_state_total",
+				Help:      "Total number of weather records ingested by station state",
+			},
+			[]string{"state"},
+		),
+
 		DBQueryDuration: promauto.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
@@ -128,6 +154,32 @@ func NewCollector(namespace string) *Collector {
 			[]string{"error_type"},
 		),
 
+		DBSlowQueriesTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "db_slow_queries_total",
+				Help:      "Total number of queries exceeding the slow query threshold by query type",
+			},
+			[]string{"query_type"},
+		),
+
+		DBActiveHost: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "db_active_host",
+				Help:      "Database host currently serving queries (1) among configured candidates (0)",
+			},
+			[]string{"host"},
+		),
+
+		DBFailoversTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "db_failovers_total",
+				Help:      "Total number of switches to another database host after connection loss",
+			},
+		),
+
 		StatsCacheHitRatio: promauto.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
@@ -155,6 +207,15 @@ func NewCollector(namespace string) *Collector {
 			[]string{"operation"},
 		),
 
+		APIRequestsInFlight: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "api_requests_in_flight",
+				Help:      "Number of API requests currently being served by endpoint",
+			},
+			[]string{"endpoint"},
+		),
+
 		ActiveConnections: promauto.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
@@ -162,6 +223,14 @@ func NewCollector(namespace string) *Collector {
 				Help:      "Number of active client connections",
 			},
 		),
+
+		ConnectionsAcceptedTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "connections_accepted_total",
+				Help:      "Total number of accepted client connections",
+			},
+		),
 	}
 }
 
@@ -203,6 +272,11 @@ func (c *Collector) RecordIngestionError(errorType string) {
 	c.IngestionErrorsTotal.WithLabelValues(errorType).Inc()
 }
 
+// ObserveProcessingTime records an operation's duration in milliseconds
+func (c *Collector) ObserveProcessingTime(operation string, duration time.Duration) {
+	c.ProcessingTimeMS.WithLabelValues(operation).Observe(float64(duration) / float64(time.Millisecond))
+}
+
 // RecordDBError increments database error counter
 func (c *Collector) RecordDBError(errorType string) {
 	c.DBErrorsTotal.WithLabelValues(errorType).Inc()
@@ -214,3 +288,27 @@ func (c *Collector) UpdateDBConnectionPool(inUse, idle, total int) {
 	c.DBConnectionPool.WithLabelValues("idle").Set(float64(idle))
 	c.DBConnectionPool.WithLabelValues("total").Set(float64(total))
 }
+
+// SetDBActiveHost marks host as the active database host and clears the others
+func (c *Collector) SetDBActiveHost(active string, hosts []string) {
+	for _, host := range hosts {
+		value := 0.0
+		if host == active {
+			value = 1
+		}
+		c.DBActiveHost.WithLabelValues(host).Set(value)
+	}
+}
+
+// TrackConnState updates connection metrics from http.Server connection state changes
+// Intended for use as http.Server.ConnState
+func (c *Collector) TrackConnState(conn net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		c.ConnectionsAcceptedTotal.Inc()
+		c.ActiveConnections.Inc()
+	case http.StateHijacked, http.StateClosed:
+		// Hijacked connections are no longer managed by the server and never reach StateClosed
+		c.ActiveConnections.Dec()
+	}
+}